@@ -10,7 +10,30 @@ type Snowflake struct {
 	PrivateKeyPath string `mapstructure:"private-key-path"`
 	UserIdentifier string `mapstructure:"user-identifier"`
 	SyncSecrets bool `mapstructure:"sync-secrets"`
+	SyncTables bool `mapstructure:"sync-tables"`
+	SyncIntegrations bool `mapstructure:"sync-integrations"`
+	SyncLicenses bool `mapstructure:"sync-licenses"`
+	SyncNetworkPolicies bool `mapstructure:"sync-network-policies"`
+	SyncPolicies bool `mapstructure:"sync-policies"`
+	SyncDataPolicies bool `mapstructure:"sync-data-policies"`
+	SyncTags bool `mapstructure:"sync-tags"`
+	SyncShares bool `mapstructure:"sync-shares"`
+	SyncReaderAccounts bool `mapstructure:"sync-reader-accounts"`
+	SyncApplications bool `mapstructure:"sync-applications"`
+	SyncOrganizationUsers bool `mapstructure:"sync-organization-users"`
+	SyncMfaMethods bool `mapstructure:"sync-mfa-methods"`
+	SyncUserNetworkPolicies bool `mapstructure:"sync-user-network-policies"`
+	IncludedDatabases []string `mapstructure:"included-databases"`
 	ExcludedDatabases []string `mapstructure:"excluded-databases"`
+	IncludedSchemas []string `mapstructure:"included-schemas"`
+	ExcludedSchemas []string `mapstructure:"excluded-schemas"`
+	IncludedTables []string `mapstructure:"included-tables"`
+	ExcludedTables []string `mapstructure:"excluded-tables"`
+	AccessHistoryLookbackDays int `mapstructure:"access-history-lookback-days"`
+	IncrementalSync bool `mapstructure:"incremental-sync"`
+	FullSyncIntervalHours int `mapstructure:"full-sync-interval-hours"`
+	OrganizationSync bool `mapstructure:"organization-sync"`
+	OrganizationAccountCredentials []string `mapstructure:"organization-account-credentials"`
 }
 
 func (c *Snowflake) findFieldByTag(tagValue string) (any, bool) {
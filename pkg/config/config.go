@@ -45,11 +45,144 @@ var (
 		field.WithDescription("Enable synchronization of Snowflake secrets. When enabled, the connector will sync secrets from your Snowflake account."),
 		field.WithDefaultValue(false),
 	)
+	SyncTables = field.BoolField(
+		"sync-tables",
+		field.WithDisplayName("Sync Tables"),
+		field.WithDescription("Sync tables and views and their grants. Turn off to sync databases without their tables; table sync usually dominates sync time."),
+		field.WithDefaultValue(true),
+	)
+	SyncIntegrations = field.BoolField(
+		"sync-integrations",
+		field.WithDisplayName("Sync Integrations"),
+		field.WithDescription("Sync security, API, storage and other integrations."),
+		field.WithDefaultValue(true),
+	)
+	SyncLicenses = field.BoolField(
+		"sync-licenses",
+		field.WithDisplayName("Sync Licenses"),
+		field.WithDescription("Sync the account's Snowflake edition as a license. Requires GLOBALORGADMIN on the organization account."),
+		field.WithDefaultValue(true),
+	)
+	SyncNetworkPolicies = field.BoolField(
+		"sync-network-policies",
+		field.WithDisplayName("Sync Network Policies"),
+		field.WithDescription("Sync network policies and network rules."),
+		field.WithDefaultValue(true),
+	)
+	SyncPolicies = field.BoolField(
+		"sync-policies",
+		field.WithDisplayName("Sync Policies"),
+		field.WithDescription("Sync authentication, password and session policies."),
+		field.WithDefaultValue(true),
+	)
+	SyncDataPolicies = field.BoolField(
+		"sync-data-policies",
+		field.WithDisplayName("Sync Data Policies"),
+		field.WithDescription("Sync masking and row access policies."),
+		field.WithDefaultValue(true),
+	)
+	SyncTags = field.BoolField(
+		"sync-tags",
+		field.WithDisplayName("Sync Tags"),
+		field.WithDescription("Sync tags."),
+		field.WithDefaultValue(true),
+	)
+	SyncShares = field.BoolField(
+		"sync-shares",
+		field.WithDisplayName("Sync Shares"),
+		field.WithDescription("Sync outbound shares."),
+		field.WithDefaultValue(true),
+	)
+	SyncReaderAccounts = field.BoolField(
+		"sync-reader-accounts",
+		field.WithDisplayName("Sync Reader Accounts"),
+		field.WithDescription("Sync reader accounts."),
+		field.WithDefaultValue(true),
+	)
+	SyncApplications = field.BoolField(
+		"sync-applications",
+		field.WithDisplayName("Sync Applications"),
+		field.WithDescription("Sync Native Apps and their application roles."),
+		field.WithDefaultValue(true),
+	)
+	SyncOrganizationUsers = field.BoolField(
+		"sync-organization-users",
+		field.WithDisplayName("Sync Organization Users"),
+		field.WithDescription("Sync organization users and organization user groups, and link account users to the organization users they were imported from. Requires GLOBALORGADMIN on the organization account."),
+		field.WithDefaultValue(true),
+	)
+	SyncMfaMethods = field.BoolField(
+		"sync-mfa-methods",
+		field.WithDisplayName("Sync MFA Methods"),
+		field.WithDescription("Add the types of the MFA methods each user has enrolled to the user's profile. Runs SHOW MFA METHODS once per user, so it is off by default."),
+		field.WithDefaultValue(false),
+	)
+	SyncUserNetworkPolicies = field.BoolField(
+		"sync-user-network-policies",
+		field.WithDisplayName("Sync User Network Policies"),
+		field.WithDescription("Add each user's own network policy, and the network policy Snowflake enforces for it, to the user's profile. Runs DESCRIBE USER once per user, so it is off by default."),
+		field.WithDefaultValue(false),
+	)
+	IncludedDatabases = field.StringSliceField(
+		"included-databases",
+		field.WithDisplayName("Included Databases"),
+		field.WithDescription("Database name patterns to sync (case-insensitive; * or % matches any characters, ? matches one). Can be specified multiple times. When set, only matching databases and their tables are synced."),
+	)
 	ExcludedDatabases = field.StringSliceField(
 		"excluded-databases",
 		field.WithDisplayName("Excluded Databases"),
 		field.WithDescription("Database names to exclude from sync (case-insensitive). Can be specified multiple times. When set, matching databases and all their tables are skipped entirely."),
 	)
+	IncludedSchemas = field.StringSliceField(
+		"included-schemas",
+		field.WithDisplayName("Included Schemas"),
+		field.WithDescription("Schema name patterns to sync tables from, in every database (case-insensitive; * or % matches any characters, ? matches one). Can be specified multiple times."),
+	)
+	ExcludedSchemas = field.StringSliceField(
+		"excluded-schemas",
+		field.WithDisplayName("Excluded Schemas"),
+		field.WithDescription("Schema name patterns whose tables are skipped, in every database (case-insensitive; * or % matches any characters, ? matches one). Can be specified multiple times."),
+	)
+	IncludedTables = field.StringSliceField(
+		"included-tables",
+		field.WithDisplayName("Included Tables"),
+		field.WithDescription("Table and view name patterns to sync, in every schema (case-insensitive; * or % matches any characters, ? matches one). Can be specified multiple times."),
+	)
+	ExcludedTables = field.StringSliceField(
+		"excluded-tables",
+		field.WithDisplayName("Excluded Tables"),
+		field.WithDescription("Table and view name patterns to skip, in every schema (case-insensitive; * or % matches any characters, ? matches one). Can be specified multiple times."),
+	)
+	AccessHistoryLookbackDays = field.IntField(
+		"access-history-lookback-days",
+		field.WithDisplayName("Access History Lookback Days"),
+		field.WithDescription("How many days of SNOWFLAKE.ACCOUNT_USAGE.ACCESS_HISTORY the table usage event feed reads when it starts without a cursor (at most 365)."),
+		field.WithDefaultValue(90),
+	)
+	IncrementalSync = field.BoolField(
+		"incremental-sync",
+		field.WithDisplayName("Incremental Sync"),
+		field.WithDescription("Keep account role and table grants between syncs that reuse the same c1z file, and re-read only those SNOWFLAKE.ACCOUNT_USAGE shows changed since the last sync. Resources are still listed in full. Requires IMPORTED PRIVILEGES on the SNOWFLAKE database."),
+		field.WithDefaultValue(false),
+	)
+	FullSyncIntervalHours = field.IntField(
+		"full-sync-interval-hours",
+		field.WithDisplayName("Full Sync Interval Hours"),
+		field.WithDescription("With incremental sync, how many hours after the last full sync the next sync re-reads every grant."),
+		field.WithDefaultValue(24),
+	)
+	OrganizationSync = field.BoolField(
+		"organization-sync",
+		field.WithDisplayName("Organization Sync"),
+		field.WithDescription("Sync every account in the Snowflake organization, each under an account resource. The configured account lists them with SHOW ORGANIZATION ACCOUNTS, which needs GLOBALORGADMIN."),
+		field.WithDefaultValue(false),
+	)
+	OrganizationAccountCredentials = field.StringSliceField(
+		"organization-account-credentials",
+		field.WithDisplayName("Organization Account Credentials"),
+		field.WithDescription("With organization sync, the key pair to authenticate to an account with, as ACCOUNT=USER:PRIVATE_KEY_PATH, where ACCOUNT is the account name or locator. Can be specified multiple times. Other accounts use user-identifier and the configured private key."),
+		field.WithExportTarget(field.ExportTargetCLIOnly),
+	)
 
 	fieldRelationships = []field.SchemaFieldRelationship{
 		field.FieldsMutuallyExclusive(
@@ -69,7 +202,30 @@ var (
 		PrivateKeyPathField,
 		UserIdentifierField,
 		SyncSecrets,
+		SyncTables,
+		SyncIntegrations,
+		SyncLicenses,
+		SyncNetworkPolicies,
+		SyncPolicies,
+		SyncDataPolicies,
+		SyncTags,
+		SyncShares,
+		SyncReaderAccounts,
+		SyncApplications,
+		SyncOrganizationUsers,
+		SyncMfaMethods,
+		SyncUserNetworkPolicies,
+		IncludedDatabases,
 		ExcludedDatabases,
+		IncludedSchemas,
+		ExcludedSchemas,
+		IncludedTables,
+		ExcludedTables,
+		AccessHistoryLookbackDays,
+		IncrementalSync,
+		FullSyncIntervalHours,
+		OrganizationSync,
+		OrganizationAccountCredentials,
 	}
 
 	Configuration = field.NewConfiguration(
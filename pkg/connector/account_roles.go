@@ -17,6 +17,7 @@ import (
 type accountRoleBuilder struct {
 	resourceType *v2.ResourceType
 	client       *snowflake.Client
+	incremental  *incrementalSync
 }
 
 func (o *accountRoleBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -73,6 +74,27 @@ func (o *accountRoleBuilder) List(ctx context.Context, parentResourceID *v2.Reso
 	return resources, &rs.SyncOpResults{NextPageToken: nextCursor}, nil
 }
 
+// Get returns the account role, or nil if it does not exist. A role SHOW ROLES will not describe
+// to the connector's role is returned by name, as table grants refer to it.
+func (o *accountRoleBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	role, _, err := o.client.GetAccountRole(ctx, nil, resourceId.Resource)
+	if err != nil {
+		if !snowflake.IsInsufficientPrivileges(err) {
+			return nil, nil, wrapError(err, fmt.Sprintf("failed to get account role %q", resourceId.Resource))
+		}
+		role = &snowflake.AccountRole{Name: resourceId.Resource}
+	}
+	if role == nil {
+		return nil, nil, nil
+	}
+
+	resource, err := accountRoleResource(role)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to create account role resource")
+	}
+	return resource, nil, nil
+}
+
 func (o *accountRoleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
 	var rv []*v2.Entitlement
 
@@ -100,7 +122,13 @@ func (o *accountRoleBuilder) Grants(ctx context.Context, resource *v2.Resource,
 		return nil, nil, wrapError(err, "failed to get next page offset")
 	}
 
-	accountRoleGrantees, nextCursor, err := o.client.ListAccountRoleGrantees(ctx, resource.DisplayName, cursor)
+	// Grantees are only cached with incremental sync on; a sync reads each role's once.
+	ss, err := o.incremental.store(ctx, opts)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to prepare incremental sync")
+	}
+
+	accountRoleGrantees, nextCursor, err := o.client.ListAccountRoleGrantees(ctx, ss, resource.DisplayName, cursor)
 	if err != nil {
 		return nil, nil, wrapError(err, "failed to list account role grantees")
 	}
@@ -196,9 +224,10 @@ func (o *accountRoleBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annot
 	return nil, nil
 }
 
-func newAccountRoleBuilder(client *snowflake.Client) *accountRoleBuilder {
+func newAccountRoleBuilder(client *snowflake.Client, incremental *incrementalSync) *accountRoleBuilder {
 	return &accountRoleBuilder{
 		resourceType: accountRoleResourceType,
 		client:       client,
+		incremental:  incremental,
 	}
 }
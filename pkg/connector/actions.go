@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	config "github.com/conductorone/baton-sdk/pb/c1/config/v1"
@@ -10,20 +11,75 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/actions"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/crypto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
 )
 
 const (
-	actionDisableUser = "disable_user"
-	actionEnableUser  = "enable_user"
+	actionDisableUser             = "disable_user"
+	actionEnableUser              = "enable_user"
+	actionSetNetworkPolicy        = "set_network_policy"
+	actionSetAuthenticationPolicy = "set_authentication_policy"
+	actionSetPasswordPolicy       = "set_password_policy"
+	actionUnsetPolicy             = "unset_policy"
+	actionUnsetRsaPublicKey       = "unset_rsa_public_key"
+	actionUpdateUser              = "update_user"
+	actionResetPassword           = "reset_password"
+	actionRequirePasswordChange   = "require_password_change"
+	actionUnlockUser              = "unlock_user"
+	actionTerminateUserSessions   = "terminate_user_sessions"
+	actionResetMfa                = "reset_mfa"
+	actionBypassMfa               = "bypass_mfa"
+
+	argUserIDKey         = "user_id"
+	argUserIDDisplay     = "User Resource ID"
+	argPolicyKey         = "policy"
+	argPolicyTypeKey     = "policy_type"
+	argPolicyTypeDisplay = "Policy Type"
+	argRsaKeyKey         = "key"
+	argPasswordLengthKey = "password_length"
+	argMinutesKey        = "minutes"
+	retSuccessKey        = "success"
+	retPasswordKey       = "password"
+	retRemovedMethodsKey = "removed_methods"
 
-	argUserIDKey     = "user_id"
-	argUserIDDisplay = "User Resource ID"
-	retSuccessKey    = "success"
+	// defaultPasswordLength is the length of passwords reset_password generates when
+	// password_length is not given.
+	defaultPasswordLength = 16
 )
 
+// unsetPolicyTypes maps the unset_policy policy_type argument to the policy kind to detach.
+var unsetPolicyTypes = map[string]string{
+	"network":        snowflake.PolicyKindNetwork,
+	"authentication": snowflake.PolicyKindAuthentication,
+	"password":       snowflake.PolicyKindPassword,
+	"session":        snowflake.PolicyKindSession,
+}
+
+// unsetRsaPublicKeyKeys maps the unset_rsa_public_key key argument to the user property to clear.
+var unsetRsaPublicKeyKeys = map[string]string{
+	"rsa_public_key":   snowflake.RsaPublicKeyProperty,
+	"rsa_public_key_2": snowflake.RsaPublicKey2Property,
+}
+
+// updateUserFields maps the update_user arguments, which are the mutable accountCreationFields,
+// to the user properties they set.
+var updateUserFields = map[string]string{
+	"display_name":            "DISPLAY_NAME",
+	"first_name":              "FIRST_NAME",
+	"last_name":               "LAST_NAME",
+	"email":                   "EMAIL",
+	profileKeyComment:         "COMMENT",
+	"default_warehouse":       "DEFAULT_WAREHOUSE",
+	"default_namespace":       "DEFAULT_NAMESPACE",
+	"default_role":            "DEFAULT_ROLE",
+	"default_secondary_roles": "DEFAULT_SECONDARY_ROLES",
+}
+
 // successReturnType is shared across all schemas - define once, reuse everywhere.
 var successReturnType = []*config.Field{
 	{Name: retSuccessKey, DisplayName: "Success", Field: &config.Field_BoolField{}},
@@ -51,8 +107,181 @@ var enableUserSchema = &v2.BatonActionSchema{
 	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT, v2.ActionType_ACTION_TYPE_ACCOUNT_ENABLE},
 }
 
+var setNetworkPolicySchema = &v2.BatonActionSchema{
+	Name:        actionSetNetworkPolicy,
+	DisplayName: "Set Network Policy",
+	Description: "Attaches a network policy to a Snowflake user, replacing any network policy already set on the user.",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+		{Name: argPolicyKey, DisplayName: "Network Policy Name", Field: &config.Field_StringField{}, IsRequired: true},
+	},
+	ReturnTypes: successReturnType,
+	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
+var setAuthenticationPolicySchema = &v2.BatonActionSchema{
+	Name:        actionSetAuthenticationPolicy,
+	DisplayName: "Set Authentication Policy",
+	Description: "Attaches an authentication policy (DATABASE.SCHEMA.NAME) to a Snowflake user, replacing any authentication policy already set on the user.",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+		{Name: argPolicyKey, DisplayName: "Authentication Policy Resource ID", Field: &config.Field_StringField{}, IsRequired: true},
+	},
+	ReturnTypes: successReturnType,
+	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
+var setPasswordPolicySchema = &v2.BatonActionSchema{
+	Name:        actionSetPasswordPolicy,
+	DisplayName: "Set Password Policy",
+	Description: "Attaches a password policy (DATABASE.SCHEMA.NAME) to a Snowflake user, replacing any password policy already set on the user.",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+		{Name: argPolicyKey, DisplayName: "Password Policy Resource ID", Field: &config.Field_StringField{}, IsRequired: true},
+	},
+	ReturnTypes: successReturnType,
+	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
+var unsetPolicySchema = &v2.BatonActionSchema{
+	Name:        actionUnsetPolicy,
+	DisplayName: "Unset Policy",
+	Description: "Detaches a user-level policy from a Snowflake user. policy_type is one of network, authentication, password or session.",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+		{Name: argPolicyTypeKey, DisplayName: argPolicyTypeDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+	},
+	ReturnTypes: successReturnType,
+	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
+var unsetRsaPublicKeySchema = &v2.BatonActionSchema{
+	Name:        actionUnsetRsaPublicKey,
+	DisplayName: "Unset RSA Public Key",
+	Description: "Clears one of a Snowflake user's key-pair slots, such as the old key after a rotation. key is rsa_public_key or rsa_public_key_2.",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+		{Name: argRsaKeyKey, DisplayName: "Key Slot", Field: &config.Field_StringField{}, IsRequired: true},
+	},
+	ReturnTypes: successReturnType,
+	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
+var updateUserSchema = newUpdateUserSchema()
+
+// newUpdateUserSchema builds the update_user arguments from accountCreationFields, so a field is
+// labelled the same whether it is set at creation or updated later.
+func newUpdateUserSchema() *v2.BatonActionSchema {
+	keys := make([]string, 0, len(updateUserFields))
+	for key := range updateUserFields {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return accountCreationFields[keys[i]].GetOrder() < accountCreationFields[keys[j]].GetOrder()
+	})
+
+	arguments := []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+	}
+	for _, key := range keys {
+		field := accountCreationFields[key]
+		arguments = append(arguments, &config.Field{
+			Name:        key,
+			DisplayName: field.GetDisplayName(),
+			Description: field.GetDescription(),
+			Placeholder: field.GetPlaceholder(),
+			Field:       &config.Field_StringField{},
+		})
+	}
+
+	return &v2.BatonActionSchema{
+		Name:        actionUpdateUser,
+		DisplayName: "Update User",
+		Description: "Sets the given profile attributes of a Snowflake user in a single ALTER USER. Omitted or blank fields are left unchanged.",
+		Arguments:   arguments,
+		ReturnTypes: successReturnType,
+		ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT, v2.ActionType_ACTION_TYPE_ACCOUNT_UPDATE_PROFILE},
+	}
+}
+
+var resetPasswordSchema = &v2.BatonActionSchema{
+	Name:        actionResetPassword,
+	DisplayName: "Reset Password",
+	Description: "Sets a new random password on a Snowflake user and requires it to be changed at next login. The password is returned as a secret. Refused for SERVICE users, which cannot have passwords.",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+		{Name: argPasswordLengthKey, DisplayName: "Password Length", Description: "Length of the generated password (default 16, minimum 8).", Field: &config.Field_IntField{}},
+	},
+	ReturnTypes: []*config.Field{
+		{Name: retSuccessKey, DisplayName: "Success", Field: &config.Field_BoolField{}},
+		{Name: retPasswordKey, DisplayName: "Password", Field: &config.Field_StringField{}, IsSecret: true},
+	},
+	ActionType: []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
+var requirePasswordChangeSchema = &v2.BatonActionSchema{
+	Name:        actionRequirePasswordChange,
+	DisplayName: "Require Password Change",
+	Description: "Requires a Snowflake user to choose a new password at next login (sets MUST_CHANGE_PASSWORD = TRUE).",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+	},
+	ReturnTypes: successReturnType,
+	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
+var unlockUserSchema = &v2.BatonActionSchema{
+	Name:        actionUnlockUser,
+	DisplayName: "Unlock User",
+	Description: "Clears a Snowflake user's lockout after failed login attempts (sets MINS_TO_UNLOCK = 0). Does not enable a disabled user.",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+	},
+	ReturnTypes: successReturnType,
+	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
+var terminateUserSessionsSchema = &v2.BatonActionSchema{
+	Name:        actionTerminateUserSessions,
+	DisplayName: "Terminate User Sessions",
+	Description: "Disables a Snowflake user and aborts all of their running and queued queries. Reversible via enable_user.",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+	},
+	ReturnTypes: successReturnType,
+	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT, v2.ActionType_ACTION_TYPE_ACCOUNT_DISABLE},
+}
+
+var resetMfaSchema = &v2.BatonActionSchema{
+	Name:        actionResetMfa,
+	DisplayName: "Reset MFA",
+	Description: "Removes every MFA method (passkey, TOTP, Duo) a Snowflake user has enrolled, so they can enroll again at next login.",
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+	},
+	ReturnTypes: []*config.Field{
+		{Name: retSuccessKey, DisplayName: "Success", Field: &config.Field_BoolField{}},
+		{Name: retRemovedMethodsKey, DisplayName: "Removed Methods", Field: &config.Field_IntField{}},
+	},
+	ActionType: []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
+var bypassMfaSchema = &v2.BatonActionSchema{
+	Name:        actionBypassMfa,
+	DisplayName: "Bypass MFA",
+	Description: fmt.Sprintf("Lets a Snowflake user log in without MFA for the given number of minutes, at most %d (sets MINS_TO_BYPASS_MFA).", snowflake.MaxMinsToBypassMfa),
+	Arguments: []*config.Field{
+		{Name: argUserIDKey, DisplayName: argUserIDDisplay, Field: &config.Field_StringField{}, IsRequired: true},
+		{Name: argMinutesKey, DisplayName: "Minutes", Field: &config.Field_IntField{}, IsRequired: true},
+	},
+	ReturnTypes: successReturnType,
+	ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT},
+}
+
 var _ connectorbuilder.GlobalActionProvider = (*Connector)(nil)
 
+// GlobalActions registers the user actions. Each runs in the account of the user it is given; see
+// userClient.
 func (c *Connector) GlobalActions(ctx context.Context, registry actions.ActionRegistry) error {
 	if err := registry.Register(ctx, disableUserSchema, c.disableUserHandler); err != nil {
 		return fmt.Errorf("baton-snowflake: register disable_user: %w", err)
@@ -60,9 +289,63 @@ func (c *Connector) GlobalActions(ctx context.Context, registry actions.ActionRe
 	if err := registry.Register(ctx, enableUserSchema, c.enableUserHandler); err != nil {
 		return fmt.Errorf("baton-snowflake: register enable_user: %w", err)
 	}
+	if err := registry.Register(ctx, setNetworkPolicySchema, c.setNetworkPolicyHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register set_network_policy: %w", err)
+	}
+	if err := registry.Register(ctx, setAuthenticationPolicySchema, c.setAuthenticationPolicyHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register set_authentication_policy: %w", err)
+	}
+	if err := registry.Register(ctx, setPasswordPolicySchema, c.setPasswordPolicyHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register set_password_policy: %w", err)
+	}
+	if err := registry.Register(ctx, unsetPolicySchema, c.unsetPolicyHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register unset_policy: %w", err)
+	}
+	if err := registry.Register(ctx, unsetRsaPublicKeySchema, c.unsetRsaPublicKeyHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register unset_rsa_public_key: %w", err)
+	}
+	if err := registry.Register(ctx, updateUserSchema, c.updateUserHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register update_user: %w", err)
+	}
+	if err := registry.Register(ctx, resetPasswordSchema, c.resetPasswordHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register reset_password: %w", err)
+	}
+	if err := registry.Register(ctx, requirePasswordChangeSchema, c.requirePasswordChangeHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register require_password_change: %w", err)
+	}
+	if err := registry.Register(ctx, unlockUserSchema, c.unlockUserHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register unlock_user: %w", err)
+	}
+	if err := registry.Register(ctx, terminateUserSessionsSchema, c.terminateUserSessionsHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register terminate_user_sessions: %w", err)
+	}
+	if err := registry.Register(ctx, resetMfaSchema, c.resetMfaHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register reset_mfa: %w", err)
+	}
+	if err := registry.Register(ctx, bypassMfaSchema, c.bypassMfaHandler); err != nil {
+		return fmt.Errorf("baton-snowflake: register bypass_mfa: %w", err)
+	}
 	return nil
 }
 
+// userClient returns the client for the account the user with the id is in, and the user's name
+// there. Outside organization sync that is the configured account and the id itself; in it, user
+// ids are scoped to their account, LOCATOR/NAME, as the users are listed.
+func (c *Connector) userClient(ctx context.Context, userID string) (*snowflake.Client, string, error) {
+	if c.organization == nil {
+		return c.Client, userID, nil
+	}
+	locator, user, err := unscopeResourceID(&v2.ResourceId{ResourceType: userResourceType.Id, Resource: userID})
+	if err != nil {
+		return nil, "", err
+	}
+	client, err := c.organization.scopedClient(ctx, locator)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, user.Resource, nil
+}
+
 func successStruct() *structpb.Struct {
 	return &structpb.Struct{
 		Fields: map[string]*structpb.Value{
@@ -75,19 +358,16 @@ func (c *Connector) disableUserHandler(
 	ctx context.Context,
 	args *structpb.Struct,
 ) (*structpb.Struct, annotations.Annotations, error) {
-	if args == nil {
-		return nil, nil, status.Error(codes.InvalidArgument, "baton-snowflake: missing arguments")
-	}
-	userID, err := actions.RequireStringArg(args, argUserIDKey)
+	userID, err := userArg(args)
 	if err != nil {
-		return nil, nil, status.Errorf(codes.InvalidArgument, "baton-snowflake: user_id: %v", err)
+		return nil, nil, err
 	}
-	userID = strings.TrimSpace(userID)
-	if userID == "" {
-		return nil, nil, status.Error(codes.InvalidArgument, "baton-snowflake: user_id must not be empty")
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := c.Client.SetUserDisabled(ctx, userID, true); err != nil {
+	if err := client.SetUserDisabled(ctx, userName, true); err != nil {
 		return nil, nil, fmt.Errorf("baton-snowflake: disable user %s: %w", userID, err)
 	}
 	return successStruct(), nil, nil
@@ -97,20 +377,372 @@ func (c *Connector) enableUserHandler(
 	ctx context.Context,
 	args *structpb.Struct,
 ) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := client.SetUserDisabled(ctx, userName, false); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: enable user %s: %w", userID, err)
+	}
+	return successStruct(), nil, nil
+}
+
+// userArg reads the user_id argument every action takes. It rejects a missing argument struct
+// and a blank user_id, so handlers can read their other arguments from args afterwards.
+func userArg(args *structpb.Struct) (string, error) {
 	if args == nil {
-		return nil, nil, status.Error(codes.InvalidArgument, "baton-snowflake: missing arguments")
+		return "", status.Error(codes.InvalidArgument, "baton-snowflake: missing arguments")
 	}
-	userID, err := actions.RequireStringArg(args, argUserIDKey)
+	return requireTrimmedStringArg(args, argUserIDKey)
+}
+
+// requireTrimmedStringArg reads a required string argument and rejects it when it is blank
+// after trimming.
+func requireTrimmedStringArg(args *structpb.Struct, key string) (string, error) {
+	value, err := actions.RequireStringArg(args, key)
 	if err != nil {
-		return nil, nil, status.Errorf(codes.InvalidArgument, "baton-snowflake: user_id: %v", err)
+		return "", status.Errorf(codes.InvalidArgument, "baton-snowflake: %s: %v", key, err)
 	}
-	userID = strings.TrimSpace(userID)
-	if userID == "" {
-		return nil, nil, status.Error(codes.InvalidArgument, "baton-snowflake: user_id must not be empty")
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", status.Errorf(codes.InvalidArgument, "baton-snowflake: %s must not be empty", key)
 	}
+	return value, nil
+}
 
-	if err := c.Client.SetUserDisabled(ctx, userID, false); err != nil {
-		return nil, nil, fmt.Errorf("baton-snowflake: enable user %s: %w", userID, err)
+func (c *Connector) setNetworkPolicyHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	policyName, err := requireTrimmedStringArg(args, argPolicyKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policy, err := client.GetNetworkPolicy(ctx, policyName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: look up network policy %s: %w", policyName, err)
+	}
+	if policy == nil {
+		return nil, nil, status.Errorf(codes.NotFound, "baton-snowflake: network policy %s not found", policyName)
+	}
+
+	if err := client.SetUserNetworkPolicy(ctx, userName, policy.Name); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: set network policy on user %s: %w", userID, err)
+	}
+	return successStruct(), nil, nil
+}
+
+func (c *Connector) setAuthenticationPolicyHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	return c.setUserPolicy(ctx, args, snowflake.PolicyKindAuthentication)
+}
+
+func (c *Connector) setPasswordPolicyHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	return c.setUserPolicy(ctx, args, snowflake.PolicyKindPassword)
+}
+
+// setUserPolicy attaches a schema-level policy, given by its DATABASE.SCHEMA.NAME resource ID,
+// after checking that the policy exists.
+func (c *Connector) setUserPolicy(
+	ctx context.Context,
+	args *structpb.Struct,
+	kind string,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	policyID, err := requireTrimmedStringArg(args, argPolicyKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parts := snowflake.SplitQualifiedName(policyID)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "baton-snowflake: policy must be DATABASE.SCHEMA.NAME, got %q", policyID)
+	}
+
+	policy, err := client.GetPolicy(ctx, kind, parts[0], parts[1], parts[2])
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: look up policy %s: %w", policyID, err)
+	}
+	if policy == nil {
+		return nil, nil, status.Errorf(codes.NotFound, "baton-snowflake: policy %s not found", policyID)
+	}
+
+	if err := client.SetUserPolicy(ctx, userName, policy); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: set policy %s on user %s: %w", policyID, userID, err)
+	}
+	return successStruct(), nil, nil
+}
+
+func (c *Connector) unsetPolicyHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	policyType, err := requireTrimmedStringArg(args, argPolicyTypeKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kind, ok := unsetPolicyTypes[strings.ToLower(policyType)]
+	if !ok {
+		return nil, nil, status.Errorf(codes.InvalidArgument,
+			"baton-snowflake: policy_type must be one of network, authentication, password or session, got %q", policyType)
+	}
+
+	if err := client.UnsetUserPolicy(ctx, userName, kind); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: unset %s policy on user %s: %w", policyType, userID, err)
+	}
+	return successStruct(), nil, nil
+}
+
+func (c *Connector) unsetRsaPublicKeyHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := requireTrimmedStringArg(args, argRsaKeyKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	property, ok := unsetRsaPublicKeyKeys[strings.ToLower(key)]
+	if !ok {
+		return nil, nil, status.Errorf(codes.InvalidArgument,
+			"baton-snowflake: key must be rsa_public_key or rsa_public_key_2, got %q", key)
+	}
+
+	if err := client.UnsetUserRsaPublicKey(ctx, userName, property); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: unset %s on user %s: %w", key, userID, err)
+	}
+	return successStruct(), nil, nil
+}
+
+func (c *Connector) updateUserHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	properties := make(map[string]string)
+	for key, property := range updateUserFields {
+		value, ok := actions.GetStringArg(args, key)
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if property == "DEFAULT_SECONDARY_ROLES" && !strings.EqualFold(value, "ALL") && !strings.EqualFold(value, "NONE") {
+			return nil, nil, status.Errorf(codes.InvalidArgument,
+				"baton-snowflake: default_secondary_roles must be ALL or NONE, got %q", value)
+		}
+		properties[property] = value
+	}
+	if len(properties) == 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "baton-snowflake: at least one field to update is required")
+	}
+
+	if err := client.UpdateUser(ctx, userName, properties); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: update user %s: %w", userID, err)
+	}
+	return successStruct(), nil, nil
+}
+
+func (c *Connector) resetPasswordHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	length := int64(defaultPasswordLength)
+	if value, ok := actions.GetIntArg(args, argPasswordLengthKey); ok && value != 0 {
+		length = value
+	}
+
+	user, _, err := client.GetUser(ctx, nil, userName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: look up user %s: %w", userID, err)
+	}
+	if strings.EqualFold(user.Type, snowflake.UserTypeService) {
+		return nil, nil, status.Errorf(codes.FailedPrecondition,
+			"baton-snowflake: user %s is a SERVICE user and cannot have a password", userID)
+	}
+
+	password, err := crypto.GenerateRandomPassword(&v2.LocalCredentialOptions_RandomPassword{Length: length})
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "baton-snowflake: generate password: %v", err)
+	}
+
+	if err := client.SetUserPassword(ctx, userName, password); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: reset password of user %s: %w", userID, err)
+	}
+
+	result := successStruct()
+	result.Fields[retPasswordKey] = structpb.NewStringValue(password)
+	return result, nil, nil
+}
+
+func (c *Connector) requirePasswordChangeHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := client.RequireUserPasswordChange(ctx, userName); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: require password change for user %s: %w", userID, err)
+	}
+	return successStruct(), nil, nil
+}
+
+func (c *Connector) unlockUserHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := client.UnlockUser(ctx, userName); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: unlock user %s: %w", userID, err)
+	}
+	return successStruct(), nil, nil
+}
+
+// terminateUserSessionsHandler disables the user before aborting their queries, so no new session
+// can start work between the two statements.
+func (c *Connector) terminateUserSessionsHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := client.SetUserDisabled(ctx, userName, true); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: disable user %s: %w", userID, err)
+	}
+	if err := client.AbortUserQueries(ctx, userName); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: abort queries of user %s: %w", userID, err)
+	}
+	return successStruct(), nil, nil
+}
+
+func (c *Connector) resetMfaHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	methods, _, err := client.ListUserMfaMethods(ctx, userName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: list mfa methods of user %s: %w", userID, err)
+	}
+	for _, method := range methods {
+		if err := client.RemoveUserMfaMethod(ctx, userName, method.Name); err != nil {
+			return nil, nil, fmt.Errorf("baton-snowflake: remove mfa method %s of user %s: %w", method.Name, userID, err)
+		}
+	}
+
+	result := successStruct()
+	result.Fields[retRemovedMethodsKey] = structpb.NewNumberValue(float64(len(methods)))
+	return result, nil, nil
+}
+
+func (c *Connector) bypassMfaHandler(
+	ctx context.Context,
+	args *structpb.Struct,
+) (*structpb.Struct, annotations.Annotations, error) {
+	userID, err := userArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, userName, err := c.userClient(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	minutes, ok := actions.GetIntArg(args, argMinutesKey)
+	if !ok || minutes < 1 || minutes > snowflake.MaxMinsToBypassMfa {
+		return nil, nil, status.Errorf(codes.InvalidArgument,
+			"baton-snowflake: minutes must be between 1 and %d", snowflake.MaxMinsToBypassMfa)
+	}
+
+	if err := client.SetUserMinsToBypassMfa(ctx, userName, int(minutes)); err != nil {
+		return nil, nil, fmt.Errorf("baton-snowflake: bypass mfa of user %s: %w", userID, err)
 	}
 	return successStruct(), nil, nil
 }
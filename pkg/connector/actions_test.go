@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -79,6 +81,31 @@ func TestEnableUserHandler(t *testing.T) {
 	assert.True(t, result.Fields["success"].GetBoolValue())
 }
 
+// TestDisableUserHandler_OrganizationUserRunsInItsAccount checks that in organization sync the
+// action runs through the client of the account the LOCATOR/NAME user id names.
+func TestDisableUserHandler_OrganizationUserRunsInItsAccount(t *testing.T) {
+	var capturedSQL string
+	server := newSetUserDisabledMockServer(t, &capturedSQL)
+	defer server.Close()
+
+	c := &Connector{organization: newOrganization(nil, nil, accountCredentials{}, nil, nil)}
+	c.organization.accounts = map[string]snowflake.OrganizationAccount{"CD67890": {AccountLocator: "CD67890"}}
+	c.organization.clients["CD67890"] = newTestConnector(t, server.URL).Client
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "CD67890/testuser"})
+	require.NoError(t, err)
+	_, _, err = c.disableUserHandler(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER USER "testuser" SET DISABLED = true;`, capturedSQL)
+
+	capturedSQL = ""
+	args, err = structpb.NewStruct(map[string]any{"user_id": "testuser"})
+	require.NoError(t, err)
+	_, _, err = c.disableUserHandler(context.Background(), args)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "organization sync needs the account the user is in")
+	assert.Empty(t, capturedSQL)
+}
+
 // TestDisableEnableUserHandler_EmptyUserID verifies that a present-but-blank user_id is
 // rejected as InvalidArgument before any request reaches Snowflake, rather than surfacing
 // as an opaque error from an ALTER USER "" statement.
@@ -160,3 +187,403 @@ func TestDisableEnableUserHandler_TrimsUserID(t *testing.T) {
 		})
 	}
 }
+
+// newStatementRowsMockServer records every statement it receives and answers all of them (and
+// the follow-up result GETs) with the given rows, so lookup-then-ALTER handlers can be exercised
+// end to end. created_on is typed timestamp_ltz like SHOW output; every other column is text.
+func newStatementRowsMockServer(t *testing.T, columns []string, rows [][]string, statements *[]string) *httptest.Server {
+	t.Helper()
+	rowType := make([]map[string]any, 0, len(columns))
+	for _, column := range columns {
+		columnType := "text"
+		if column == "created_on" {
+			columnType = "timestamp_ltz"
+		}
+		rowType = append(rowType, map[string]any{"name": column, "type": columnType})
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPost {
+			var body struct {
+				Statement string `json:"statement"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			*statements = append(*statements, body.Statement)
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"statementHandle": "handle",
+			"resultSetMetadata": map[string]any{
+				"numRows": len(rows),
+				"rowType": rowType,
+			},
+			"data": rows,
+		})
+	}))
+}
+
+// statementResult is the result set newStatementDispatchServer answers a statement with.
+type statementResult struct {
+	columns []string
+	rows    [][]string
+}
+
+// newStatementDispatchServer is newStatementRowsMockServer for tests that run several kinds of
+// statement: respond picks each statement's result. Statement handles are the statement's index,
+// so the follow-up GET returns the same result.
+func newStatementDispatchServer(t *testing.T, respond func(statement string) statementResult) *httptest.Server {
+	t.Helper()
+	var statements []string
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var handle int
+		if r.Method == http.MethodPost {
+			var body struct {
+				Statement string `json:"statement"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			handle = len(statements)
+			statements = append(statements, body.Statement)
+		} else {
+			var err error
+			handle, err = strconv.Atoi(path.Base(r.URL.Path))
+			require.NoError(t, err)
+		}
+
+		result := respond(statements[handle])
+		rowType := make([]map[string]any, 0, len(result.columns))
+		for _, column := range result.columns {
+			columnType := "text"
+			if column == "created_on" || column == "last_success_login" {
+				columnType = "timestamp_ltz"
+			}
+			rowType = append(rowType, map[string]any{"name": column, "type": columnType})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"statementHandle": strconv.Itoa(handle),
+			"resultSetMetadata": map[string]any{
+				"numRows": len(result.rows),
+				"rowType": rowType,
+			},
+			"data": result.rows,
+		})
+	}))
+}
+
+var policyColumns = []string{"created_on", "name", "database_name", "schema_name", "owner", "comment"}
+
+func TestSetAuthenticationPolicyHandler(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, policyColumns, [][]string{
+		{"", "KEYPAIR_ONLY_X", "SECURITY", "PUBLIC", "SECURITYADMIN", ""},
+		{"", "KEYPAIR_ONLY", "SECURITY", "PUBLIC", "SECURITYADMIN", ""},
+	}, &statements)
+	defer server.Close()
+
+	c := newTestConnector(t, server.URL)
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "svc_etl", "policy": "SECURITY.PUBLIC.KEYPAIR_ONLY"})
+	require.NoError(t, err)
+
+	result, _, err := c.setAuthenticationPolicyHandler(context.Background(), args)
+	require.NoError(t, err)
+	assert.True(t, result.Fields["success"].GetBoolValue())
+	assert.Equal(t, []string{
+		`SHOW AUTHENTICATION POLICIES LIKE 'KEYPAIR_ONLY' IN SCHEMA "SECURITY"."PUBLIC" LIMIT 50;`,
+		`ALTER USER "svc_etl" SET AUTHENTICATION POLICY "SECURITY"."PUBLIC"."KEYPAIR_ONLY" FORCE;`,
+	}, statements)
+}
+
+// TestSetPolicyHandlers_PolicyNotFound verifies a policy that does not exist is reported as
+// NotFound and no ALTER USER is issued.
+func TestSetPolicyHandlers_PolicyNotFound(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, policyColumns, [][]string{}, &statements)
+	defer server.Close()
+
+	c := newTestConnector(t, server.URL)
+
+	tests := []struct {
+		name    string
+		handler func(context.Context, *structpb.Struct) (*structpb.Struct, any, error)
+		policy  string
+	}{
+		{name: "set_network_policy", policy: "CORP", handler: func(ctx context.Context, args *structpb.Struct) (*structpb.Struct, any, error) {
+			return c.setNetworkPolicyHandler(ctx, args)
+		}},
+		{name: "set_password_policy", policy: "SECURITY.PUBLIC.STRICT", handler: func(ctx context.Context, args *structpb.Struct) (*structpb.Struct, any, error) {
+			return c.setPasswordPolicyHandler(ctx, args)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements = nil
+			args, err := structpb.NewStruct(map[string]any{"user_id": "svc_etl", "policy": tt.policy})
+			require.NoError(t, err)
+
+			_, _, err = tt.handler(context.Background(), args)
+			require.Error(t, err)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			for _, statement := range statements {
+				assert.NotContains(t, statement, "ALTER USER")
+			}
+		})
+	}
+}
+
+func TestSetAuthenticationPolicyHandler_RejectsUnqualifiedPolicy(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, policyColumns, [][]string{}, &statements)
+	defer server.Close()
+
+	c := newTestConnector(t, server.URL)
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "svc_etl", "policy": "KEYPAIR_ONLY"})
+	require.NoError(t, err)
+
+	_, _, err = c.setAuthenticationPolicyHandler(context.Background(), args)
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Empty(t, statements)
+}
+
+func TestUnsetPolicyHandler(t *testing.T) {
+	tests := []struct {
+		policyType string
+		want       string
+	}{
+		{policyType: "network", want: `ALTER USER "svc_etl" UNSET NETWORK_POLICY;`},
+		{policyType: "Authentication", want: `ALTER USER "svc_etl" UNSET AUTHENTICATION POLICY;`},
+		{policyType: "password", want: `ALTER USER "svc_etl" UNSET PASSWORD POLICY;`},
+		{policyType: "session", want: `ALTER USER "svc_etl" UNSET SESSION POLICY;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policyType, func(t *testing.T) {
+			var capturedSQL string
+			server := newSetUserDisabledMockServer(t, &capturedSQL)
+			defer server.Close()
+
+			c := newTestConnector(t, server.URL)
+
+			args, err := structpb.NewStruct(map[string]any{"user_id": "svc_etl", "policy_type": tt.policyType})
+			require.NoError(t, err)
+
+			_, _, err = c.unsetPolicyHandler(context.Background(), args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, capturedSQL)
+		})
+	}
+
+	var capturedSQL string
+	server := newSetUserDisabledMockServer(t, &capturedSQL)
+	defer server.Close()
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "svc_etl", "policy_type": "masking"})
+	require.NoError(t, err)
+	_, _, err = newTestConnector(t, server.URL).unsetPolicyHandler(context.Background(), args)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Empty(t, capturedSQL)
+}
+
+func TestUnsetRsaPublicKeyHandler(t *testing.T) {
+	var capturedSQL string
+	server := newSetUserDisabledMockServer(t, &capturedSQL)
+	defer server.Close()
+
+	c := newTestConnector(t, server.URL)
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "svc_etl", "key": "RSA_PUBLIC_KEY_2"})
+	require.NoError(t, err)
+	_, _, err = c.unsetRsaPublicKeyHandler(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER USER "svc_etl" UNSET RSA_PUBLIC_KEY_2;`, capturedSQL)
+
+	capturedSQL = ""
+	args, err = structpb.NewStruct(map[string]any{"user_id": "svc_etl", "key": "rsa_public_key_3"})
+	require.NoError(t, err)
+	_, _, err = c.unsetRsaPublicKeyHandler(context.Background(), args)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Empty(t, capturedSQL)
+}
+
+func TestUpdateUserHandler(t *testing.T) {
+	var capturedSQL string
+	server := newSetUserDisabledMockServer(t, &capturedSQL)
+	defer server.Close()
+
+	c := newTestConnector(t, server.URL)
+
+	args, err := structpb.NewStruct(map[string]any{
+		"user_id":                 "jdoe",
+		"email":                   "jane.o'doe@example.com",
+		"display_name":            " Jane Doe ",
+		"default_role":            "ANALYST",
+		"default_secondary_roles": "none",
+		"last_name":               "",
+	})
+	require.NoError(t, err)
+
+	_, _, err = c.updateUserHandler(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`ALTER USER "jdoe" SET DEFAULT_ROLE = 'ANALYST' DEFAULT_SECONDARY_ROLES = () DISPLAY_NAME = 'Jane Doe' EMAIL = 'jane.o''doe@example.com';`,
+		capturedSQL,
+	)
+}
+
+func TestUpdateUserHandler_RejectsEmptyAndInvalidUpdates(t *testing.T) {
+	var capturedSQL string
+	server := newSetUserDisabledMockServer(t, &capturedSQL)
+	defer server.Close()
+
+	c := newTestConnector(t, server.URL)
+
+	for _, fields := range []map[string]any{
+		{"user_id": "jdoe"},
+		{"user_id": "jdoe", "email": "  "},
+		{"user_id": "jdoe", "default_secondary_roles": "SOME"},
+	} {
+		args, err := structpb.NewStruct(fields)
+		require.NoError(t, err)
+		_, _, err = c.updateUserHandler(context.Background(), args)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), fields)
+	}
+	assert.Empty(t, capturedSQL)
+}
+
+func TestUpdateUserSchema_UsesAccountCreationFields(t *testing.T) {
+	require.Len(t, updateUserSchema.GetArguments(), len(updateUserFields)+1)
+	for _, argument := range updateUserSchema.GetArguments()[1:] {
+		field, ok := accountCreationFields[argument.GetName()]
+		require.True(t, ok, argument.GetName())
+		assert.Equal(t, field.GetDisplayName(), argument.GetDisplayName())
+	}
+}
+
+func TestResetPasswordHandler(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, []string{"property", "value"}, describeUserRows("jdoe", snowflake.UserTypePerson), &statements)
+	defer server.Close()
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "jdoe", "password_length": 20})
+	require.NoError(t, err)
+
+	result, _, err := newTestConnector(t, server.URL).resetPasswordHandler(context.Background(), args)
+	require.NoError(t, err)
+
+	password := result.GetFields()["password"].GetStringValue()
+	assert.Len(t, password, 20)
+	require.Len(t, statements, 2)
+	assert.Equal(t, `DESCRIBE USER "jdoe";`, statements[0])
+	assert.Equal(t, `ALTER USER "jdoe" SET PASSWORD = ? MUST_CHANGE_PASSWORD = TRUE;`, statements[1])
+	assert.NotContains(t, statements[1], password, "the password is bound, not written into the statement")
+}
+
+func TestResetPasswordHandler_RefusesServiceUser(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, []string{"property", "value"}, describeUserRows("svc_etl", snowflake.UserTypeService), &statements)
+	defer server.Close()
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "svc_etl"})
+	require.NoError(t, err)
+
+	_, _, err = newTestConnector(t, server.URL).resetPasswordHandler(context.Background(), args)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Len(t, statements, 1, "no ALTER USER after the type check")
+}
+
+func TestRequirePasswordChangeHandler(t *testing.T) {
+	var capturedSQL string
+	server := newSetUserDisabledMockServer(t, &capturedSQL)
+	defer server.Close()
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "jdoe"})
+	require.NoError(t, err)
+
+	_, _, err = newTestConnector(t, server.URL).requirePasswordChangeHandler(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER USER "jdoe" SET MUST_CHANGE_PASSWORD = TRUE;`, capturedSQL)
+}
+
+func TestUnlockUserHandler(t *testing.T) {
+	var capturedSQL string
+	server := newSetUserDisabledMockServer(t, &capturedSQL)
+	defer server.Close()
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "jdoe"})
+	require.NoError(t, err)
+
+	_, _, err = newTestConnector(t, server.URL).unlockUserHandler(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER USER "jdoe" SET MINS_TO_UNLOCK = 0;`, capturedSQL)
+}
+
+func TestTerminateUserSessionsHandler(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, nil, nil, &statements)
+	defer server.Close()
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "jdoe"})
+	require.NoError(t, err)
+
+	_, _, err = newTestConnector(t, server.URL).terminateUserSessionsHandler(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER USER "jdoe" SET DISABLED = true;`,
+		`ALTER USER "jdoe" ABORT ALL QUERIES;`,
+	}, statements)
+}
+
+func TestResetMfaHandler(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t,
+		[]string{"name", "type"},
+		[][]string{{"PASSKEY-1", "PASSKEY"}, {"TOTP-1", "TOTP"}},
+		&statements,
+	)
+	defer server.Close()
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "jdoe"})
+	require.NoError(t, err)
+
+	result, _, err := newTestConnector(t, server.URL).resetMfaHandler(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, float64(2), result.GetFields()[retRemovedMethodsKey].GetNumberValue())
+	assert.Equal(t, []string{
+		`SHOW MFA METHODS FOR USER "jdoe";`,
+		`ALTER USER "jdoe" REMOVE MFA METHOD "PASSKEY-1";`,
+		`ALTER USER "jdoe" REMOVE MFA METHOD "TOTP-1";`,
+	}, statements)
+}
+
+func TestBypassMfaHandler(t *testing.T) {
+	var capturedSQL string
+	server := newSetUserDisabledMockServer(t, &capturedSQL)
+	defer server.Close()
+
+	c := newTestConnector(t, server.URL)
+
+	for _, minutes := range []any{nil, 0, snowflake.MaxMinsToBypassMfa + 1} {
+		fields := map[string]any{"user_id": "jdoe"}
+		if minutes != nil {
+			fields["minutes"] = minutes
+		}
+		args, err := structpb.NewStruct(fields)
+		require.NoError(t, err)
+		_, _, err = c.bypassMfaHandler(context.Background(), args)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), minutes)
+	}
+	assert.Empty(t, capturedSQL)
+
+	args, err := structpb.NewStruct(map[string]any{"user_id": "jdoe", "minutes": 15})
+	require.NoError(t, err)
+	_, _, err = c.bypassMfaHandler(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER USER "jdoe" SET MINS_TO_BYPASS_MFA = 15;`, capturedSQL)
+}
@@ -0,0 +1,331 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+// grantedToApplicationRole is the SHOW GRANTS OF APPLICATION ROLE granted_to value for an
+// application role granted to another role of the same application.
+const grantedToApplicationRole = "APPLICATION_ROLE"
+
+// grantedOnAccount is the SHOW GRANTS granted_on value for account-level privileges.
+const grantedOnAccount = "ACCOUNT"
+
+// applicationBuilder syncs installed Snowflake Native Apps. An application's own privileges in
+// the account (SHOW GRANTS TO APPLICATION) are reported in its profile; its roles are synced
+// as application_role children.
+type applicationBuilder struct {
+	resourceType *v2.ResourceType
+	client       *snowflake.Client
+}
+
+func (o *applicationBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+func applicationResource(application *snowflake.Application, applicationGrants []snowflake.TableGrant) (*v2.Resource, error) {
+	grantedPrivileges := make([]string, 0, len(applicationGrants))
+	accountPrivileges := make([]string, 0, len(applicationGrants))
+	for _, ag := range applicationGrants {
+		if strings.EqualFold(ag.GrantedOn, grantedOnAccount) {
+			accountPrivileges = append(accountPrivileges, ag.Privilege)
+			grantedPrivileges = append(grantedPrivileges, fmt.Sprintf("%s ON ACCOUNT", ag.Privilege))
+			continue
+		}
+		grantedPrivileges = append(grantedPrivileges, fmt.Sprintf("%s ON %s %s", ag.Privilege, ag.GrantedOn, ag.Name))
+	}
+	sort.Strings(grantedPrivileges)
+	sort.Strings(accountPrivileges)
+
+	profile := map[string]interface{}{
+		profileKeyName:       application.Name,
+		"source_type":        application.SourceType,
+		"source":             application.Source,
+		"version":            application.Version,
+		"label":              application.Label,
+		"owner":              application.Owner,
+		profileKeyComment:    application.Comment,
+		"account_privileges": stringListProfileValue(accountPrivileges),
+		"granted_privileges": stringListProfileValue(grantedPrivileges),
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithResourceProfile(profile),
+		rs.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: applicationRoleResourceType.Id}),
+	}
+	if !application.CreatedOn.IsZero() {
+		opts = append(opts, rs.WithResourceCreatedAt(application.CreatedOn))
+	}
+
+	return rs.NewAppResource(application.Name, applicationResourceType, application.Name, nil, opts...)
+}
+
+func (o *applicationBuilder) List(ctx context.Context, _ *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	l := ctxzap.Extract(ctx)
+
+	applications, err := o.client.ListApplications(ctx)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			l.Debug("ListApplications: insufficient privileges, skipping", zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list applications")
+	}
+
+	var resources []*v2.Resource
+	for i := range applications {
+		application := &applications[i]
+		applicationGrants, err := o.client.ListApplicationGrants(ctx, application.Name)
+		if err != nil {
+			if !snowflake.IsInsufficientPrivileges(err) {
+				return nil, nil, wrapError(err, "failed to list grants to application")
+			}
+			l.Debug("ListApplicationGrants: insufficient privileges, syncing without privileges",
+				zap.String("application", application.Name), zap.Error(err))
+		}
+
+		resource, err := applicationResource(application, applicationGrants)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create application resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *applicationBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return []*v2.Entitlement{
+		ent.NewAssignmentEntitlement(
+			resource,
+			ownerEntitlement,
+			ent.WithGrantableTo(accountRoleResourceType),
+			ent.WithDescription(fmt.Sprintf("Is owned by %s", resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("Is owner of %s", resource.DisplayName)),
+		),
+	}, nil, nil
+}
+
+func (o *applicationBuilder) Grants(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	owner, _ := rs.GetProfileStringValue(rs.GetProfile(resource), "owner")
+	if owner == "" {
+		return nil, nil, nil
+	}
+
+	roleID, err := rs.NewResourceID(accountRoleResourceType, owner)
+	if err != nil {
+		return nil, nil, wrapError(err, "unable to create role resource id")
+	}
+
+	return []*v2.Grant{
+		grant.NewGrant(resource, ownerEntitlement, roleID, addExpandableOpts(owner)...),
+	}, nil, nil
+}
+
+func newApplicationBuilder(client *snowflake.Client) *applicationBuilder {
+	return &applicationBuilder{
+		resourceType: applicationResourceType,
+		client:       client,
+	}
+}
+
+// applicationRoleBuilder syncs the roles of each application. They are granted to account
+// roles, which is how consumers decide who may use the application.
+type applicationRoleBuilder struct {
+	resourceType *v2.ResourceType
+	client       *snowflake.Client
+}
+
+func (o *applicationRoleBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+func applicationRoleAssignedEntitlementID(application, role string) string {
+	return fmt.Sprintf("%s:%s.%s:%s", applicationRoleResourceType.Id, application, role, assignedEntitlement)
+}
+
+func applicationRoleResource(role *snowflake.ApplicationRole, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		profileKeyName:    role.Name,
+		"application":     role.Application,
+		"owner":           role.Owner,
+		profileKeyComment: role.Comment,
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithParentResourceID(parentResourceID),
+	}
+	if !role.CreatedOn.IsZero() {
+		opts = append(opts, rs.WithResourceCreatedAt(role.CreatedOn))
+	}
+
+	return rs.NewRoleResource(role.Name, applicationRoleResourceType, role.FullyQualifiedName(), []rs.RoleTraitOption{rs.WithRoleProfile(profile)}, opts...)
+}
+
+// applicationRoleNameParts returns the application and role name of an application role
+// resource, preferring the unquoted names in its profile over splitting the APP.ROLE ID.
+func applicationRoleNameParts(resource *v2.Resource) (string, string, error) {
+	profile := rs.GetProfile(resource)
+	application, _ := rs.GetProfileStringValue(profile, "application")
+	name, _ := rs.GetProfileStringValue(profile, profileKeyName)
+	if application != "" && name != "" {
+		return application, name, nil
+	}
+
+	parts := snowflake.SplitQualifiedName(resource.Id.Resource)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid application role resource ID format: %s", resource.Id.Resource)
+	}
+	return parts[0], parts[1], nil
+}
+
+func (o *applicationRoleBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	if parentResourceID == nil {
+		return nil, &rs.SyncOpResults{}, nil
+	}
+
+	roles, err := o.client.ListApplicationRoles(ctx, parentResourceID.Resource)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			ctxzap.Extract(ctx).Debug("ListApplicationRoles: insufficient privileges, skipping",
+				zap.String("application", parentResourceID.Resource), zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list application roles")
+	}
+
+	var resources []*v2.Resource
+	for i := range roles {
+		resource, err := applicationRoleResource(&roles[i], parentResourceID)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create application role resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *applicationRoleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return []*v2.Entitlement{
+		ent.NewAssignmentEntitlement(
+			resource,
+			assignedEntitlement,
+			ent.WithGrantableTo(accountRoleResourceType, applicationRoleResourceType),
+			ent.WithDescription(fmt.Sprintf("Has %s application role assigned", resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s application role %s", resource.DisplayName, assignedEntitlement)),
+		),
+	}, nil, nil
+}
+
+func (o *applicationRoleBuilder) Grants(ctx context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	application, role, err := applicationRoleNameParts(resource)
+	if err != nil {
+		return nil, nil, wrapError(err, "expected format: application.role")
+	}
+
+	grantees, err := o.client.ListApplicationRoleGrantees(ctx, application, role)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			ctxzap.Extract(ctx).Debug("skipping application role grants: insufficient privileges to show grants",
+				zap.String("application_role", resource.Id.Resource))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list application role grantees")
+	}
+
+	var grants []*v2.Grant
+	for _, grantee := range grantees {
+		switch grantee.GranteeType {
+		case grantedToRole:
+			roleID, err := rs.NewResourceID(accountRoleResourceType, grantee.GranteeName)
+			if err != nil {
+				return nil, nil, wrapError(err, "unable to create role resource id")
+			}
+			grants = append(grants, grant.NewGrant(resource, assignedEntitlement, roleID, addExpandableOpts(grantee.GranteeName)...))
+		case grantedToApplicationRole:
+			// Application roles can only be granted within their own application.
+			granteeRole := grantee.GranteeName
+			if parts := snowflake.SplitQualifiedName(granteeRole); len(parts) == 2 {
+				granteeRole = parts[1]
+			}
+			granteeID, err := rs.NewResourceID(applicationRoleResourceType, fmt.Sprintf("%s.%s", application, granteeRole))
+			if err != nil {
+				return nil, nil, wrapError(err, "unable to create application role resource id")
+			}
+			grants = append(grants, grant.NewGrant(resource, assignedEntitlement, granteeID,
+				grant.WithAnnotation(&v2.GrantExpandable{
+					EntitlementIds:  []string{applicationRoleAssignedEntitlementID(application, granteeRole)},
+					Shallow:         true,
+					ResourceTypeIds: []string{accountRoleResourceType.Id, applicationRoleResourceType.Id},
+				}),
+			))
+		}
+	}
+
+	return grants, nil, nil
+}
+
+func (o *applicationRoleBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	if principal.Id.ResourceType != accountRoleResourceType.Id {
+		return nil, fmt.Errorf("baton-snowflake: application roles can only be granted to account roles")
+	}
+
+	application, role, err := applicationRoleNameParts(entitlement.Resource)
+	if err != nil {
+		return nil, wrapError(err, "expected format: application.role")
+	}
+
+	if err := o.client.GrantApplicationRole(ctx, application, role, principal.Id.Resource); err != nil {
+		err = wrapError(err, "failed to grant application role")
+		ctxzap.Extract(ctx).Error(err.Error(),
+			zap.String("application_role", entitlement.Resource.Id.Resource),
+			zap.String("account_role", principal.Id.Resource),
+		)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (o *applicationRoleBuilder) Revoke(ctx context.Context, g *v2.Grant) (annotations.Annotations, error) {
+	if g.Principal.Id.ResourceType != accountRoleResourceType.Id {
+		return nil, fmt.Errorf("baton-snowflake: only account roles can be revoked from application roles")
+	}
+
+	application, role, err := applicationRoleNameParts(g.Entitlement.Resource)
+	if err != nil {
+		return nil, wrapError(err, "expected format: application.role")
+	}
+
+	if err := o.client.RevokeApplicationRole(ctx, application, role, g.Principal.Id.Resource); err != nil {
+		err = wrapError(err, "failed to revoke application role")
+		ctxzap.Extract(ctx).Error(err.Error(),
+			zap.String("application_role", g.Entitlement.Resource.Id.Resource),
+			zap.String("account_role", g.Principal.Id.Resource),
+		)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func newApplicationRoleBuilder(client *snowflake.Client) *applicationRoleBuilder {
+	return &applicationRoleBuilder{
+		resourceType: applicationRoleResourceType,
+		client:       client,
+	}
+}
@@ -0,0 +1,53 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+func TestApplicationResource_ReportsAccountPrivileges(t *testing.T) {
+	resource, err := applicationResource(
+		&snowflake.Application{Name: "CRM_APP", Source: "ACME.LISTING.CRM", Owner: "ACCOUNTADMIN"},
+		[]snowflake.TableGrant{
+			{Privilege: "EXECUTE TASK", GrantedOn: "ACCOUNT", Name: "ACME"},
+			{Privilege: "CREATE DATABASE", GrantedOn: "ACCOUNT", Name: "ACME"},
+			{Privilege: "USAGE", GrantedOn: "WAREHOUSE", Name: "COMPUTE_WH"},
+		},
+	)
+	require.NoError(t, err)
+
+	profile := rs.GetProfile(resource).AsMap()
+	require.Equal(t, []interface{}{"CREATE DATABASE", "EXECUTE TASK"}, profile["account_privileges"])
+	require.Equal(t,
+		[]interface{}{"CREATE DATABASE ON ACCOUNT", "EXECUTE TASK ON ACCOUNT", "USAGE ON WAREHOUSE COMPUTE_WH"},
+		profile["granted_privileges"],
+	)
+}
+
+func TestApplicationRoleNameParts(t *testing.T) {
+	parent := &v2.ResourceId{ResourceType: applicationResourceType.Id, Resource: "CRM.APP"}
+	resource, err := applicationRoleResource(&snowflake.ApplicationRole{Name: "APP_VIEWER", Application: "CRM.APP"}, parent)
+	require.NoError(t, err)
+	require.Equal(t, "CRM.APP.APP_VIEWER", resource.GetId().GetResource())
+
+	// The profile keeps a dotted application name intact.
+	application, role, err := applicationRoleNameParts(resource)
+	require.NoError(t, err)
+	require.Equal(t, "CRM.APP", application)
+	require.Equal(t, "APP_VIEWER", role)
+}
+
+func TestApplicationRoleBuilder_GrantRejectsUsers(t *testing.T) {
+	builder := newApplicationRoleBuilder(nil)
+	principal := &v2.Resource{Id: &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "ALICE"}}
+	entitlement := &v2.Entitlement{Resource: &v2.Resource{Id: &v2.ResourceId{ResourceType: applicationRoleResourceType.Id, Resource: "CRM_APP.APP_VIEWER"}}}
+
+	_, err := builder.Grant(context.Background(), principal, entitlement)
+	require.Error(t, err)
+}
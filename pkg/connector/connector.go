@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"net/http"
+	"slices"
 	"strings"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -18,31 +21,102 @@ import (
 )
 
 type Connector struct {
-	Client            *snowflake.Client
-	SyncSecrets       bool
-	excludedDatabases []string
+	Client                    *snowflake.Client
+	SyncSecrets               bool
+	syncMfaMethods            bool
+	syncUserNetworkPolicies   bool
+	objects                   *objectFilter
+	accessHistoryLookbackDays int
+	incremental               *incrementalSync
+	// disabledResourceTypes are the ids of the resource types the config turned off.
+	disabledResourceTypes map[string]bool
+	// organization is set in organization sync, which syncs every account in the organization.
+	organization *organization
+}
+
+// syncs reports whether resources of the type are synced.
+func (d *Connector) syncs(resourceType *v2.ResourceType) bool {
+	return !d.disabledResourceTypes[resourceType.Id]
+}
+
+// profileLookups are the lookups List adds to user, database and table profiles. Each account-wide
+// one is made only when the resource types it reports on are synced.
+type profileLookups struct {
+	// policies are the authentication, password and session policies attached to users.
+	policies bool
+	// dataPolicies are the masking and row access policies attached to tables.
+	dataPolicies bool
+	// tags are the tags set on databases, schemas, tables and columns.
+	tags bool
+	// networkPolicies are the network policies set on users, one DESCRIBE USER per user, read only
+	// when sync-user-network-policies is set.
+	networkPolicies bool
+}
+
+// profileLookups returns the profile lookups of the synced resource types.
+func (d *Connector) profileLookups() profileLookups {
+	return profileLookups{
+		policies:        d.syncs(authenticationPolicyResourceType) || d.syncs(passwordPolicyResourceType) || d.syncs(sessionPolicyResourceType),
+		dataPolicies:    d.syncs(maskingPolicyResourceType) || d.syncs(rowAccessPolicyResourceType),
+		tags:            d.syncs(tagResourceType),
+		networkPolicies: d.syncUserNetworkPolicies,
+	}
 }
 
 // ResourceSyncers returns a ResourceSyncerV2 for each resource type that should be synced from the upstream service.
 func (d *Connector) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncerV2 {
+	builders := d.accountSyncers(ctx, d.Client, d.incremental)
+	if d.organization != nil {
+		return d.organization.resourceSyncers(ctx, builders)
+	}
+	return builders
+}
+
+// accountSyncers returns the builders that sync the account client reads.
+func (d *Connector) accountSyncers(ctx context.Context, client *snowflake.Client, incremental *incrementalSync) []connectorbuilder.ResourceSyncerV2 {
+	linkOrganizationUsers := d.syncs(organizationUserResourceType)
+	lookups := d.profileLookups()
+	var users connectorbuilder.ResourceSyncerV2 = newUserBuilder(client, d.SyncSecrets, d.syncMfaMethods, linkOrganizationUsers, lookups)
+	if d.SyncSecrets {
+		users = newTokenIssuingUserBuilder(client, d.syncMfaMethods, linkOrganizationUsers, lookups)
+	}
+
 	builders := []connectorbuilder.ResourceSyncerV2{
-		newUserBuilder(d.Client, d.SyncSecrets),
-		newAccountRoleBuilder(d.Client),
-		newDatabaseBuilder(d.Client, d.SyncSecrets, d.excludedDatabases),
-		newTableBuilder(d.Client),
-		newIntegrationBuilder(d.Client),
-		newLicenseBuilder(d.Client),
+		newCurrentAccountBuilder(client),
+		users,
+		newAccountRoleBuilder(client, incremental),
+		newDatabaseBuilder(client, d.SyncSecrets, d.syncs(tableResourceType), d.objects, lookups),
+		newTableBuilder(client, incremental, d.objects, lookups),
+		newIntegrationBuilder(client),
+		newNetworkPolicyBuilder(client),
+		newNetworkRuleBuilder(client),
+		newPolicyBuilder(client, authenticationPolicyResourceType, snowflake.PolicyKindAuthentication),
+		newPolicyBuilder(client, passwordPolicyResourceType, snowflake.PolicyKindPassword),
+		newPolicyBuilder(client, sessionPolicyResourceType, snowflake.PolicyKindSession),
+		newDataPolicyBuilder(client, maskingPolicyResourceType, snowflake.PolicyKindMasking),
+		newDataPolicyBuilder(client, rowAccessPolicyResourceType, snowflake.PolicyKindRowAccess),
+		newTagBuilder(client),
+		newShareBuilder(client),
+		newReaderAccountBuilder(client),
+		newApplicationBuilder(client),
+		newApplicationRoleBuilder(client),
+		newLicenseBuilder(client),
+		newOrganizationUserBuilder(client),
+		newOrganizationUserGroupBuilder(client, d.organization),
 	}
 
 	if d.SyncSecrets {
 		builders = append(
 			builders,
-			newSecretBuilder(d.Client),
-			newRsaBuilder(d.Client),
+			newSecretBuilder(client),
+			newRsaBuilder(client),
+			newProgrammaticAccessTokenBuilder(client),
 		)
 	}
 
-	return builders
+	return slices.DeleteFunc(builders, func(builder connectorbuilder.ResourceSyncerV2) bool {
+		return !d.syncs(builder.ResourceType(ctx))
+	})
 }
 
 // Asset takes an input AssetRef and attempts to fetch it using the connector's authenticated http client
@@ -51,133 +125,174 @@ func (d *Connector) Asset(ctx context.Context, asset *v2.AssetRef) (string, io.R
 	return "", nil, nil
 }
 
+// accountCreationFields are the user properties CreateAccount reads from the account profile.
+// The update_user action offers the mutable subset of them.
+var accountCreationFields = map[string]*v2.ConnectorAccountCreationSchema_Field{
+	profileKeyName: {
+		DisplayName: "User Name",
+		Required:    true,
+		Description: "The name of the user (required - case-sensitive)",
+		Placeholder: "username",
+		Order:       0,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"login": {
+		DisplayName: "Login Name",
+		Required:    false,
+		Description: "The login name for the user (defaults to email if not provided)",
+		Placeholder: "user@example.com",
+		Order:       1,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"display_name": {
+		DisplayName: "Display Name",
+		Required:    false,
+		Description: "The display name for the user",
+		Placeholder: "John Doe",
+		Order:       2,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"first_name": {
+		DisplayName: "First Name",
+		Required:    false,
+		Description: "The first name of the user",
+		Placeholder: "John",
+		Order:       3,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"last_name": {
+		DisplayName: "Last Name",
+		Required:    false,
+		Description: "The last name of the user",
+		Placeholder: "Doe",
+		Order:       4,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"email": {
+		DisplayName: "Email",
+		Required:    false,
+		Description: "The email address for the user",
+		Placeholder: "user@example.com",
+		Order:       5,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	profileKeyComment: {
+		DisplayName: "Comment",
+		Required:    false,
+		Description: "A comment or description for the user",
+		Placeholder: "User description",
+		Order:       6,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"disabled": {
+		DisplayName: "Disabled",
+		Required:    false,
+		Description: "Whether the user account should be disabled",
+		Order:       7,
+		Field: &v2.ConnectorAccountCreationSchema_Field_BoolField{
+			BoolField: &v2.ConnectorAccountCreationSchema_BoolField{},
+		},
+	},
+	"default_warehouse": {
+		DisplayName: "Default Warehouse",
+		Required:    false,
+		Description: "The default warehouse to use when this user starts a session",
+		Placeholder: "COMPUTE_WH",
+		Order:       8,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"default_namespace": {
+		DisplayName: "Default Namespace",
+		Required:    false,
+		Description: "The default namespace to use when this user starts a session",
+		Placeholder: "DATABASE.SCHEMA",
+		Order:       9,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"default_role": {
+		DisplayName: "Default Role",
+		Required:    false,
+		Description: "The default role to use when this user starts a session",
+		Placeholder: "PUBLIC",
+		Order:       10,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"default_secondary_roles": {
+		DisplayName: "Default Secondary Roles",
+		Required:    false,
+		Description: "The default secondary roles of this user to use when starting a session. Valid values: ALL or NONE. Default is ALL.",
+		Placeholder: "ALL",
+		Order:       11,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	"type": {
+		DisplayName: "Type",
+		Required:    false,
+		Description: "The type of user: PERSON, SERVICE or LEGACY_SERVICE. SERVICE users cannot have a password. Default is PERSON.",
+		Placeholder: "PERSON",
+		Order:       12,
+		Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+			StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+		},
+	},
+	profileKeyKeyPair: {
+		DisplayName: "Generate Key Pair",
+		Required:    false,
+		Description: "Generate an RSA key pair for the user instead of a password. Requires the no-password credential option.",
+		Order:       13,
+		Field: &v2.ConnectorAccountCreationSchema_Field_BoolField{
+			BoolField: &v2.ConnectorAccountCreationSchema_BoolField{},
+		},
+	},
+}
+
+// organizationAccountCreationField names the account organization sync creates a user in.
+var organizationAccountCreationField = &v2.ConnectorAccountCreationSchema_Field{
+	DisplayName: "Account",
+	Required:    true,
+	Description: "The name or locator of the organization account to create the user in",
+	Placeholder: "MY_ACCOUNT",
+	Order:       14,
+	Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+		StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+	},
+}
+
 // Metadata returns metadata about the connector.
 func (d *Connector) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error) {
+	fields := accountCreationFields
+	if d.organization != nil {
+		fields = maps.Clone(accountCreationFields)
+		fields[profileKeyAccount] = organizationAccountCreationField
+	}
+
 	return &v2.ConnectorMetadata{
 		DisplayName: "Baton Snowflake",
 		Description: "Connector syncing users, databases, tables, and account roles from Snowflake.",
 		AccountCreationSchema: &v2.ConnectorAccountCreationSchema{
-			FieldMap: map[string]*v2.ConnectorAccountCreationSchema_Field{
-				profileKeyName: {
-					DisplayName: "User Name",
-					Required:    true,
-					Description: "The name of the user (required - case-sensitive)",
-					Placeholder: "username",
-					Order:       0,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				"login": {
-					DisplayName: "Login Name",
-					Required:    false,
-					Description: "The login name for the user (defaults to email if not provided)",
-					Placeholder: "user@example.com",
-					Order:       1,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				"display_name": {
-					DisplayName: "Display Name",
-					Required:    false,
-					Description: "The display name for the user",
-					Placeholder: "John Doe",
-					Order:       2,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				"first_name": {
-					DisplayName: "First Name",
-					Required:    false,
-					Description: "The first name of the user",
-					Placeholder: "John",
-					Order:       3,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				"last_name": {
-					DisplayName: "Last Name",
-					Required:    false,
-					Description: "The last name of the user",
-					Placeholder: "Doe",
-					Order:       4,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				"email": {
-					DisplayName: "Email",
-					Required:    false,
-					Description: "The email address for the user",
-					Placeholder: "user@example.com",
-					Order:       5,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				profileKeyComment: {
-					DisplayName: "Comment",
-					Required:    false,
-					Description: "A comment or description for the user",
-					Placeholder: "User description",
-					Order:       6,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				"disabled": {
-					DisplayName: "Disabled",
-					Required:    false,
-					Description: "Whether the user account should be disabled",
-					Order:       7,
-					Field: &v2.ConnectorAccountCreationSchema_Field_BoolField{
-						BoolField: &v2.ConnectorAccountCreationSchema_BoolField{},
-					},
-				},
-				"default_warehouse": {
-					DisplayName: "Default Warehouse",
-					Required:    false,
-					Description: "The default warehouse to use when this user starts a session",
-					Placeholder: "COMPUTE_WH",
-					Order:       8,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				"default_namespace": {
-					DisplayName: "Default Namespace",
-					Required:    false,
-					Description: "The default namespace to use when this user starts a session",
-					Placeholder: "DATABASE.SCHEMA",
-					Order:       9,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				"default_role": {
-					DisplayName: "Default Role",
-					Required:    false,
-					Description: "The default role to use when this user starts a session",
-					Placeholder: "PUBLIC",
-					Order:       10,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-				"default_secondary_roles": {
-					DisplayName: "Default Secondary Roles",
-					Required:    false,
-					Description: "The default secondary roles of this user to use when starting a session. Valid values: ALL or NONE. Default is ALL.",
-					Placeholder: "ALL",
-					Order:       11,
-					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
-						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
-					},
-				},
-			},
+			FieldMap: fields,
 		},
 	}, nil
 }
@@ -213,6 +328,37 @@ func missingLoginPrivilegeErr(users []snowflake.User) error {
 	return errors.New("baton-snowflake: SHOW USERS returned no login_name; role likely lacks OWNERSHIP or account-level MANAGE GRANTS")
 }
 
+// disabledResourceTypes maps each sync toggle in cfg that is off to the resource types it covers.
+func disabledResourceTypes(cfg *config.Snowflake) map[string]bool {
+	toggles := []struct {
+		enabled bool
+		types   []*v2.ResourceType
+	}{
+		{cfg.SyncTables, []*v2.ResourceType{tableResourceType}},
+		{cfg.SyncIntegrations, []*v2.ResourceType{integrationResourceType}},
+		{cfg.SyncLicenses, []*v2.ResourceType{licenseResourceType}},
+		{cfg.SyncNetworkPolicies, []*v2.ResourceType{networkPolicyResourceType, networkRuleResourceType}},
+		{cfg.SyncPolicies, []*v2.ResourceType{authenticationPolicyResourceType, passwordPolicyResourceType, sessionPolicyResourceType}},
+		{cfg.SyncDataPolicies, []*v2.ResourceType{maskingPolicyResourceType, rowAccessPolicyResourceType}},
+		{cfg.SyncTags, []*v2.ResourceType{tagResourceType}},
+		{cfg.SyncShares, []*v2.ResourceType{shareResourceType}},
+		{cfg.SyncReaderAccounts, []*v2.ResourceType{readerAccountResourceType}},
+		{cfg.SyncApplications, []*v2.ResourceType{applicationResourceType, applicationRoleResourceType}},
+		{cfg.SyncOrganizationUsers, []*v2.ResourceType{organizationUserResourceType, organizationUserGroupResourceType}},
+	}
+
+	disabled := map[string]bool{}
+	for _, toggle := range toggles {
+		if toggle.enabled {
+			continue
+		}
+		for _, resourceType := range toggle.types {
+			disabled[resourceType.Id] = true
+		}
+	}
+	return disabled
+}
+
 // New returns a new instance of the connector.
 func New(ctx context.Context, cfg *config.Snowflake, _ *cli.ConnectorOpts) (connectorbuilder.ConnectorBuilderV2, []connectorbuilder.Opt, error) {
 	if cfg.PrivateKeyPath == "" && len(cfg.PrivateKey) == 0 {
@@ -247,18 +393,56 @@ func New(ctx context.Context, cfg *config.Snowflake, _ *cli.ConnectorOpts) (conn
 	if err != nil {
 		return nil, nil, err
 	}
-	ts := snowflake.NewJWTTokenSource(&jwtConfig)
-	ctx = context.WithValue(ctx, oauth2.HTTPClient, baseHttpClient)
-	httpClient := oauth2.NewClient(ctx, ts)
 
-	client, err := snowflake.New(cfg.AccountUrl, jwtConfig, httpClient)
+	client, err := newClient(ctx, baseHttpClient, cfg.AccountUrl, jwtConfig)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return &Connector{
-		Client:            client,
-		SyncSecrets:       cfg.SyncSecrets,
-		excludedDatabases: cfg.ExcludedDatabases,
-	}, nil, nil
+	// Incremental sync keeps one account's grants, so organization sync reads every grant.
+	var incremental *incrementalSync
+	if cfg.IncrementalSync && !cfg.OrganizationSync {
+		incremental = newIncrementalSync(client, cfg.FullSyncIntervalHours)
+	}
+
+	objects := newObjectFilter(objectFilterConfig{
+		IncludedDatabases: cfg.IncludedDatabases,
+		ExcludedDatabases: cfg.ExcludedDatabases,
+		IncludedSchemas:   cfg.IncludedSchemas,
+		ExcludedSchemas:   cfg.ExcludedSchemas,
+		IncludedTables:    cfg.IncludedTables,
+		ExcludedTables:    cfg.ExcludedTables,
+	})
+
+	connector := &Connector{
+		Client:                    client,
+		SyncSecrets:               cfg.SyncSecrets,
+		syncMfaMethods:            cfg.SyncMfaMethods,
+		syncUserNetworkPolicies:   cfg.SyncUserNetworkPolicies,
+		objects:                   objects,
+		accessHistoryLookbackDays: cfg.AccessHistoryLookbackDays,
+		incremental:               incremental,
+		disabledResourceTypes:     disabledResourceTypes(cfg),
+	}
+
+	if cfg.OrganizationSync {
+		credentials, err := parseAccountCredentials(cfg.OrganizationAccountCredentials)
+		if err != nil {
+			return nil, nil, err
+		}
+		shared := accountCredentials{userIdentifier: cfg.UserIdentifier, privateKey: privateKeyValue}
+		connector.organization = newOrganization(client, baseHttpClient, shared, credentials,
+			func(ctx context.Context, client *snowflake.Client) []connectorbuilder.ResourceSyncerV2 {
+				return connector.accountSyncers(ctx, client, nil)
+			})
+	}
+
+	return connector, nil, nil
+}
+
+// newClient returns a client for the account at accountURL, authenticated with a key-pair JWT.
+func newClient(ctx context.Context, baseHttpClient *http.Client, accountURL string, jwtConfig snowflake.JWTConfig) (*snowflake.Client, error) {
+	ts := snowflake.NewJWTTokenSource(&jwtConfig)
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, baseHttpClient)
+	return snowflake.New(accountURL, jwtConfig, oauth2.NewClient(ctx, ts))
 }
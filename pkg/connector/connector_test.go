@@ -1,8 +1,12 @@
 package connector
 
 import (
+	"context"
 	"testing"
 
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+
+	"github.com/conductorone/baton-snowflake/pkg/config"
 	"github.com/conductorone/baton-snowflake/pkg/snowflake"
 )
 
@@ -35,3 +39,66 @@ func TestMissingLoginPrivilegeErr(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceSyncers_HonorsSyncToggles(t *testing.T) {
+	cfg := &config.Snowflake{
+		SyncIntegrations:    true,
+		SyncLicenses:        false,
+		SyncNetworkPolicies: true,
+		SyncPolicies:        true,
+		SyncDataPolicies:    true,
+		SyncTags:            true,
+		SyncShares:          true,
+		SyncReaderAccounts:  true,
+		SyncApplications:    false,
+		SyncTables:          false,
+	}
+	c := &Connector{disabledResourceTypes: disabledResourceTypes(cfg)}
+
+	synced := map[string]bool{}
+	for _, builder := range c.ResourceSyncers(context.Background()) {
+		synced[builder.ResourceType(context.Background()).Id] = true
+	}
+	for _, id := range []string{userResourceType.Id, accountRoleResourceType.Id, databaseResourceType.Id, integrationResourceType.Id, tagResourceType.Id} {
+		if !synced[id] {
+			t.Errorf("resource type %s is not synced, want synced", id)
+		}
+	}
+	for _, id := range []string{tableResourceType.Id, licenseResourceType.Id, applicationResourceType.Id, applicationRoleResourceType.Id} {
+		if synced[id] {
+			t.Errorf("resource type %s is synced, want it turned off", id)
+		}
+	}
+
+	for _, feed := range c.EventFeeds(context.Background()) {
+		if id := feed.EventFeedMetadata(context.Background()).GetId(); id == tableUsageFeedID {
+			t.Errorf("event feed %s is registered with tables turned off", id)
+		}
+	}
+}
+
+func TestProfileLookups_FollowSyncToggles(t *testing.T) {
+	c := &Connector{disabledResourceTypes: disabledResourceTypes(&config.Snowflake{SyncPolicies: true})}
+	if got, want := c.profileLookups(), (profileLookups{policies: true}); got != want {
+		t.Errorf("profileLookups() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDatabaseResource_OmitsTableChildTypeWhenTablesAreOff(t *testing.T) {
+	for _, syncTables := range []bool{true, false} {
+		resource, err := databaseResource(&snowflake.Database{Name: "DB"}, false, syncTables)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hasTables := false
+		for _, a := range resource.GetAnnotations() {
+			child := &v2.ChildResourceType{}
+			if a.MessageIs(child) && a.UnmarshalTo(child) == nil && child.ResourceTypeId == tableResourceType.Id {
+				hasTables = true
+			}
+		}
+		if hasTables != syncTables {
+			t.Errorf("syncTables=%v: table child type annotation present = %v", syncTables, hasTables)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+const privilegeApply = "apply"
+
+// dataPolicyPrivileges are the SHOW GRANTS ON <kind> POLICY privileges synced as entitlements.
+// Entitlement IDs are the lower-cased privilege, as for tables.
+var dataPolicyPrivileges = []string{privilegeApply, privilegeOwner}
+
+// dataPolicyBuilder syncs masking or row access policies. Roles holding APPLY can attach or
+// detach the policy; the OWNERSHIP role can also change what it masks.
+type dataPolicyBuilder struct {
+	resourceType *v2.ResourceType
+	kind         string
+	client       *snowflake.Client
+}
+
+func (o *dataPolicyBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+func dataPolicyResource(resourceType *v2.ResourceType, policy *snowflake.Policy, description *snowflake.DataPolicyDescription, attachedTo []string) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		profileKeyName:    policy.Name,
+		"database":        policy.DatabaseName,
+		"schema":          policy.SchemaName,
+		"owner":           policy.Owner,
+		profileKeyComment: policy.Comment,
+		"attached_to":     stringListProfileValue(attachedTo),
+	}
+	if description != nil {
+		profile["signature"] = description.Signature
+		profile["return_type"] = description.ReturnType
+		profile["body"] = description.Body
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithResourceProfile(profile),
+	}
+	if !policy.CreatedOn.IsZero() {
+		opts = append(opts, rs.WithResourceCreatedAt(policy.CreatedOn))
+	}
+
+	return rs.NewResource(policy.Name, resourceType, policy.FullyQualifiedName(), opts...)
+}
+
+// dataPolicyAttachedTo lists what the policy protects: DB.SCHEMA.TABLE.COLUMN for masking
+// policies, DB.SCHEMA.TABLE for row access policies.
+func dataPolicyAttachedTo(kind, policyName string, attachments *snowflake.DataPolicyAttachments) []string {
+	if attachments == nil {
+		return nil
+	}
+
+	var attachedTo []string
+	for table, policies := range attachments.Tables {
+		switch kind {
+		case snowflake.PolicyKindMasking:
+			for column, policy := range policies.ColumnMaskingPolicies {
+				if policy == policyName {
+					attachedTo = append(attachedTo, table+"."+column)
+				}
+			}
+		case snowflake.PolicyKindRowAccess:
+			if policies.RowAccessPolicy == policyName {
+				attachedTo = append(attachedTo, table)
+			}
+		}
+	}
+	sort.Strings(attachedTo)
+	return attachedTo
+}
+
+func (o *dataPolicyBuilder) List(ctx context.Context, _ *v2.ResourceId, opts rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	l := ctxzap.Extract(ctx)
+
+	policies, err := o.client.ListPolicies(ctx, o.kind)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			l.Debug("ListPolicies: insufficient privileges, skipping", zap.String("kind", o.kind), zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, fmt.Sprintf("failed to list %s policies", o.resourceType.DisplayName))
+	}
+	if len(policies) == 0 {
+		return nil, nil, nil
+	}
+
+	attachments, err := o.client.GetDataPolicyAttachments(ctx, opts.Session)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to get masking and row access policy attachments")
+	}
+
+	var resources []*v2.Resource
+	for i := range policies {
+		policy := &policies[i]
+		description, err := o.client.DescribeDataPolicy(ctx, policy)
+		if err != nil {
+			if !snowflake.IsInsufficientPrivileges(err) {
+				return nil, nil, wrapError(err, "failed to describe policy")
+			}
+			l.Debug("DescribeDataPolicy: insufficient privileges, syncing without body",
+				zap.String("policy", policy.FullyQualifiedName()), zap.Error(err))
+		}
+
+		attachedTo := dataPolicyAttachedTo(o.kind, policy.FullyQualifiedName(), attachments)
+		resource, err := dataPolicyResource(o.resourceType, policy, description, attachedTo)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create policy resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *dataPolicyBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+	for _, privilege := range dataPolicyPrivileges {
+		rv = append(rv, ent.NewAssignmentEntitlement(
+			resource,
+			privilege,
+			ent.WithGrantableTo(accountRoleResourceType),
+			ent.WithDescription(fmt.Sprintf("%s privilege on %s %s", strings.ToUpper(privilege), o.resourceType.DisplayName, resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, strings.ToUpper(privilege))),
+		))
+	}
+	return rv, nil, nil
+}
+
+func (o *dataPolicyBuilder) Grants(ctx context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	parts, err := schemaObjectNameParts(resource)
+	if err != nil {
+		return nil, nil, wrapError(err, "expected format: database.schema.policy")
+	}
+
+	policyGrants, err := o.client.ListPolicyGrants(ctx, &snowflake.Policy{
+		DatabaseName: parts[0],
+		SchemaName:   parts[1],
+		Name:         parts[2],
+		Kind:         o.kind,
+	})
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			ctxzap.Extract(ctx).Debug("skipping policy grants: insufficient privileges to show grants",
+				zap.String("policy", resource.Id.Resource))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list policy grants")
+	}
+
+	return privilegeRoleGrants(resource, policyGrants, dataPolicyPrivileges)
+}
+
+// schemaObjectNameParts returns the database, schema and name of a schema-level object whose
+// resource ID is DB.SCHEMA.NAME. The unquoted names in the profile are preferred, since a
+// quoted name containing a dot cannot be split back out of the ID reliably.
+func schemaObjectNameParts(resource *v2.Resource) ([]string, error) {
+	profile := rs.GetProfile(resource)
+	database, _ := rs.GetProfileStringValue(profile, "database")
+	schema, _ := rs.GetProfileStringValue(profile, "schema")
+	name, _ := rs.GetProfileStringValue(profile, profileKeyName)
+	if database != "" && schema != "" && name != "" {
+		return []string{database, schema, name}, nil
+	}
+
+	parts := snowflake.SplitQualifiedName(resource.Id.Resource)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid resource ID format: %s", resource.Id.Resource)
+	}
+	return parts, nil
+}
+
+// privilegeRoleGrants turns SHOW GRANTS ON <object> rows into grants of the matching
+// lower-cased privilege entitlement. Only the given privileges and role grantees are kept.
+func privilegeRoleGrants(resource *v2.Resource, objectGrants []snowflake.TableGrant, privileges []string) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	var grants []*v2.Grant
+	for _, og := range objectGrants {
+		entitlementID := strings.ToLower(og.Privilege)
+		if !snowflake.Contains(privileges, entitlementID) {
+			continue
+		}
+		if og.GrantedTo != grantedToRole {
+			continue
+		}
+		roleID, err := rs.NewResourceID(accountRoleResourceType, og.GranteeName)
+		if err != nil {
+			return nil, nil, wrapError(err, "unable to create role resource id")
+		}
+		grants = append(grants, grant.NewGrant(resource, entitlementID, roleID, addExpandableOpts(og.GranteeName)...))
+	}
+
+	return grants, nil, nil
+}
+
+func newDataPolicyBuilder(client *snowflake.Client, resourceType *v2.ResourceType, kind string) *dataPolicyBuilder {
+	return &dataPolicyBuilder{
+		resourceType: resourceType,
+		kind:         kind,
+		client:       client,
+	}
+}
@@ -0,0 +1,56 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+func testDataPolicyAttachments() *snowflake.DataPolicyAttachments {
+	return &snowflake.DataPolicyAttachments{
+		Tables: map[string]*snowflake.TablePolicies{
+			"DB.PUBLIC.CUSTOMERS": {
+				ColumnMaskingPolicies: map[string]string{"SSN": "SECURITY.PUBLIC.SSN_MASK"},
+				RowAccessPolicy:       "SECURITY.PUBLIC.REGION_FILTER",
+			},
+			"DB.PUBLIC.EMPLOYEES": {
+				ColumnMaskingPolicies: map[string]string{"SSN": "SECURITY.PUBLIC.SSN_MASK"},
+			},
+		},
+		PolicyOwners: map[string]string{"SECURITY.PUBLIC.SSN_MASK": "SYSADMIN"},
+	}
+}
+
+func TestDataPolicyAttachedTo(t *testing.T) {
+	attachments := testDataPolicyAttachments()
+
+	require.Equal(t,
+		[]string{"DB.PUBLIC.CUSTOMERS.SSN", "DB.PUBLIC.EMPLOYEES.SSN"},
+		dataPolicyAttachedTo(snowflake.PolicyKindMasking, "SECURITY.PUBLIC.SSN_MASK", attachments),
+	)
+	require.Equal(t,
+		[]string{"DB.PUBLIC.CUSTOMERS"},
+		dataPolicyAttachedTo(snowflake.PolicyKindRowAccess, "SECURITY.PUBLIC.REGION_FILTER", attachments),
+	)
+	require.Empty(t, dataPolicyAttachedTo(snowflake.PolicyKindMasking, "SECURITY.PUBLIC.UNUSED", attachments))
+}
+
+// TestTablePolicyProfile_FlagsOwnerOfMaskingPolicy checks a table whose owner role also owns the
+// masking policy on one of its columns is flagged, since that role can unmask the data.
+func TestTablePolicyProfile_FlagsOwnerOfMaskingPolicy(t *testing.T) {
+	attachments := testDataPolicyAttachments()
+
+	profile := tablePolicyProfile(&snowflake.Table{DatabaseName: "DB", SchemaName: "PUBLIC", Name: "CUSTOMERS", Owner: "SYSADMIN"}, attachments)
+	require.Equal(t, map[string]interface{}{"SSN": "SECURITY.PUBLIC.SSN_MASK"}, profile["column_masking_policies"])
+	require.Equal(t, "SECURITY.PUBLIC.REGION_FILTER", profile["row_access_policy"])
+	require.Equal(t, true, profile["masking_policy_owned_by_table_owner"])
+
+	profile = tablePolicyProfile(&snowflake.Table{DatabaseName: "DB", SchemaName: "PUBLIC", Name: "EMPLOYEES", Owner: "HR_ADMIN"}, attachments)
+	require.Equal(t, false, profile["masking_policy_owned_by_table_owner"])
+
+	profile = tablePolicyProfile(&snowflake.Table{DatabaseName: "DB", SchemaName: "PUBLIC", Name: "ORDERS"}, nil)
+	require.Equal(t, map[string]interface{}{}, profile["column_masking_policies"])
+	require.Equal(t, "", profile["row_access_policy"])
+}
@@ -3,9 +3,9 @@ package connector
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
@@ -16,17 +16,19 @@ import (
 )
 
 type databaseBuilder struct {
-	resourceType      *v2.ResourceType
-	client            *snowflake.Client
-	syncSecrets       bool
-	excludedDatabases map[string]struct{} // uppercase-normalised names to exclude
+	resourceType *v2.ResourceType
+	client       *snowflake.Client
+	syncSecrets  bool
+	syncTables   bool
+	objects      *objectFilter
+	lookups      profileLookups
 }
 
 func (o *databaseBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
 	return databaseResourceType
 }
 
-func databaseResource(database *snowflake.Database, syncSecrets bool) (*v2.Resource, error) {
+func databaseResource(database *snowflake.Database, syncSecrets, syncTables bool) (*v2.Resource, error) {
 	profile := map[string]interface{}{
 		profileKeyName:        database.Name,
 		"kind":                database.Kind,
@@ -36,7 +38,9 @@ func databaseResource(database *snowflake.Database, syncSecrets bool) (*v2.Resou
 
 	opts := []rs.ResourceOption{
 		rs.WithResourceProfile(profile),
-		rs.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: tableResourceType.Id}),
+	}
+	if syncTables {
+		opts = append(opts, rs.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: tableResourceType.Id}))
 	}
 	if syncSecrets {
 		opts = append(opts, rs.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: secretResourceType.Id}))
@@ -67,16 +71,23 @@ func (o *databaseBuilder) List(ctx context.Context, parentResourceID *v2.Resourc
 		return nil, nil, wrapError(err, "failed to list databases")
 	}
 
+	var objectTags *snowflake.ObjectTags
+	if o.lookups.tags && len(databases) > 0 {
+		objectTags, err = o.client.GetObjectTags(ctx, opts.Session)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to get object tags")
+		}
+	}
+
 	var resources []*v2.Resource
 	for _, database := range databases {
-		if _, excluded := o.excludedDatabases[strings.ToUpper(database.Name)]; excluded {
+		if !o.objects.includesDatabase(database.Name) {
 			continue
 		}
-		resource, err := databaseResource(&database, o.syncSecrets) // #nosec G601
+		resource, err := o.databaseResourceWithTags(&database, objectTags) // #nosec G601
 		if err != nil {
-			return nil, nil, wrapError(err, "failed to create database resource")
+			return nil, nil, err
 		}
-
 		resources = append(resources, resource)
 	}
 
@@ -92,6 +103,51 @@ func (o *databaseBuilder) List(ctx context.Context, parentResourceID *v2.Resourc
 	return resources, &rs.SyncOpResults{NextPageToken: nextCursor}, nil
 }
 
+// databaseResourceWithTags is the database resource with the tag profile field List adds, left
+// out when objectTags is nil.
+func (o *databaseBuilder) databaseResourceWithTags(database *snowflake.Database, objectTags *snowflake.ObjectTags) (*v2.Resource, error) {
+	resource, err := databaseResource(database, o.syncSecrets, o.syncTables)
+	if err != nil {
+		return nil, wrapError(err, "failed to create database resource")
+	}
+	if objectTags != nil && !objectTags.Unavailable {
+		tags := map[string]interface{}{"tags": stringMapProfileValue(objectTags.ForDatabase(database.Name))}
+		if err := addProfileFields(resource, tags); err != nil {
+			return nil, wrapError(err, "failed to add database tag profile fields")
+		}
+	}
+	return resource, nil
+}
+
+// Get returns the database as List builds it, or nil if it does not exist or is filtered out.
+func (o *databaseBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	if !o.objects.includesDatabase(resourceId.Resource) {
+		return nil, nil, nil
+	}
+
+	database, _, err := o.client.GetDatabase(ctx, resourceId.Resource)
+	if err != nil {
+		return nil, nil, wrapError(err, fmt.Sprintf("failed to get database %q", resourceId.Resource))
+	}
+	if database == nil {
+		return nil, nil, nil
+	}
+
+	var objectTags *snowflake.ObjectTags
+	if o.lookups.tags {
+		objectTags, err = o.client.GetDatabaseTags(ctx, database.Name)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to get object tags")
+		}
+	}
+
+	resource, err := o.databaseResourceWithTags(database, objectTags)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resource, nil, nil
+}
+
 func (o *databaseBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
 	var rv []*v2.Entitlement
 
@@ -147,15 +203,13 @@ func (o *databaseBuilder) Grants(ctx context.Context, resource *v2.Resource, opt
 	return grants, nil, nil
 }
 
-func newDatabaseBuilder(client *snowflake.Client, syncSecrets bool, excludedDatabases []string) *databaseBuilder {
-	excluded := make(map[string]struct{}, len(excludedDatabases))
-	for _, name := range excludedDatabases {
-		excluded[strings.ToUpper(name)] = struct{}{}
-	}
+func newDatabaseBuilder(client *snowflake.Client, syncSecrets, syncTables bool, objects *objectFilter, lookups profileLookups) *databaseBuilder {
 	return &databaseBuilder{
-		resourceType:      databaseResourceType,
-		client:            client,
-		syncSecrets:       syncSecrets,
-		excludedDatabases: excluded,
+		resourceType: databaseResourceType,
+		client:       client,
+		syncSecrets:  syncSecrets,
+		syncTables:   syncTables,
+		objects:      objects,
+		lookups:      lookups,
 	}
 }
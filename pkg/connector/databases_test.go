@@ -116,3 +116,53 @@ func TestDatabaseBuilder_Grants_EmitsOwnerWhenRoleIsDescribable(t *testing.T) {
 	require.Len(t, grants, 1)
 	assert.Equal(t, "SYSADMIN", grants[0].Principal.Id.Resource)
 }
+
+func TestDatabaseBuilder_Get_SkipsExcludedDatabase(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, nil, nil, &statements)
+	defer server.Close()
+
+	client, err := snowflake.New(server.URL, snowflake.JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	builder := newDatabaseBuilder(client, false, true, newObjectFilter(objectFilterConfig{ExcludedDatabases: []string{"snowflake"}}), profileLookups{})
+	resource, _, err := builder.Get(context.Background(), &v2.ResourceId{ResourceType: databaseResourceType.Id, Resource: "SNOWFLAKE"}, nil)
+
+	require.NoError(t, err)
+	assert.Nil(t, resource)
+	assert.Empty(t, statements, "an excluded database must not be looked up")
+}
+
+func TestDatabaseBuilder_Get_ReturnsNilForMissingDatabase(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, []string{"name", "owner", "kind", "origin"}, [][]string{}, &statements)
+	defer server.Close()
+
+	client, err := snowflake.New(server.URL, snowflake.JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	builder := newDatabaseBuilder(client, false, true, nil, profileLookups{})
+	resource, _, err := builder.Get(context.Background(), &v2.ResourceId{ResourceType: databaseResourceType.Id, Resource: "GONE"}, nil)
+
+	require.NoError(t, err)
+	assert.Nil(t, resource)
+	require.Len(t, statements, 1)
+	assert.Contains(t, statements[0], "SHOW DATABASES LIKE 'GONE'")
+}
+
+func TestDatabaseBuilder_List_SkipsTagsWhenTagsAreOff(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, []string{"name", "owner", "kind", "origin"}, [][]string{{"SALES", "SYSADMIN", "STANDARD", ""}}, &statements)
+	defer server.Close()
+
+	client, err := snowflake.New(server.URL, snowflake.JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	builder := newDatabaseBuilder(client, false, true, nil, profileLookups{})
+	resources, _, err := builder.List(context.Background(), nil, rs.SyncOpAttrs{})
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	require.Len(t, statements, 1, "tags are not read when they are not synced")
+	assert.NotContains(t, rs.GetProfile(resources[0]).GetFields(), "tags")
+}
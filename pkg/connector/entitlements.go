@@ -3,4 +3,6 @@ package connector
 const (
 	assignedEntitlement = "assigned"
 	ownerEntitlement    = "owns"
+	attachedEntitlement = "attached"
+	consumerEntitlement = "consumer"
 )
@@ -0,0 +1,123 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventPageSize is the number of ACCOUNT_USAGE rows an event feed reads per page when the caller
+// does not ask for a size.
+const eventPageSize = 100
+
+var _ connectorbuilder.EventFeedsLimited = (*Connector)(nil)
+
+// EventFeeds returns the event feeds backed by SNOWFLAKE.ACCOUNT_USAGE views. The table usage
+// feed is left out when tables are not synced. The views only cover the account they are read in,
+// so organization sync reads each feed in every account of the organization.
+func (d *Connector) EventFeeds(ctx context.Context) []connectorbuilder.EventFeed {
+	newFeeds := []func(client *snowflake.Client) connectorbuilder.EventFeed{
+		func(client *snowflake.Client) connectorbuilder.EventFeed { return newLoginHistoryFeed(client) },
+	}
+	if d.syncs(tableResourceType) {
+		newFeeds = append(newFeeds, func(client *snowflake.Client) connectorbuilder.EventFeed {
+			return newTableUsageFeed(client, d.accessHistoryLookbackDays)
+		})
+	}
+	newFeeds = append(newFeeds, func(client *snowflake.Client) connectorbuilder.EventFeed {
+		return newGrantChangesFeed(client, d.syncs(tableResourceType))
+	})
+
+	feeds := make([]connectorbuilder.EventFeed, 0, len(newFeeds))
+	for _, newFeed := range newFeeds {
+		if d.organization != nil {
+			feeds = append(feeds, &accountScopedFeed{org: d.organization, newFeed: newFeed})
+			continue
+		}
+		feeds = append(feeds, newFeed(d.Client))
+	}
+	return feeds
+}
+
+// parseEventCursor decodes a feed cursor into the ACCOUNT_USAGE position it stopped at. An empty
+// cursor has no position, so the feed starts at the earliest event it was asked for.
+func parseEventCursor(cursor string) (*snowflake.AccountUsagePosition, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	position := &snowflake.AccountUsagePosition{}
+	if err := json.Unmarshal([]byte(cursor), position); err != nil {
+		return nil, fmt.Errorf("baton-snowflake: invalid event cursor: %w", err)
+	}
+	return position, nil
+}
+
+// nextEventCursor encodes position as a feed cursor. Without a position (an empty page) the
+// current cursor is kept, so the next poll resumes from the same place.
+func nextEventCursor(current string, position *snowflake.AccountUsagePosition) (string, error) {
+	if position == nil {
+		return current, nil
+	}
+	cursor, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return string(cursor), nil
+}
+
+// eventRange returns the page size to read and the ACCOUNT_USAGE rows to read it from: those after
+// the cursor's position or, without one, from earliestEvent; and older than latency, so rows the
+// view has yet to fill in are not skipped.
+func eventRange(
+	earliestEvent *timestamppb.Timestamp,
+	position *snowflake.AccountUsagePosition,
+	latency time.Duration,
+	size int,
+) (*snowflake.AccountUsageRange, int) {
+	if size <= 0 {
+		size = eventPageSize
+	}
+	r := &snowflake.AccountUsageRange{After: position, Until: time.Now().Add(-latency)}
+	if earliestEvent != nil {
+		r.Since = earliestEvent.AsTime()
+	}
+	return r, size
+}
+
+// The event*Resource functions return the minimal resources events refer to, with the ids the
+// resource builders give the same objects.
+
+func eventUserResource(userName string) *v2.Resource {
+	return &v2.Resource{
+		Id:          &v2.ResourceId{ResourceType: userResourceType.Id, Resource: userName},
+		DisplayName: userName,
+	}
+}
+
+func eventAccountRoleResource(roleName string) *v2.Resource {
+	return &v2.Resource{
+		Id:          &v2.ResourceId{ResourceType: accountRoleResourceType.Id, Resource: roleName},
+		DisplayName: roleName,
+	}
+}
+
+func eventDatabaseResource(databaseName string) *v2.Resource {
+	return &v2.Resource{
+		Id:          &v2.ResourceId{ResourceType: databaseResourceType.Id, Resource: databaseName},
+		DisplayName: databaseName,
+	}
+}
+
+func eventTableResource(databaseName, schemaName, tableName string) *v2.Resource {
+	return &v2.Resource{
+		Id:               &v2.ResourceId{ResourceType: tableResourceType.Id, Resource: fmt.Sprintf("%s.%s.%s", databaseName, schemaName, tableName)},
+		ParentResourceId: &v2.ResourceId{ResourceType: databaseResourceType.Id, Resource: databaseName},
+		DisplayName:      tableName,
+	}
+}
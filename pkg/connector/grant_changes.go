@@ -0,0 +1,219 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	grantChangesFeedID = "grant_changes"
+
+	grantChangeSourceGrants       = "grants"
+	grantChangeSourceQueryHistory = "query_history"
+)
+
+// grantChangesFeed emits a grant or revoke event for every change to an entitlement this connector
+// syncs: account role assignments, table privileges and database ownership. It reads two sources
+// with separate positions, since they fill in at different rates: GRANTS_TO_ROLES and
+// GRANTS_TO_USERS, which record every change, and the GRANT and REVOKE statements in
+// QUERY_HISTORY, which show the common ones sooner. A statement's change is dropped when a grants
+// view row read in the same page records the same grant or revoke, but one the grants views only
+// record in a later page is emitted from both. Table privilege changes are skipped when tables are
+// not synced.
+type grantChangesFeed struct {
+	client     *snowflake.Client
+	syncTables bool
+}
+
+// grantChangesCursor is the grant changes feed cursor: a position in each source.
+type grantChangesCursor struct {
+	Grants       *snowflake.AccountUsagePosition `json:"grants,omitempty"`
+	QueryHistory *snowflake.AccountUsagePosition `json:"query_history,omitempty"`
+}
+
+func (f *grantChangesFeed) EventFeedMetadata(ctx context.Context) *v2.EventFeedMetadata {
+	return &v2.EventFeedMetadata{
+		Id:                  grantChangesFeedID,
+		SupportedEventTypes: []v2.EventType{v2.EventType_EVENT_TYPE_CREATE_GRANT, v2.EventType_EVENT_TYPE_CREATE_REVOKE},
+	}
+}
+
+func (f *grantChangesFeed) ListEvents(
+	ctx context.Context,
+	earliestEvent *timestamppb.Timestamp,
+	pToken *pagination.StreamToken,
+) ([]*v2.Event, *pagination.StreamState, annotations.Annotations, error) {
+	cursor := &grantChangesCursor{}
+	if pToken.Cursor != "" {
+		if err := json.Unmarshal([]byte(pToken.Cursor), cursor); err != nil {
+			return nil, nil, nil, fmt.Errorf("baton-snowflake: invalid event cursor: %w", err)
+		}
+	}
+
+	r, size := eventRange(earliestEvent, cursor.Grants, snowflake.GrantsLatency, pToken.Size)
+	changes, _, err := f.client.ListGrantChanges(ctx, r, size)
+	if err != nil {
+		return nil, nil, nil, wrapError(err, "failed to list grant changes")
+	}
+
+	var events []*v2.Event
+	// recorded are the grants and revokes the grants views recorded in this page.
+	recorded := map[string]bool{}
+	for i := range changes {
+		event, err := grantChangeEvent(&changes[i], changes[i].ChangedOn, grantChangesFeedID+":"+changes[i].ChangeID, nil)
+		if err != nil {
+			return nil, nil, nil, wrapError(err, "failed to create grant change event")
+		}
+		if event != nil && f.syncs(&changes[i]) {
+			events = append(events, event)
+			recorded[grantChangeEventKey(event)] = true
+		}
+		cursor.Grants = changes[i].Position()
+	}
+
+	r, _ = eventRange(earliestEvent, cursor.QueryHistory, snowflake.QueryHistoryLatency, size)
+	statements, _, err := f.client.ListGrantStatements(ctx, r, size)
+	if err != nil {
+		return nil, nil, nil, wrapError(err, "failed to list grant statements")
+	}
+
+	for i := range statements {
+		statement := &statements[i]
+		for j, change := range statement.Changes() {
+			id := fmt.Sprintf("%s:%s:%d", grantChangesFeedID, statement.QueryID, j)
+			event, err := grantChangeEvent(&change, statement.StartTime, id, statement)
+			if err != nil {
+				return nil, nil, nil, wrapError(err, "failed to create grant change event")
+			}
+			if event != nil && f.syncs(&change) && !recorded[grantChangeEventKey(event)] {
+				events = append(events, event)
+			}
+		}
+		cursor.QueryHistory = statement.Position()
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].GetOccurredAt().AsTime().Before(events[j].GetOccurredAt().AsTime())
+	})
+
+	nextCursor := pToken.Cursor
+	if len(changes) > 0 || len(statements) > 0 {
+		encoded, err := json.Marshal(cursor)
+		if err != nil {
+			return nil, nil, nil, wrapError(err, "failed to create grant changes cursor")
+		}
+		nextCursor = string(encoded)
+	}
+
+	hasMore := len(changes) == size || len(statements) == size
+	return events, &pagination.StreamState{Cursor: nextCursor, HasMore: hasMore}, nil, nil
+}
+
+// grantChangeEvent is the grant or revoke event for change, or nil if the change is to an
+// entitlement this connector does not sync. Changes read from a statement carry its query id and
+// the user and role that ran it.
+func grantChangeEvent(change *snowflake.GrantChange, changedOn, id string, statement *snowflake.GrantStatement) (*v2.Event, error) {
+	entitlement, principal := grantChangeEntitlement(change)
+	if entitlement == nil {
+		return nil, nil
+	}
+
+	occurredAt, err := snowflake.ParseAccountUsageTime(changedOn)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{"source": grantChangeSourceGrants}
+	if statement != nil {
+		fields = map[string]interface{}{
+			"source":    grantChangeSourceQueryHistory,
+			"query_id":  statement.QueryID,
+			"user_name": statement.UserName,
+			"role_name": statement.RoleName,
+		}
+	}
+	details, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &v2.Event{
+		Id:          id,
+		OccurredAt:  timestamppb.New(occurredAt),
+		Annotations: annotations.New(details),
+	}
+	if change.Change == snowflake.GrantChangeRevoke {
+		event.Event = &v2.Event_CreateRevokeEvent{CreateRevokeEvent: &v2.CreateRevokeEvent{
+			Entitlement: entitlement,
+			Principal:   principal,
+		}}
+	} else {
+		event.Event = &v2.Event_CreateGrantEvent{CreateGrantEvent: &v2.CreateGrantEvent{
+			Entitlement: entitlement,
+			Principal:   principal,
+		}}
+	}
+	return event, nil
+}
+
+// grantChangeEventKey identifies the grant or revoke an event records, whichever source it came from.
+func grantChangeEventKey(event *v2.Event) string {
+	kind, entitlement, principal := "grant", event.GetCreateGrantEvent().GetEntitlement(), event.GetCreateGrantEvent().GetPrincipal()
+	if revoke := event.GetCreateRevokeEvent(); revoke != nil {
+		kind, entitlement, principal = "revoke", revoke.GetEntitlement(), revoke.GetPrincipal()
+	}
+	return fmt.Sprintf("%s:%s:%s:%s", kind, entitlement.GetId(), principal.GetId().GetResourceType(), principal.GetId().GetResource())
+}
+
+// grantChangeEntitlement maps a change to the entitlement it grants or revokes and the principal,
+// matching the ids the role, table and database builders use.
+func grantChangeEntitlement(change *snowflake.GrantChange) (*v2.Entitlement, *v2.Resource) {
+	var principal *v2.Resource
+	switch change.GrantedTo {
+	case grantedToRole:
+		principal = eventAccountRoleResource(change.GranteeName)
+	case grantedToUser:
+		principal = eventUserResource(change.GranteeName)
+	default:
+		return nil, nil
+	}
+
+	privilege := strings.ToLower(change.Privilege)
+	switch change.GrantedOn {
+	case "ROLE":
+		if privilege != "usage" {
+			return nil, nil
+		}
+		return ent.NewAssignmentEntitlement(eventAccountRoleResource(change.Name), assignedEntitlement), principal
+	case "TABLE":
+		table := eventTableResource(change.TableCatalog, change.TableSchema, change.Name)
+		return ent.NewAssignmentEntitlement(table, privilege), principal
+	case "DATABASE":
+		// Databases only have the owner entitlement.
+		if privilege != privilegeOwner {
+			return nil, nil
+		}
+		return ent.NewAssignmentEntitlement(eventDatabaseResource(change.Name), ownerEntitlement), principal
+	}
+	return nil, nil
+}
+
+// syncs reports whether the change is to a resource of a type the connector syncs.
+func (f *grantChangesFeed) syncs(change *snowflake.GrantChange) bool {
+	return f.syncTables || change.GrantedOn != "TABLE"
+}
+
+func newGrantChangesFeed(client *snowflake.Client, syncTables bool) *grantChangesFeed {
+	return &grantChangesFeed{client: client, syncTables: syncTables}
+}
@@ -0,0 +1,138 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantChangesFeed_ListEvents(t *testing.T) {
+	// The mock server answers both reads with the same rows, so each row carries the columns of a
+	// grants view change and of a GRANT statement.
+	var statements []string
+	server := newStatementRowsMockServer(t,
+		[]string{
+			"CHANGE_ID", "CHANGED_ON", "CHANGE", "PRIVILEGE", "GRANTED_ON", "NAME", "TABLE_CATALOG", "TABLE_SCHEMA",
+			"GRANTED_TO", "GRANTEE_NAME", "QUERY_ID", "START_TIME", "QUERY_TEXT", "USER_NAME", "ROLE_NAME",
+		},
+		[][]string{
+			{
+				"REVOKE|USER|JDOE|ROLE|||ANALYST|USAGE", "2025-01-02 03:04:06.000000000 +00:00", "REVOKE", "USAGE", "ROLE", "ANALYST", "", "",
+				"USER", "JDOE", "01b2", "2025-01-02 03:04:05.000000000 +00:00", "GRANT SELECT ON TABLE SALES.PUBLIC.ORDERS TO ROLE ANALYST;", "ADMIN", "SECURITYADMIN",
+			},
+		},
+		&statements,
+	)
+	defer server.Close()
+
+	feed := newGrantChangesFeed(newTestConnector(t, server.URL).Client, true)
+	events, state, _, err := feed.ListEvents(context.Background(), nil, &pagination.StreamToken{Size: 1})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.True(t, state.HasMore)
+
+	grant := events[0].GetCreateGrantEvent()
+	require.NotNil(t, grant, "the statement's grant happened first")
+	assert.Equal(t, "grant_changes:01b2:0", events[0].GetId())
+	assert.Equal(t, "table:SALES.PUBLIC.ORDERS:select", grant.GetEntitlement().GetId())
+	assert.Equal(t, "ANALYST", grant.GetPrincipal().GetId().GetResource())
+
+	revoke := events[1].GetCreateRevokeEvent()
+	require.NotNil(t, revoke)
+	assert.Equal(t, "account_role:ANALYST:assigned", revoke.GetEntitlement().GetId())
+	assert.Equal(t, userResourceType.Id, revoke.GetPrincipal().GetId().GetResourceType())
+
+	cursor := &grantChangesCursor{}
+	require.NoError(t, json.Unmarshal([]byte(state.Cursor), cursor))
+	assert.Equal(t, "REVOKE|USER|JDOE|ROLE|||ANALYST|USAGE", cursor.Grants.ID)
+	assert.Equal(t, "01b2", cursor.QueryHistory.ID)
+
+	_, _, _, err = feed.ListEvents(context.Background(), nil, &pagination.StreamToken{Cursor: state.Cursor})
+	require.NoError(t, err)
+	require.Len(t, statements, 4)
+	assert.Contains(t, statements[2], "g.CHANGE_KEY > 'REVOKE|USER|JDOE|ROLE|||ANALYST|USAGE'")
+	assert.Contains(t, statements[3], "q.QUERY_ID > '01b2'")
+}
+
+func TestGrantChangeEntitlement(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      snowflake.GrantChange
+		entitlement string
+	}{
+		{
+			name:        "role to role",
+			change:      snowflake.GrantChange{Privilege: "USAGE", GrantedOn: "ROLE", Name: "ANALYST", GrantedTo: grantedToRole, GranteeName: "SYSADMIN"},
+			entitlement: "account_role:ANALYST:assigned",
+		},
+		{
+			name:        "table privilege",
+			change:      snowflake.GrantChange{Privilege: "UPDATE", GrantedOn: "TABLE", TableCatalog: "SALES", TableSchema: "PUBLIC", Name: "ORDERS", GrantedTo: grantedToRole, GranteeName: "ANALYST"},
+			entitlement: "table:SALES.PUBLIC.ORDERS:update",
+		},
+		{
+			name:        "database ownership",
+			change:      snowflake.GrantChange{Privilege: "OWNERSHIP", GrantedOn: "DATABASE", Name: "SALES", GrantedTo: grantedToRole, GranteeName: "SYSADMIN"},
+			entitlement: "database:SALES:owns",
+		},
+		{
+			name:   "database usage is not synced",
+			change: snowflake.GrantChange{Privilege: "USAGE", GrantedOn: "DATABASE", Name: "SALES", GrantedTo: grantedToRole, GranteeName: "ANALYST"},
+		},
+		{
+			name:   "share grantee is not synced",
+			change: snowflake.GrantChange{Privilege: "USAGE", GrantedOn: "DATABASE", Name: "SALES", GrantedTo: "SHARE", GranteeName: "PARTNERS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entitlement, principal := grantChangeEntitlement(&tt.change)
+			if tt.entitlement == "" {
+				assert.Nil(t, entitlement)
+				return
+			}
+			require.NotNil(t, entitlement)
+			assert.Equal(t, tt.entitlement, entitlement.GetId())
+			assert.Equal(t, tt.change.GranteeName, principal.GetId().GetResource())
+		})
+	}
+}
+
+func TestGrantChangesFeed_SkipsTableChangesWhenTablesAreOff(t *testing.T) {
+	feed := newGrantChangesFeed(nil, false)
+	assert.False(t, feed.syncs(&snowflake.GrantChange{GrantedOn: "TABLE"}))
+	assert.True(t, feed.syncs(&snowflake.GrantChange{GrantedOn: "ROLE"}))
+	assert.True(t, newGrantChangesFeed(nil, true).syncs(&snowflake.GrantChange{GrantedOn: "TABLE"}))
+}
+
+func TestGrantChangesFeed_DropsStatementChangesTheGrantsViewsRecorded(t *testing.T) {
+	// Both reads return the same row: the grants view change and the statement record one grant.
+	var statements []string
+	server := newStatementRowsMockServer(t,
+		[]string{
+			"CHANGE_ID", "CHANGED_ON", "CHANGE", "PRIVILEGE", "GRANTED_ON", "NAME", "TABLE_CATALOG", "TABLE_SCHEMA",
+			"GRANTED_TO", "GRANTEE_NAME", "QUERY_ID", "START_TIME", "QUERY_TEXT", "USER_NAME", "ROLE_NAME",
+		},
+		[][]string{
+			{
+				"GRANT|ROLE|ANALYST|TABLE|SALES|PUBLIC|ORDERS|SELECT", "2025-01-02 03:04:06.000000000 +00:00", "GRANT", "SELECT", "TABLE", "ORDERS", "SALES", "PUBLIC",
+				"ROLE", "ANALYST", "01b2", "2025-01-02 03:04:05.000000000 +00:00", "GRANT SELECT ON TABLE SALES.PUBLIC.ORDERS TO ROLE ANALYST;", "ADMIN", "SECURITYADMIN",
+			},
+		},
+		&statements,
+	)
+	defer server.Close()
+
+	feed := newGrantChangesFeed(newTestConnector(t, server.URL).Client, true)
+	events, _, _, err := feed.ListEvents(context.Background(), nil, &pagination.StreamToken{Size: 1})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "grant_changes:GRANT|ROLE|ANALYST|TABLE|SALES|PUBLIC|ORDERS|SELECT", events[0].GetId())
+	assert.Equal(t, "table:SALES.PUBLIC.ORDERS:select", events[0].GetCreateGrantEvent().GetEntitlement().GetId())
+}
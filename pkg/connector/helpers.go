@@ -3,6 +3,9 @@ package connector
 import (
 	"fmt"
 	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func wrapError(err error, message string) error {
@@ -20,3 +23,41 @@ func quoteSnowflakeIdentifier(identifier string) string {
 	// Wrap in double quotes
 	return fmt.Sprintf(`"%s"`, escaped)
 }
+
+// stringListProfileValue converts a string slice to the []interface{} form structpb accepts
+// for resource profiles.
+func stringListProfileValue(values []string) []interface{} {
+	out := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		out = append(out, v)
+	}
+	return out
+}
+
+// stringMapProfileValue converts a string map to the map[string]interface{} form structpb
+// accepts for resource profiles. A nil map becomes an empty one.
+func stringMapProfileValue(values map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		out[k] = v
+	}
+	return out
+}
+
+// addProfileFields merges fields into an already-built resource's profile, for values that are
+// resolved after the resource itself (per-page lookups such as effective policies).
+func addProfileFields(resource *v2.Resource, fields map[string]interface{}) error {
+	profile := resource.GetProfile()
+	if profile == nil {
+		profile = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+		resource.SetProfile(profile)
+	}
+	for key, value := range fields {
+		v, err := structpb.NewValue(value)
+		if err != nil {
+			return err
+		}
+		profile.Fields[key] = v
+	}
+	return nil
+}
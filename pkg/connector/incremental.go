@@ -0,0 +1,174 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/session"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// incrementalSyncID is the session store sync id grants are kept under between syncs. The SDK
+	// only clears the active sync's entries when a sync ends.
+	incrementalSyncID   = "baton-snowflake-incremental"
+	incrementalStateKey = "state"
+
+	// maxObjectChanges is how many changed roles and tables a sync re-reads before it re-reads
+	// every grant instead.
+	maxObjectChanges = 10000
+
+	defaultFullSyncIntervalHours = 24
+)
+
+var incrementalStateNamespace = sessions.WithPrefix("incremental")
+
+// incrementalSyncState is what incrementalSync keeps between syncs: the time the next sync reads
+// ACCOUNT_USAGE changes from, and when every grant was last re-read.
+type incrementalSyncState struct {
+	Watermark  time.Time `json:"watermark"`
+	FullSyncAt time.Time `json:"full_sync_at"`
+}
+
+// incrementalSync keeps account role grantees and table grants in the session store between syncs.
+// At the start of each sync it drops those ACCOUNT_USAGE shows changed or dropped since the previous
+// one, so only they are read from Snowflake again. Every fullSyncInterval, and whenever the changes
+// cannot be read, it drops them all. Resources are still listed in full: a sync has to report every
+// one, and SHOW is cheap next to a SHOW GRANTS per role and table.
+type incrementalSync struct {
+	client           *snowflake.Client
+	fullSyncInterval time.Duration
+
+	mu             sync.Mutex
+	preparedSyncID string
+}
+
+func newIncrementalSync(client *snowflake.Client, fullSyncIntervalHours int) *incrementalSync {
+	if fullSyncIntervalHours <= 0 {
+		fullSyncIntervalHours = defaultFullSyncIntervalHours
+	}
+	return &incrementalSync{
+		client:           client,
+		fullSyncInterval: time.Duration(fullSyncIntervalHours) * time.Hour,
+	}
+}
+
+// store returns the session store grants are kept in between syncs, preparing it for the current
+// sync on first use, or nil if incremental sync is off.
+func (s *incrementalSync) store(ctx context.Context, opts rs.SyncOpAttrs) (sessions.SessionStore, error) {
+	if s == nil || opts.Session == nil {
+		return nil, nil
+	}
+	ss := persistentSessionStore{opts.Session}
+	if err := s.prepare(ctx, ss, opts.SyncID); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (s *incrementalSync) prepare(ctx context.Context, ss sessions.SessionStore, syncID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if syncID != "" && syncID == s.preparedSyncID {
+		return nil
+	}
+
+	l := ctxzap.Extract(ctx)
+	now := time.Now()
+	state, found, err := session.GetJSON[incrementalSyncState](ctx, ss, incrementalStateKey, incrementalStateNamespace)
+	if err != nil {
+		l.Warn("baton-snowflake: re-reading every grant: failed to read incremental sync state", zap.Error(err))
+		found = false
+	}
+
+	// Rows can land in ACCOUNT_USAGE up to ObjectChangesLatency late, so the next sync reads from
+	// that far back; re-reading a few objects twice is cheaper than missing one.
+	next := incrementalSyncState{Watermark: now.Add(-snowflake.ObjectChangesLatency), FullSyncAt: state.FullSyncAt}
+	full := !found || now.Sub(state.FullSyncAt) >= s.fullSyncInterval
+	if !full {
+		changed, err := s.forgetChanged(ctx, ss, state.Watermark)
+		if err != nil {
+			l.Warn("baton-snowflake: re-reading every grant: failed to read changes since the last sync", zap.Error(err))
+			full = true
+		} else {
+			l.Debug("incremental sync", zap.Time("since", state.Watermark), zap.Int("changed_objects", changed))
+		}
+	}
+	if full {
+		if err := ss.Clear(ctx); err != nil {
+			return fmt.Errorf("baton-snowflake: failed to clear incremental sync state: %w", err)
+		}
+		next.FullSyncAt = now
+	}
+
+	if err := session.SetJSON(ctx, ss, incrementalStateKey, next, incrementalStateNamespace); err != nil {
+		return fmt.Errorf("baton-snowflake: failed to save incremental sync state: %w", err)
+	}
+	s.preparedSyncID = syncID
+	return nil
+}
+
+// forgetChanged drops the kept grants of each role and table changed since, returning how many.
+func (s *incrementalSync) forgetChanged(ctx context.Context, ss sessions.SessionStore, since time.Time) (int, error) {
+	changes, _, err := s.client.ListObjectChanges(ctx, since, maxObjectChanges)
+	if err != nil {
+		return 0, err
+	}
+	for _, change := range changes {
+		switch change.ObjectType {
+		case snowflake.ObjectChangeRole:
+			err = s.client.ForgetAccountRoleGrantees(ctx, ss, change.Name)
+		case snowflake.ObjectChangeTable:
+			err = s.client.ForgetTableGrants(ctx, ss, change.DatabaseName, change.SchemaName, change.Name)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(changes), nil
+}
+
+// persistentSessionStore is a session store whose entries are kept between syncs, under
+// incrementalSyncID rather than the active sync's id.
+type persistentSessionStore struct {
+	ss sessions.SessionStore
+}
+
+func persistentOpts(opt []sessions.SessionStoreOption) []sessions.SessionStoreOption {
+	opts := make([]sessions.SessionStoreOption, 0, len(opt)+1)
+	return append(append(opts, opt...), sessions.WithSyncID(incrementalSyncID))
+}
+
+func (p persistentSessionStore) Get(ctx context.Context, key string, opt ...sessions.SessionStoreOption) ([]byte, bool, error) {
+	return p.ss.Get(ctx, key, persistentOpts(opt)...)
+}
+
+func (p persistentSessionStore) GetMany(ctx context.Context, keys []string, opt ...sessions.SessionStoreOption) (map[string][]byte, []string, error) {
+	return p.ss.GetMany(ctx, keys, persistentOpts(opt)...)
+}
+
+func (p persistentSessionStore) Set(ctx context.Context, key string, value []byte, opt ...sessions.SessionStoreOption) error {
+	return p.ss.Set(ctx, key, value, persistentOpts(opt)...)
+}
+
+func (p persistentSessionStore) SetMany(ctx context.Context, values map[string][]byte, opt ...sessions.SessionStoreOption) error {
+	return p.ss.SetMany(ctx, values, persistentOpts(opt)...)
+}
+
+func (p persistentSessionStore) Delete(ctx context.Context, key string, opt ...sessions.SessionStoreOption) error {
+	return p.ss.Delete(ctx, key, persistentOpts(opt)...)
+}
+
+func (p persistentSessionStore) Clear(ctx context.Context, opt ...sessions.SessionStoreOption) error {
+	return p.ss.Clear(ctx, persistentOpts(opt)...)
+}
+
+func (p persistentSessionStore) GetAll(ctx context.Context, pageToken string, opt ...sessions.SessionStoreOption) (map[string][]byte, string, error) {
+	return p.ss.GetAll(ctx, pageToken, persistentOpts(opt)...)
+}
@@ -0,0 +1,131 @@
+package connector
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/session"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySessionStore is an in-memory sessions.SessionStore that, like the c1z store, keeps each
+// sync id's entries apart.
+type memorySessionStore struct {
+	data map[string][]byte
+}
+
+func (m *memorySessionStore) key(key string, opt []sessions.SessionStoreOption) string {
+	bag := &sessions.SessionStoreBag{}
+	for _, o := range opt {
+		_ = o(context.Background(), bag)
+	}
+	return bag.SyncID + "/" + bag.Prefix + key
+}
+
+func (m *memorySessionStore) Get(_ context.Context, key string, opt ...sessions.SessionStoreOption) ([]byte, bool, error) {
+	v, ok := m.data[m.key(key, opt)]
+	return v, ok, nil
+}
+
+func (m *memorySessionStore) GetMany(ctx context.Context, keys []string, opt ...sessions.SessionStoreOption) (map[string][]byte, []string, error) {
+	found := map[string][]byte{}
+	for _, key := range keys {
+		if v, ok, _ := m.Get(ctx, key, opt...); ok {
+			found[key] = v
+		}
+	}
+	return found, nil, nil
+}
+
+func (m *memorySessionStore) Set(_ context.Context, key string, value []byte, opt ...sessions.SessionStoreOption) error {
+	m.data[m.key(key, opt)] = value
+	return nil
+}
+
+func (m *memorySessionStore) SetMany(ctx context.Context, values map[string][]byte, opt ...sessions.SessionStoreOption) error {
+	for key, value := range values {
+		_ = m.Set(ctx, key, value, opt...)
+	}
+	return nil
+}
+
+func (m *memorySessionStore) Delete(_ context.Context, key string, opt ...sessions.SessionStoreOption) error {
+	delete(m.data, m.key(key, opt))
+	return nil
+}
+
+func (m *memorySessionStore) Clear(_ context.Context, opt ...sessions.SessionStoreOption) error {
+	prefix := m.key("", opt)
+	for key := range m.data {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.data, key)
+		}
+	}
+	return nil
+}
+
+func (m *memorySessionStore) GetAll(_ context.Context, _ string, _ ...sessions.SessionStoreOption) (map[string][]byte, string, error) {
+	return m.data, "", nil
+}
+
+func syncOpAttrs(store sessions.SessionStore, syncID string) rs.SyncOpAttrs {
+	return rs.SyncOpAttrs{SyncID: syncID, Session: connectorbuilder.WithSyncId(store, syncID)}
+}
+
+func TestIncrementalSync_ForgetsChangedObjects(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t,
+		[]string{"OBJECT_TYPE", "DATABASE_NAME", "SCHEMA_NAME", "NAME"},
+		[][]string{{"ROLE", "", "", "ANALYST"}},
+		&statements,
+	)
+	defer server.Close()
+
+	ctx := context.Background()
+	store := &memorySessionStore{data: map[string][]byte{}}
+	incremental := newIncrementalSync(newTestConnector(t, server.URL).Client, 24)
+	grantees := sessions.WithPrefix("account_role_grantees")
+
+	// The first sync has nothing to build on, so it reads every grant.
+	ss, err := incremental.store(ctx, syncOpAttrs(store, "sync-1"))
+	require.NoError(t, err)
+	assert.Empty(t, statements)
+	require.NoError(t, ss.Set(ctx, "ANALYST", []byte("[]"), grantees))
+	require.NoError(t, ss.Set(ctx, "SYSADMIN", []byte("[]"), grantees))
+	_, err = incremental.store(ctx, syncOpAttrs(store, "sync-1"))
+	require.NoError(t, err)
+	assert.Empty(t, statements, "a sync is prepared once")
+
+	// The next sync drops only what ACCOUNT_USAGE shows changed.
+	ss, err = incremental.store(ctx, syncOpAttrs(store, "sync-2"))
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+	_, found, _ := ss.Get(ctx, "ANALYST", grantees)
+	assert.False(t, found)
+	_, found, _ = ss.Get(ctx, "SYSADMIN", grantees)
+	assert.True(t, found)
+
+	// Once the full sync interval has passed, every grant is read again.
+	state, _, err := session.GetJSON[incrementalSyncState](ctx, ss, incrementalStateKey, incrementalStateNamespace)
+	require.NoError(t, err)
+	state.FullSyncAt = state.FullSyncAt.Add(-25 * time.Hour)
+	require.NoError(t, session.SetJSON(ctx, ss, incrementalStateKey, state, incrementalStateNamespace))
+	ss, err = incremental.store(ctx, syncOpAttrs(store, "sync-3"))
+	require.NoError(t, err)
+	assert.Len(t, statements, 1)
+	_, found, _ = ss.Get(ctx, "SYSADMIN", grantees)
+	assert.False(t, found)
+}
+
+func TestIncrementalSync_OffWithoutConfig(t *testing.T) {
+	var incremental *incrementalSync
+	ss, err := incremental.store(context.Background(), syncOpAttrs(&memorySessionStore{data: map[string][]byte{}}, "sync-1"))
+	require.NoError(t, err)
+	assert.Nil(t, ss)
+}
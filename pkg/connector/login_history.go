@@ -0,0 +1,99 @@
+package connector
+
+import (
+	"context"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const loginHistoryFeedID = "login_history"
+
+// loginHistoryFeed emits a usage event for every login attempt in
+// SNOWFLAKE.ACCOUNT_USAGE.LOGIN_HISTORY, failed ones included.
+type loginHistoryFeed struct {
+	client *snowflake.Client
+}
+
+func (f *loginHistoryFeed) EventFeedMetadata(ctx context.Context) *v2.EventFeedMetadata {
+	return &v2.EventFeedMetadata{
+		Id:                  loginHistoryFeedID,
+		SupportedEventTypes: []v2.EventType{v2.EventType_EVENT_TYPE_USAGE},
+	}
+}
+
+func (f *loginHistoryFeed) ListEvents(
+	ctx context.Context,
+	earliestEvent *timestamppb.Timestamp,
+	pToken *pagination.StreamToken,
+) ([]*v2.Event, *pagination.StreamState, annotations.Annotations, error) {
+	position, err := parseEventCursor(pToken.Cursor)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	r, size := eventRange(earliestEvent, position, snowflake.LoginHistoryLatency, pToken.Size)
+
+	logins, _, err := f.client.ListLoginHistory(ctx, r, size)
+	if err != nil {
+		return nil, nil, nil, wrapError(err, "failed to list login history")
+	}
+
+	events := make([]*v2.Event, 0, len(logins))
+	var last *snowflake.AccountUsagePosition
+	for i := range logins {
+		event, err := loginEvent(&logins[i])
+		if err != nil {
+			return nil, nil, nil, wrapError(err, "failed to create login event")
+		}
+		events = append(events, event)
+		last = logins[i].Position()
+	}
+
+	cursor, err := nextEventCursor(pToken.Cursor, last)
+	if err != nil {
+		return nil, nil, nil, wrapError(err, "failed to create login history cursor")
+	}
+
+	return events, &pagination.StreamState{Cursor: cursor, HasMore: len(logins) == size}, nil, nil
+}
+
+// loginEvent is a usage of the user's account by the user. How they authenticated and whether it
+// worked are attached as a struct annotation.
+func loginEvent(login *snowflake.LoginEvent) (*v2.Event, error) {
+	occurredAt, err := snowflake.ParseAccountUsageTime(login.EventTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := structpb.NewStruct(map[string]interface{}{
+		"client_ip":                    login.ClientIP,
+		"client_type":                  login.ReportedClientType,
+		"first_authentication_factor":  login.FirstAuthenticationFactor,
+		"second_authentication_factor": login.SecondAuthenticationFactor,
+		"success":                      login.Succeeded(),
+		"error_code":                   login.ErrorCode,
+		"error_message":                login.ErrorMessage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user := eventUserResource(login.UserName)
+	return &v2.Event{
+		Id:         loginHistoryFeedID + ":" + login.EventID,
+		OccurredAt: timestamppb.New(occurredAt),
+		Event: &v2.Event_UsageEvent{UsageEvent: &v2.UsageEvent{
+			TargetResource: user,
+			ActorResource:  user,
+		}},
+		Annotations: annotations.New(details),
+	}, nil
+}
+
+func newLoginHistoryFeed(client *snowflake.Client) *loginHistoryFeed {
+	return &loginHistoryFeed{client: client}
+}
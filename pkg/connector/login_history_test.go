@@ -0,0 +1,72 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestLoginHistoryFeed_ListEvents(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t,
+		[]string{
+			"EVENT_ID", "EVENT_TIMESTAMP", "USER_NAME", "CLIENT_IP", "REPORTED_CLIENT_TYPE", "FIRST_AUTHENTICATION_FACTOR",
+			"SECOND_AUTHENTICATION_FACTOR", "IS_SUCCESS", "ERROR_CODE", "ERROR_MESSAGE",
+		},
+		[][]string{
+			{"1", "2025-01-02 03:04:05.000000000 +00:00", "JDOE", "10.0.0.1", "SNOWFLAKE_UI", "PASSWORD", "DUO", "YES", "", ""},
+			{"2", "2025-01-02 03:04:06.000000000 +00:00", "SVC_ETL", "10.0.0.2", "JDBC_DRIVER", "PASSWORD", "", "NO", "390100", "INCORRECT_USERNAME_PASSWORD"},
+		},
+		&statements,
+	)
+	defer server.Close()
+
+	feed := newLoginHistoryFeed(newTestConnector(t, server.URL).Client)
+	events, state, _, err := feed.ListEvents(context.Background(), nil, &pagination.StreamToken{Size: 2})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.True(t, state.HasMore)
+
+	failed := events[1]
+	assert.Equal(t, "login_history:2", failed.GetId())
+	assert.Equal(t, "SVC_ETL", failed.GetUsageEvent().GetActorResource().GetId().GetResource())
+	assert.Equal(t, userResourceType.Id, failed.GetUsageEvent().GetTargetResource().GetId().GetResourceType())
+
+	details := &structpb.Struct{}
+	require.Len(t, failed.GetAnnotations(), 1)
+	require.NoError(t, failed.GetAnnotations()[0].UnmarshalTo(details))
+	assert.Equal(t, false, details.GetFields()["success"].GetBoolValue())
+	assert.Equal(t, "390100", details.GetFields()["error_code"].GetStringValue())
+	assert.Equal(t, "PASSWORD", details.GetFields()["first_authentication_factor"].GetStringValue())
+
+	position, err := parseEventCursor(state.Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, "2", position.ID)
+
+	_, _, _, err = feed.ListEvents(context.Background(), nil, &pagination.StreamToken{Cursor: state.Cursor})
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+	assert.Contains(t, statements[1], "h.EVENT_ID > '2'")
+	assert.Contains(t, statements[1], "LIMIT 100;")
+	assert.Contains(t, statements[1], "AND h.EVENT_TIMESTAMP < ", "rows LOGIN_HISTORY may still fill in are left for later")
+}
+
+func TestEventFeeds_MetadataIsValid(t *testing.T) {
+	c := &Connector{}
+	ids := map[string]bool{}
+	for _, feed := range c.EventFeeds(context.Background()) {
+		metadata := feed.EventFeedMetadata(context.Background())
+		require.NoError(t, metadata.Validate())
+		require.False(t, ids[metadata.GetId()], metadata.GetId())
+		ids[metadata.GetId()] = true
+		require.NotEmpty(t, metadata.GetSupportedEventTypes())
+		for _, eventType := range metadata.GetSupportedEventTypes() {
+			require.NotEqual(t, v2.EventType_EVENT_TYPE_UNSPECIFIED, eventType)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package connector
+
+import (
+	"context"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+const (
+	profileKeyNetworkPolicy          = "network_policy"
+	profileKeyEffectiveNetworkPolicy = "effective_network_policy"
+)
+
+type networkPolicyBuilder struct {
+	client *snowflake.Client
+}
+
+func (o *networkPolicyBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return networkPolicyResourceType
+}
+
+func networkPolicyResource(policy *snowflake.NetworkPolicy, description *snowflake.NetworkPolicyDescription, isAccountPolicy bool) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		profileKeyName:      policy.Name,
+		profileKeyComment:   policy.Comment,
+		"is_account_policy": isAccountPolicy,
+	}
+	if description != nil {
+		profile["allowed_ip_list"] = stringListProfileValue(description.AllowedIPList)
+		profile["blocked_ip_list"] = stringListProfileValue(description.BlockedIPList)
+		profile["allowed_network_rules"] = stringListProfileValue(description.AllowedNetworkRules)
+		profile["blocked_network_rules"] = stringListProfileValue(description.BlockedNetworkRules)
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithResourceProfile(profile),
+	}
+	if !policy.CreatedOn.IsZero() {
+		opts = append(opts, rs.WithResourceCreatedAt(policy.CreatedOn))
+	}
+
+	return rs.NewResource(policy.Name, networkPolicyResourceType, policy.Name, opts...)
+}
+
+// accountNetworkPolicy returns the network policy set on the account, or "" when none is set
+// or the connector's role cannot read account parameters.
+func accountNetworkPolicy(ctx context.Context, client *snowflake.Client) (string, error) {
+	policy, err := client.GetAccountParameter(ctx, snowflake.ParameterNetworkPolicy)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			ctxzap.Extract(ctx).Debug("cannot read account network policy: insufficient privileges", zap.Error(err))
+			return "", nil
+		}
+		return "", err
+	}
+	return policy, nil
+}
+
+func (o *networkPolicyBuilder) List(ctx context.Context, _ *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	l := ctxzap.Extract(ctx)
+
+	policies, err := o.client.ListNetworkPolicies(ctx)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			l.Debug("ListNetworkPolicies: insufficient privileges, skipping", zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list network policies")
+	}
+
+	accountPolicy, err := accountNetworkPolicy(ctx, o.client)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to get account network policy")
+	}
+
+	var resources []*v2.Resource
+	for _, policy := range policies {
+		description, err := o.client.DescribeNetworkPolicy(ctx, policy.Name)
+		if err != nil {
+			if !snowflake.IsInsufficientPrivileges(err) {
+				return nil, nil, wrapError(err, "failed to describe network policy")
+			}
+			l.Debug("DescribeNetworkPolicy: insufficient privileges, syncing without lists",
+				zap.String("network_policy", policy.Name), zap.Error(err))
+		}
+
+		resource, err := networkPolicyResource(&policy, description, policy.Name == accountPolicy) // #nosec G601
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create network policy resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *networkPolicyBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+func (o *networkPolicyBuilder) Grants(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+func newNetworkPolicyBuilder(client *snowflake.Client) *networkPolicyBuilder {
+	return &networkPolicyBuilder{
+		client: client,
+	}
+}
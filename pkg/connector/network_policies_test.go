@@ -0,0 +1,64 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+func TestEffectiveNetworkPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		userPolicy    string
+		accountPolicy string
+		want          string
+	}{
+		{name: "user policy overrides account", userPolicy: "SVC_ONLY", accountPolicy: "CORP", want: "SVC_ONLY"},
+		{name: "falls back to account", userPolicy: "", accountPolicy: "CORP", want: "CORP"},
+		{name: "unrestricted", userPolicy: "", accountPolicy: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := effectiveNetworkPolicy(tt.userPolicy, tt.accountPolicy); got != tt.want {
+				t.Errorf("effectiveNetworkPolicy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetworkPolicyResource_Profile(t *testing.T) {
+	resource, err := networkPolicyResource(
+		&snowflake.NetworkPolicy{Name: "CORP"},
+		&snowflake.NetworkPolicyDescription{
+			AllowedIPList:       []string{"10.0.0.0/8"},
+			AllowedNetworkRules: []string{"SECURITY.PUBLIC.CORP_VPN"},
+		},
+		true,
+	)
+	require.NoError(t, err)
+
+	profile := resource.GetProfile().AsMap()
+	require.Equal(t, true, profile["is_account_policy"])
+	require.Equal(t, []interface{}{"10.0.0.0/8"}, profile["allowed_ip_list"])
+	require.Equal(t, []interface{}{}, profile["blocked_ip_list"])
+	require.Equal(t, []interface{}{"SECURITY.PUBLIC.CORP_VPN"}, profile["allowed_network_rules"])
+}
+
+// TestNetworkRuleResource_IDMatchesPolicyReference checks the rule resource ID uses the same
+// DB.SCHEMA.NAME form network policy profiles list rules by.
+func TestNetworkRuleResource_IDMatchesPolicyReference(t *testing.T) {
+	resource, err := networkRuleResource(&snowflake.NetworkRule{
+		Name:         "CORP_VPN",
+		DatabaseName: "SECURITY",
+		SchemaName:   "PUBLIC",
+		Type:         "IPV4",
+		Mode:         "INGRESS",
+	}, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	require.Equal(t, "SECURITY.PUBLIC.CORP_VPN", resource.GetId().GetResource())
+	require.Equal(t, []interface{}{"10.0.0.0/8"}, resource.GetProfile().AsMap()["value_list"])
+}
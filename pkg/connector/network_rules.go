@@ -0,0 +1,91 @@
+package connector
+
+import (
+	"context"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+type networkRuleBuilder struct {
+	client *snowflake.Client
+}
+
+func (o *networkRuleBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return networkRuleResourceType
+}
+
+// networkRuleResource IDs the rule by DB.SCHEMA.NAME, the form network policy profiles use in
+// allowed_network_rules / blocked_network_rules.
+func networkRuleResource(rule *snowflake.NetworkRule, valueList []string) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		profileKeyName:    rule.Name,
+		"database":        rule.DatabaseName,
+		"schema":          rule.SchemaName,
+		"owner":           rule.Owner,
+		"type":            rule.Type,
+		"mode":            rule.Mode,
+		"value_list":      stringListProfileValue(valueList),
+		profileKeyComment: rule.Comment,
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithResourceProfile(profile),
+	}
+	if !rule.CreatedOn.IsZero() {
+		opts = append(opts, rs.WithResourceCreatedAt(rule.CreatedOn))
+	}
+
+	return rs.NewResource(rule.Name, networkRuleResourceType, rule.FullyQualifiedName(), opts...)
+}
+
+func (o *networkRuleBuilder) List(ctx context.Context, _ *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	l := ctxzap.Extract(ctx)
+
+	rules, err := o.client.ListNetworkRules(ctx)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			l.Debug("ListNetworkRules: insufficient privileges, skipping", zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list network rules")
+	}
+
+	var resources []*v2.Resource
+	for _, rule := range rules {
+		valueList, err := o.client.DescribeNetworkRule(ctx, rule.DatabaseName, rule.SchemaName, rule.Name)
+		if err != nil {
+			if !snowflake.IsInsufficientPrivileges(err) && !snowflake.IsSharedDatabaseUnavailable(err) {
+				return nil, nil, wrapError(err, "failed to describe network rule")
+			}
+			l.Debug("DescribeNetworkRule: rule not readable, syncing without value list",
+				zap.String("network_rule", rule.FullyQualifiedName()), zap.Error(err))
+		}
+
+		resource, err := networkRuleResource(&rule, valueList) // #nosec G601
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create network rule resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *networkRuleBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+func (o *networkRuleBuilder) Grants(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+func newNetworkRuleBuilder(client *snowflake.Client) *networkRuleBuilder {
+	return &networkRuleBuilder{
+		client: client,
+	}
+}
@@ -0,0 +1,121 @@
+package connector
+
+import (
+	"regexp"
+	"strings"
+)
+
+// objectFilter selects the databases, schemas and tables the connector syncs. Each level has an
+// include list and an exclude list of case-insensitive glob patterns: * and % match any run of
+// characters and ? matches one. The database exclude list predates the patterns, so its entries
+// are matched as whole names. A name is synced when it matches an include pattern, or there are
+// none, and matches no exclude pattern. A nil filter syncs everything.
+type objectFilter struct {
+	includeDatabases []*regexp.Regexp
+	excludeDatabases []*regexp.Regexp
+	includeSchemas   []*regexp.Regexp
+	excludeSchemas   []*regexp.Regexp
+	includeTables    []*regexp.Regexp
+	excludeTables    []*regexp.Regexp
+}
+
+// objectFilterConfig holds the patterns for newObjectFilter, as given in the config.
+type objectFilterConfig struct {
+	IncludedDatabases []string
+	ExcludedDatabases []string
+	IncludedSchemas   []string
+	ExcludedSchemas   []string
+	IncludedTables    []string
+	ExcludedTables    []string
+}
+
+func newObjectFilter(cfg objectFilterConfig) *objectFilter {
+	return &objectFilter{
+		includeDatabases: compileGlobs(cfg.IncludedDatabases),
+		excludeDatabases: compileNames(cfg.ExcludedDatabases),
+		includeSchemas:   compileGlobs(cfg.IncludedSchemas),
+		excludeSchemas:   compileGlobs(cfg.ExcludedSchemas),
+		includeTables:    compileGlobs(cfg.IncludedTables),
+		excludeTables:    compileGlobs(cfg.ExcludedTables),
+	}
+}
+
+// includesDatabase reports whether the database is synced.
+func (f *objectFilter) includesDatabase(name string) bool {
+	if f == nil {
+		return true
+	}
+	return selected(name, f.includeDatabases, f.excludeDatabases)
+}
+
+// includesSchema reports whether the schema's tables are synced. It applies in every database.
+func (f *objectFilter) includesSchema(name string) bool {
+	if f == nil {
+		return true
+	}
+	return selected(name, f.includeSchemas, f.excludeSchemas)
+}
+
+// includesTable reports whether the table or view is synced. It applies in every schema.
+func (f *objectFilter) includesTable(name string) bool {
+	if f == nil {
+		return true
+	}
+	return selected(name, f.includeTables, f.excludeTables)
+}
+
+func selected(name string, include, exclude []*regexp.Regexp) bool {
+	if len(include) > 0 && !matchesAny(name, include) {
+		return false
+	}
+	return !matchesAny(name, exclude)
+}
+
+func matchesAny(name string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileNames compiles each non-blank name to an anchored, case-insensitive regexp matching only
+// that name.
+func compileNames(names []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		compiled = append(compiled, regexp.MustCompile("(?is)^"+regexp.QuoteMeta(name)+"$"))
+	}
+	return compiled
+}
+
+// compileGlobs compiles each non-blank pattern to an anchored, case-insensitive regexp.
+func compileGlobs(patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		var expr strings.Builder
+		expr.WriteString("(?is)^")
+		for _, r := range pattern {
+			switch r {
+			case '*', '%':
+				expr.WriteString(".*")
+			case '?':
+				expr.WriteString(".")
+			default:
+				expr.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		expr.WriteString("$")
+		compiled = append(compiled, regexp.MustCompile(expr.String()))
+	}
+	return compiled
+}
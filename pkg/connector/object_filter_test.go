@@ -0,0 +1,47 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectFilter(t *testing.T) {
+	filter := newObjectFilter(objectFilterConfig{
+		IncludedDatabases: []string{"PROD_*"},
+		ExcludedDatabases: []string{"prod_legacy", "PROD_?"},
+		ExcludedSchemas:   []string{"*_SCRATCH"},
+		IncludedTables:    []string{"*"},
+		ExcludedTables:    []string{"TMP_%", "  ", "BAK_?"},
+	})
+
+	tests := []struct {
+		name     string
+		included bool
+		got      bool
+	}{
+		{"included database", true, filter.includesDatabase("PROD_SALES")},
+		{"include patterns ignore case", true, filter.includesDatabase("prod_sales")},
+		{"database outside include list", false, filter.includesDatabase("DEV_SALES")},
+		{"exclude wins over include", false, filter.includesDatabase("PROD_LEGACY")},
+		{"excluded databases are names, not patterns", true, filter.includesDatabase("PROD_A")},
+		{"excluded database names can hold wildcard characters", false, filter.includesDatabase("prod_?")},
+		{"schemas without include list", true, filter.includesSchema("PUBLIC")},
+		{"excluded schema", false, filter.includesSchema("DEV_SCRATCH")},
+		{"% matches any characters", false, filter.includesTable("TMP_LOAD")},
+		{"? matches one character", false, filter.includesTable("BAK_1")},
+		{"? does not match two", true, filter.includesTable("BAK_12")},
+		{"_ is not a wildcard", true, filter.includesTable("TMPXLOAD")},
+		{"blank patterns are ignored", true, filter.includesTable("ORDERS")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.included, tt.got)
+		})
+	}
+
+	var unfiltered *objectFilter
+	assert.True(t, unfiltered.includesDatabase("ANY"))
+	assert.True(t, unfiltered.includesSchema("ANY"))
+	assert.True(t, unfiltered.includesTable("ANY"))
+}
@@ -0,0 +1,1275 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+// accountIDSeparator separates the account locator from the account's own id in the resource ids
+// organization sync namespaces: LOCATOR/NAME.
+const accountIDSeparator = "/"
+
+// childResourceTypeIDs are the resource types listed under a parent within an account (tables
+// under databases, keys and tokens under users). Every other account-scoped type is listed under
+// the account itself.
+var childResourceTypeIDs = map[string]bool{
+	tableResourceType.Id:                   true,
+	secretResourceType.Id:                  true,
+	rsaPublicKeyResourceType.Id:            true,
+	programmaticAccessTokenResourceType.Id: true,
+	applicationRoleResourceType.Id:         true,
+}
+
+// organizationWideResourceTypeIDs are the resource types that describe the organization rather
+// than one account. They are not wrapped per account.
+var organizationWideResourceTypeIDs = map[string]bool{
+	licenseResourceType.Id:               true,
+	organizationUserResourceType.Id:      true,
+	organizationUserGroupResourceType.Id: true,
+}
+
+// accountCredentials is a key pair organization sync authenticates to an account with.
+type accountCredentials struct {
+	userIdentifier string
+	privateKey     any
+}
+
+// parseAccountCredentials parses organization-account-credentials entries, each
+// ACCOUNT=USER:PRIVATE_KEY_PATH, into credentials by uppercase account name or locator.
+func parseAccountCredentials(entries []string) (map[string]accountCredentials, error) {
+	credentials := make(map[string]accountCredentials, len(entries))
+	for _, entry := range entries {
+		account, rest, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("baton-snowflake: invalid organization account credentials %q: want ACCOUNT=USER:PRIVATE_KEY_PATH", entry)
+		}
+		user, keyPath, ok := strings.Cut(rest, ":")
+		account, user, keyPath = strings.TrimSpace(account), strings.TrimSpace(user), strings.TrimSpace(keyPath)
+		if !ok || account == "" || user == "" || keyPath == "" {
+			return nil, fmt.Errorf("baton-snowflake: invalid organization account credentials %q: want ACCOUNT=USER:PRIVATE_KEY_PATH", entry)
+		}
+		privateKey, err := snowflake.ReadPrivateKey(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("baton-snowflake: read private key for account %s: %w", account, err)
+		}
+		credentials[strings.ToUpper(account)] = accountCredentials{userIdentifier: user, privateKey: privateKey}
+	}
+	return credentials, nil
+}
+
+// organization syncs every account in the Snowflake organization. The configured account lists
+// them with SHOW ORGANIZATION ACCOUNTS; each account is then read by its own set of builders, with
+// a client authenticated by the account's credentials in the config or, without them, by the
+// configured user and key, for a service user set up the same way in every account.
+type organization struct {
+	client      *snowflake.Client
+	httpClient  *http.Client
+	shared      accountCredentials
+	credentials map[string]accountCredentials
+	// syncers returns the builders that read one account through client.
+	syncers func(ctx context.Context, client *snowflake.Client) []connectorbuilder.ResourceSyncerV2
+
+	mu       sync.Mutex
+	accounts map[string]snowflake.OrganizationAccount
+	clients  map[string]*snowflake.Client
+	scoped   map[string]map[string]connectorbuilder.ResourceSyncerV2
+}
+
+func newOrganization(
+	client *snowflake.Client,
+	httpClient *http.Client,
+	shared accountCredentials,
+	credentials map[string]accountCredentials,
+	syncers func(ctx context.Context, client *snowflake.Client) []connectorbuilder.ResourceSyncerV2,
+) *organization {
+	return &organization{
+		client:      client,
+		httpClient:  httpClient,
+		shared:      shared,
+		credentials: credentials,
+		syncers:     syncers,
+		clients:     map[string]*snowflake.Client{},
+		scoped:      map[string]map[string]connectorbuilder.ResourceSyncerV2{},
+	}
+}
+
+// resourceSyncers wraps the builders for a single account, which only supply the resource types:
+// the account type lists the organization's accounts, organization-wide types are synced once
+// through the configured account, and every other type is synced in each account.
+func (o *organization) resourceSyncers(ctx context.Context, builders []connectorbuilder.ResourceSyncerV2) []connectorbuilder.ResourceSyncerV2 {
+	accounts := &accountBuilder{org: o}
+	syncers := []connectorbuilder.ResourceSyncerV2{accounts}
+	for _, builder := range builders {
+		resourceType := builder.ResourceType(ctx)
+		if resourceType.Id == accountResourceType.Id {
+			// accounts lists every account, the connected one included.
+			continue
+		}
+		if organizationWideResourceTypeIDs[resourceType.Id] {
+			syncers = append(syncers, builder)
+			continue
+		}
+		topLevel := !childResourceTypeIDs[resourceType.Id]
+		if topLevel {
+			accounts.childResourceTypes = append(accounts.childResourceTypes, resourceType)
+		}
+		scoped := &accountScopedBuilder{resourceType: resourceType, org: o, topLevel: topLevel}
+		syncers = append(syncers, accountScopedSyncer(scoped, builder))
+	}
+	return syncers
+}
+
+// listAccounts lists the organization's accounts, remembering them for the account builders.
+func (o *organization) listAccounts(ctx context.Context) ([]snowflake.OrganizationAccount, error) {
+	accounts, _, err := o.client.ListOrganizationAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.accounts = make(map[string]snowflake.OrganizationAccount, len(accounts))
+	for _, account := range accounts {
+		o.accounts[account.AccountLocator] = account
+	}
+	return accounts, nil
+}
+
+// accountLocator returns the locator of the organization's account with the name or locator,
+// matched case-insensitively.
+func (o *organization) accountLocator(ctx context.Context, account string) (string, error) {
+	find := func() (string, bool) {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		for locator, known := range o.accounts {
+			if strings.EqualFold(known.AccountName, account) || strings.EqualFold(locator, account) {
+				return locator, true
+			}
+		}
+		return "", false
+	}
+
+	if locator, ok := find(); ok {
+		return locator, nil
+	}
+	if _, err := o.listAccounts(ctx); err != nil {
+		return "", wrapError(err, "failed to list organization accounts")
+	}
+	if locator, ok := find(); ok {
+		return locator, nil
+	}
+	return "", status.Errorf(codes.NotFound, "baton-snowflake: account %s is not in the organization", account)
+}
+
+// syncer returns the builder for the resource type in the account with the locator, creating the
+// account's builders on first use.
+func (o *organization) syncer(ctx context.Context, locator, resourceTypeID string) (connectorbuilder.ResourceSyncerV2, error) {
+	o.mu.Lock()
+	syncers, ok := o.scoped[locator]
+	o.mu.Unlock()
+
+	if !ok {
+		client, err := o.scopedClient(ctx, locator)
+		if err != nil {
+			return nil, err
+		}
+		syncers = map[string]connectorbuilder.ResourceSyncerV2{}
+		for _, syncer := range o.syncers(ctx, client) {
+			syncers[syncer.ResourceType(ctx).Id] = syncer
+		}
+
+		o.mu.Lock()
+		o.scoped[locator] = syncers
+		o.mu.Unlock()
+	}
+
+	syncer, ok := syncers[resourceTypeID]
+	if !ok {
+		return nil, fmt.Errorf("baton-snowflake: resource type %s is not synced", resourceTypeID)
+	}
+	return syncer, nil
+}
+
+// scopedClient returns the client for the account with the locator, creating it on first use.
+func (o *organization) scopedClient(ctx context.Context, locator string) (*snowflake.Client, error) {
+	o.mu.Lock()
+	client, ok := o.clients[locator]
+	account, known := o.accounts[locator]
+	o.mu.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	// A sync resumed in a new process has not listed the accounts yet.
+	if !known {
+		if _, err := o.listAccounts(ctx); err != nil {
+			return nil, wrapError(err, "failed to list organization accounts")
+		}
+		o.mu.Lock()
+		account, known = o.accounts[locator]
+		o.mu.Unlock()
+		if !known {
+			return nil, status.Errorf(codes.NotFound, "baton-snowflake: account %s is not in the organization", locator)
+		}
+	}
+
+	client, err := o.accountClient(ctx, &account)
+	if err != nil {
+		return nil, wrapError(err, fmt.Sprintf("failed to create client for account %s", locator))
+	}
+
+	o.mu.Lock()
+	o.clients[locator] = client
+	o.mu.Unlock()
+	return client, nil
+}
+
+// accountClient returns a client for the account, authenticated with its credentials.
+func (o *organization) accountClient(ctx context.Context, account *snowflake.OrganizationAccount) (*snowflake.Client, error) {
+	credentials, ok := o.credentials[strings.ToUpper(account.AccountName)]
+	if !ok {
+		credentials, ok = o.credentials[strings.ToUpper(account.AccountLocator)]
+	}
+	if !ok {
+		credentials = o.shared
+	}
+
+	return newClient(ctx, o.httpClient, account.AccountURL, snowflake.JWTConfig{
+		AccountIdentifier: account.AccountIdentifier(),
+		UserIdentifier:    credentials.userIdentifier,
+		PrivateKeyValue:   credentials.privateKey,
+	})
+}
+
+// accountBuilder lists the organization's accounts. Every account-scoped resource type that is
+// not a child of another is listed under them.
+type accountBuilder struct {
+	org                *organization
+	childResourceTypes []*v2.ResourceType
+}
+
+func (o *accountBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return accountResourceType
+}
+
+func accountResource(account *snowflake.OrganizationAccount, childResourceTypes []*v2.ResourceType) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		profileKeyName:      account.AccountName,
+		"organization_name": account.OrganizationName,
+		"account_locator":   account.AccountLocator,
+		"account_url":       account.AccountURL,
+		"edition":           account.Edition,
+		"snowflake_region":  account.SnowflakeRegion,
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithResourceProfile(profile),
+	}
+	for _, resourceType := range childResourceTypes {
+		opts = append(opts, rs.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: resourceType.Id}))
+	}
+
+	return rs.NewResource(account.AccountName, accountResourceType, account.AccountLocator, opts...)
+}
+
+func (o *accountBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	if parentResourceID != nil {
+		return nil, &rs.SyncOpResults{}, nil
+	}
+
+	accounts, err := o.org.listAccounts(ctx)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to list organization accounts")
+	}
+
+	var resources []*v2.Resource
+	for i := range accounts {
+		resource, err := accountResource(&accounts[i], o.childResourceTypes)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create account resource")
+		}
+		resources = append(resources, resource)
+	}
+	return resources, &rs.SyncOpResults{}, nil
+}
+
+func (o *accountBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return nil, &rs.SyncOpResults{}, nil
+}
+
+func (o *accountBuilder) Grants(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	return nil, &rs.SyncOpResults{}, nil
+}
+
+// currentAccountBuilder lists the connected account, the principal of grants that attach an object
+// to the account itself. Organization sync lists every account with accountBuilder instead.
+type currentAccountBuilder struct {
+	client *snowflake.Client
+}
+
+func (o *currentAccountBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return accountResourceType
+}
+
+func (o *currentAccountBuilder) List(ctx context.Context, _ *v2.ResourceId, opts rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	account, err := o.client.GetCurrentAccount(ctx, opts.Session)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to get current account")
+	}
+
+	resource, err := accountResource(account, nil)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to create account resource")
+	}
+	return []*v2.Resource{resource}, &rs.SyncOpResults{}, nil
+}
+
+func (o *currentAccountBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return nil, &rs.SyncOpResults{}, nil
+}
+
+func (o *currentAccountBuilder) Grants(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	return nil, &rs.SyncOpResults{}, nil
+}
+
+func newCurrentAccountBuilder(client *snowflake.Client) *currentAccountBuilder {
+	return &currentAccountBuilder{
+		client: client,
+	}
+}
+
+// accountPrincipalID is the id of the account with the locator as a grant principal. Account ids
+// are locators in both single-account and organization sync, so they are never scoped.
+func accountPrincipalID(locator string) *v2.ResourceId {
+	return &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: locator}
+}
+
+// accountScopedBuilder syncs a resource type in every account of the organization, through each
+// account's own builder. Resource ids are prefixed with the account locator, so the same name in
+// two accounts is two resources, and entitlement and grant ids follow. Resources of top-level
+// types are listed under their account.
+type accountScopedBuilder struct {
+	resourceType *v2.ResourceType
+	org          *organization
+	topLevel     bool
+}
+
+func (o *accountScopedBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// accountScoped returns the builder, which composeAccountScoped's syncers promote from the
+// builder they embed.
+func (o *accountScopedBuilder) accountScoped() *accountScopedBuilder {
+	return o
+}
+
+func (o *accountScopedBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, opts rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	if parentResourceID == nil || (parentResourceID.ResourceType == accountResourceType.Id) != o.topLevel {
+		return nil, &rs.SyncOpResults{}, nil
+	}
+
+	locator := parentResourceID.Resource
+	var parent *v2.ResourceId
+	if !o.topLevel {
+		var err error
+		locator, parent, err = unscopeResourceID(parentResourceID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	resources, results, err := syncer.List(ctx, parent, accountSyncOpAttrs(locator, opts))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, resource := range resources {
+		resources[i] = scopeResource(locator, resource)
+		if resources[i].ParentResourceId == nil {
+			resources[i].ParentResourceId = parentResourceID
+		}
+	}
+	return resources, results, nil
+}
+
+func (o *accountScopedBuilder) Entitlements(ctx context.Context, resource *v2.Resource, opts rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	locator, inner, err := unscopeResource(resource)
+	if err != nil {
+		return nil, nil, err
+	}
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entitlements, results, err := syncer.Entitlements(ctx, inner, accountSyncOpAttrs(locator, opts))
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, entitlement := range entitlements {
+		entitlements[i] = scopeEntitlement(locator, entitlement)
+	}
+	return entitlements, results, nil
+}
+
+func (o *accountScopedBuilder) Grants(ctx context.Context, resource *v2.Resource, opts rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	locator, inner, err := unscopeResource(resource)
+	if err != nil {
+		return nil, nil, err
+	}
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	grants, results, err := syncer.Grants(ctx, inner, accountSyncOpAttrs(locator, opts))
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, grant := range grants {
+		grants[i], err = scopeGrant(locator, grant)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to scope grant to account")
+		}
+	}
+	return grants, results, nil
+}
+
+// Get returns the resource from the account's builder, for the types that support targeted sync.
+func (o *accountScopedBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, parentResourceId *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	locator, inner, err := unscopeResourceID(resourceId)
+	if err != nil {
+		return nil, nil, err
+	}
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	getter, ok := syncer.(connectorbuilder.ResourceTargetedSyncerLimited)
+	if !ok {
+		return nil, nil, status.Errorf(codes.Unimplemented, "baton-snowflake: %s does not support targeted sync", o.resourceType.Id)
+	}
+
+	var parent *v2.ResourceId
+	if parentResourceId != nil && parentResourceId.ResourceType != accountResourceType.Id {
+		if _, parent, err = unscopeResourceID(parentResourceId); err != nil {
+			return nil, nil, err
+		}
+	}
+	resource, annos, err := getter.Get(ctx, inner, parent)
+	if err != nil || resource == nil {
+		return nil, annos, err
+	}
+
+	return scopeAccountResource(locator, resource), annos, nil
+}
+
+// accountScopedCapabilities are the provisioning interfaces of connectorbuilder a builder
+// implements, one bit each, so the account-scoped wrapper can offer the same ones.
+type accountScopedCapabilities int
+
+const (
+	canProvision accountScopedCapabilities = 1 << iota
+	canDelete
+	canRotate
+	canCreateAccount
+	canIssue
+)
+
+// accountScopedSyncer wraps builder, the connected account's builder for the type, in the account
+// scoped builder that offers the same provisioning: grants for roles, deletion and rotation for
+// users and programmatic access tokens, and account creation, and token issuance when secrets are
+// synced, for users. Every account's builder for the type is built the same way, so they share
+// builder's capabilities.
+func accountScopedSyncer(scoped *accountScopedBuilder, builder connectorbuilder.ResourceSyncerV2) connectorbuilder.ResourceSyncerV2 {
+	var capabilities accountScopedCapabilities
+	wrappers := accountScopedWrappers{
+		provisioner: &accountScopedProvisioner{scoped},
+		deleter:     &accountScopedDeleter{scoped},
+	}
+	if _, ok := builder.(connectorbuilder.ResourceProvisionerLimited); ok {
+		capabilities |= canProvision
+	}
+	if _, ok := builder.(connectorbuilder.ResourceDeleterV2Limited); ok {
+		capabilities |= canDelete
+	}
+	if manager, ok := builder.(connectorbuilder.CredentialManagerLimited); ok {
+		capabilities |= canRotate
+		wrappers.credentialManager = &accountScopedCredentialManager{scoped, manager}
+	}
+	if manager, ok := builder.(connectorbuilder.AccountManagerLimited); ok {
+		capabilities |= canCreateAccount
+		wrappers.accountManager = &accountScopedAccountManager{scoped, manager}
+	}
+	if issuer, ok := builder.(connectorbuilder.CredentialIssuerLimited); ok {
+		capabilities |= canIssue
+		wrappers.credentialIssuer = &accountScopedCredentialIssuer{scoped, issuer}
+	}
+	return composeAccountScoped(scoped, capabilities, &wrappers)
+}
+
+// accountScopedWrappers are the wrappers that add each capability to an account-scoped builder.
+type accountScopedWrappers struct {
+	provisioner       *accountScopedProvisioner
+	deleter           *accountScopedDeleter
+	credentialManager *accountScopedCredentialManager
+	accountManager    *accountScopedAccountManager
+	credentialIssuer  *accountScopedCredentialIssuer
+}
+
+// composeAccountScoped embeds the wrappers of the capabilities in one syncer with scoped, so it
+// implements exactly those interfaces: the SDK finds a type's capabilities by type assertion, and
+// Go only composes method sets statically, hence a struct type for each combination. The wrappers
+// embed scoped too, but the syncer's own scoped is shallower, so its methods are the ones promoted.
+func composeAccountScoped(scoped *accountScopedBuilder, capabilities accountScopedCapabilities, w *accountScopedWrappers) connectorbuilder.ResourceSyncerV2 {
+	type (
+		b = accountScopedBuilder
+		p = accountScopedProvisioner
+		d = accountScopedDeleter
+		r = accountScopedCredentialManager
+		a = accountScopedAccountManager
+		i = accountScopedCredentialIssuer
+	)
+	pr, de, ro, ac, is := w.provisioner, w.deleter, w.credentialManager, w.accountManager, w.credentialIssuer
+
+	switch capabilities {
+	case canProvision:
+		return &struct {
+			*b
+			*p
+		}{scoped, pr}
+	case canDelete:
+		return &struct {
+			*b
+			*d
+		}{scoped, de}
+	case canProvision | canDelete:
+		return &struct {
+			*b
+			*p
+			*d
+		}{scoped, pr, de}
+	case canRotate:
+		return &struct {
+			*b
+			*r
+		}{scoped, ro}
+	case canProvision | canRotate:
+		return &struct {
+			*b
+			*p
+			*r
+		}{scoped, pr, ro}
+	case canDelete | canRotate:
+		return &struct {
+			*b
+			*d
+			*r
+		}{scoped, de, ro}
+	case canProvision | canDelete | canRotate:
+		return &struct {
+			*b
+			*p
+			*d
+			*r
+		}{scoped, pr, de, ro}
+	case canCreateAccount:
+		return &struct {
+			*b
+			*a
+		}{scoped, ac}
+	case canProvision | canCreateAccount:
+		return &struct {
+			*b
+			*p
+			*a
+		}{scoped, pr, ac}
+	case canDelete | canCreateAccount:
+		return &struct {
+			*b
+			*d
+			*a
+		}{scoped, de, ac}
+	case canProvision | canDelete | canCreateAccount:
+		return &struct {
+			*b
+			*p
+			*d
+			*a
+		}{scoped, pr, de, ac}
+	case canRotate | canCreateAccount:
+		return &struct {
+			*b
+			*r
+			*a
+		}{scoped, ro, ac}
+	case canProvision | canRotate | canCreateAccount:
+		return &struct {
+			*b
+			*p
+			*r
+			*a
+		}{scoped, pr, ro, ac}
+	case canDelete | canRotate | canCreateAccount:
+		return &struct {
+			*b
+			*d
+			*r
+			*a
+		}{scoped, de, ro, ac}
+	case canProvision | canDelete | canRotate | canCreateAccount:
+		return &struct {
+			*b
+			*p
+			*d
+			*r
+			*a
+		}{scoped, pr, de, ro, ac}
+	case canIssue:
+		return &struct {
+			*b
+			*i
+		}{scoped, is}
+	case canProvision | canIssue:
+		return &struct {
+			*b
+			*p
+			*i
+		}{scoped, pr, is}
+	case canDelete | canIssue:
+		return &struct {
+			*b
+			*d
+			*i
+		}{scoped, de, is}
+	case canProvision | canDelete | canIssue:
+		return &struct {
+			*b
+			*p
+			*d
+			*i
+		}{scoped, pr, de, is}
+	case canRotate | canIssue:
+		return &struct {
+			*b
+			*r
+			*i
+		}{scoped, ro, is}
+	case canProvision | canRotate | canIssue:
+		return &struct {
+			*b
+			*p
+			*r
+			*i
+		}{scoped, pr, ro, is}
+	case canDelete | canRotate | canIssue:
+		return &struct {
+			*b
+			*d
+			*r
+			*i
+		}{scoped, de, ro, is}
+	case canProvision | canDelete | canRotate | canIssue:
+		return &struct {
+			*b
+			*p
+			*d
+			*r
+			*i
+		}{scoped, pr, de, ro, is}
+	case canCreateAccount | canIssue:
+		return &struct {
+			*b
+			*a
+			*i
+		}{scoped, ac, is}
+	case canProvision | canCreateAccount | canIssue:
+		return &struct {
+			*b
+			*p
+			*a
+			*i
+		}{scoped, pr, ac, is}
+	case canDelete | canCreateAccount | canIssue:
+		return &struct {
+			*b
+			*d
+			*a
+			*i
+		}{scoped, de, ac, is}
+	case canProvision | canDelete | canCreateAccount | canIssue:
+		return &struct {
+			*b
+			*p
+			*d
+			*a
+			*i
+		}{scoped, pr, de, ac, is}
+	case canRotate | canCreateAccount | canIssue:
+		return &struct {
+			*b
+			*r
+			*a
+			*i
+		}{scoped, ro, ac, is}
+	case canProvision | canRotate | canCreateAccount | canIssue:
+		return &struct {
+			*b
+			*p
+			*r
+			*a
+			*i
+		}{scoped, pr, ro, ac, is}
+	case canDelete | canRotate | canCreateAccount | canIssue:
+		return &struct {
+			*b
+			*d
+			*r
+			*a
+			*i
+		}{scoped, de, ro, ac, is}
+	case canProvision | canDelete | canRotate | canCreateAccount | canIssue:
+		return &struct {
+			*b
+			*p
+			*d
+			*r
+			*a
+			*i
+		}{scoped, pr, de, ro, ac, is}
+	}
+	return scoped
+}
+
+// accountScopedProvisioner grants and revokes entitlements of an account-scoped type in the
+// account the entitlement belongs to.
+type accountScopedProvisioner struct {
+	*accountScopedBuilder
+}
+
+func (o *accountScopedProvisioner) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	locator, inner, err := unscopeEntitlement(entitlement)
+	if err != nil {
+		return nil, err
+	}
+	if principal, err = unscopePrincipal(locator, principal); err != nil {
+		return nil, err
+	}
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, err
+	}
+	provisioner, ok := syncer.(connectorbuilder.ResourceProvisionerLimited)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "baton-snowflake: %s does not support provisioning", o.resourceType.Id)
+	}
+	return provisioner.Grant(ctx, principal, inner)
+}
+
+func (o *accountScopedProvisioner) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	locator, inner, err := unscopeGrant(grant)
+	if err != nil {
+		return nil, err
+	}
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, err
+	}
+	provisioner, ok := syncer.(connectorbuilder.ResourceProvisionerLimited)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "baton-snowflake: %s does not support provisioning", o.resourceType.Id)
+	}
+	return provisioner.Revoke(ctx, inner)
+}
+
+// accountScopedDeleter deletes resources of an account-scoped type in the account they belong to.
+type accountScopedDeleter struct {
+	*accountScopedBuilder
+}
+
+func (o *accountScopedDeleter) Delete(ctx context.Context, resourceId *v2.ResourceId, parentResourceID *v2.ResourceId) (annotations.Annotations, error) {
+	locator, inner, err := unscopeResourceID(resourceId)
+	if err != nil {
+		return nil, err
+	}
+	var parent *v2.ResourceId
+	if parentResourceID != nil && parentResourceID.ResourceType != accountResourceType.Id {
+		if _, parent, err = unscopeResourceID(parentResourceID); err != nil {
+			return nil, err
+		}
+	}
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, err
+	}
+	deleter, ok := syncer.(connectorbuilder.ResourceDeleterV2Limited)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "baton-snowflake: %s does not support deletion", o.resourceType.Id)
+	}
+	return deleter.Delete(ctx, inner, parent)
+}
+
+// accountScopedCredentialManager rotates the credentials of resources of an account-scoped type in
+// the account they belong to.
+type accountScopedCredentialManager struct {
+	*accountScopedBuilder
+	// manager is the connected account's builder, which describes the rotation every account's
+	// builder offers.
+	manager connectorbuilder.CredentialManagerLimited
+}
+
+func (o *accountScopedCredentialManager) RotateCapabilityDetails(ctx context.Context) (*v2.CredentialDetailsCredentialRotation, annotations.Annotations, error) {
+	return o.manager.RotateCapabilityDetails(ctx)
+}
+
+func (o *accountScopedCredentialManager) Rotate(
+	ctx context.Context,
+	resourceId *v2.ResourceId,
+	credentialOptions *v2.LocalCredentialOptions,
+) ([]*v2.PlaintextData, annotations.Annotations, error) {
+	locator, inner, err := unscopeResourceID(resourceId)
+	if err != nil {
+		return nil, nil, err
+	}
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	manager, ok := syncer.(connectorbuilder.CredentialManagerLimited)
+	if !ok {
+		return nil, nil, status.Errorf(codes.Unimplemented, "baton-snowflake: %s does not support credential rotation", o.resourceType.Id)
+	}
+	return manager.Rotate(ctx, inner, credentialOptions)
+}
+
+// accountScopedAccountManager creates users in the account named by the account profile field.
+type accountScopedAccountManager struct {
+	*accountScopedBuilder
+	// manager is the connected account's builder, which describes the account creation every
+	// account's builder offers.
+	manager connectorbuilder.AccountManagerLimited
+}
+
+func (o *accountScopedAccountManager) CreateAccountCapabilityDetails(ctx context.Context) (*v2.CredentialDetailsAccountProvisioning, annotations.Annotations, error) {
+	return o.manager.CreateAccountCapabilityDetails(ctx)
+}
+
+func (o *accountScopedAccountManager) CreateAccount(
+	ctx context.Context,
+	accountInfo *v2.AccountInfo,
+	credentialOptions *v2.LocalCredentialOptions,
+) (connectorbuilder.CreateAccountResponse, []*v2.PlaintextData, annotations.Annotations, error) {
+	account, _ := rs.GetProfileStringValue(accountInfo.GetProfile(), profileKeyAccount)
+	if strings.TrimSpace(account) == "" {
+		return nil, nil, nil, status.Error(codes.InvalidArgument, "baton-snowflake: organization sync needs the account to create the user in (provide via profile.account)")
+	}
+	locator, err := o.org.accountLocator(ctx, strings.TrimSpace(account))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	manager, ok := syncer.(connectorbuilder.AccountManagerLimited)
+	if !ok {
+		return nil, nil, nil, status.Errorf(codes.Unimplemented, "baton-snowflake: %s does not support account creation", o.resourceType.Id)
+	}
+
+	response, plaintexts, annos, err := manager.CreateAccount(ctx, accountInfo, credentialOptions)
+	if err != nil {
+		return nil, nil, annos, err
+	}
+	switch result := response.(type) {
+	case *v2.CreateAccountResponse_SuccessResult:
+		result.Resource = scopeAccountResource(locator, result.Resource)
+	case *v2.CreateAccountResponse_ActionRequiredResult:
+		result.Resource = scopeAccountResource(locator, result.Resource)
+	}
+	return response, plaintexts, annos, nil
+}
+
+// accountScopedCredentialIssuer issues programmatic access tokens to users in the account the
+// user belongs to.
+type accountScopedCredentialIssuer struct {
+	*accountScopedBuilder
+	// issuer is the connected account's builder, which describes the issuance every account's
+	// builder offers.
+	issuer connectorbuilder.CredentialIssuerLimited
+}
+
+func (o *accountScopedCredentialIssuer) IssueCapabilityDetails(ctx context.Context) (*v2.CredentialDetailsCredentialIssue, annotations.Annotations, error) {
+	return o.issuer.IssueCapabilityDetails(ctx)
+}
+
+func (o *accountScopedCredentialIssuer) Issue(ctx context.Context, input *connectorbuilder.CredentialIssueInput) (*connectorbuilder.CredentialIssueOutput, error) {
+	locator, identity, err := unscopeResourceID(input.IdentityID)
+	if err != nil {
+		return nil, err
+	}
+	syncer, err := o.org.syncer(ctx, locator, o.resourceType.Id)
+	if err != nil {
+		return nil, err
+	}
+	issuer, ok := syncer.(connectorbuilder.CredentialIssuerLimited)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "baton-snowflake: %s does not support credential issuance", o.resourceType.Id)
+	}
+
+	inner := *input
+	inner.IdentityID = identity
+	output, err := issuer.Issue(ctx, &inner)
+	if err != nil || output == nil {
+		return output, err
+	}
+	output.Secret = scopeResource(locator, output.Secret)
+	return output, nil
+}
+
+// accountScopedFeed reads an event feed in every account of the organization, one account after
+// another, through each account's own client. Event ids and the resources events refer to are
+// scoped to the account, matching the resources organization sync lists.
+type accountScopedFeed struct {
+	org *organization
+	// newFeed returns the feed reading one account through client.
+	newFeed func(client *snowflake.Client) connectorbuilder.EventFeed
+}
+
+// accountScopedFeedCursor is the cursor of an accountScopedFeed: the feed's cursor in each
+// account, and the accounts left to read in the current pass, the first being the one read next.
+type accountScopedFeedCursor struct {
+	Cursors map[string]string `json:"cursors,omitempty"`
+	Pending []string          `json:"pending,omitempty"`
+}
+
+func (f *accountScopedFeed) EventFeedMetadata(ctx context.Context) *v2.EventFeedMetadata {
+	// The metadata is the same in every account.
+	return f.newFeed(f.org.client).EventFeedMetadata(ctx)
+}
+
+// ListEvents reads a page of the feed in the next pending account, starting a pass over every
+// account when none are pending. The pass ends, and the feed has no more events, once each
+// account's feed has none.
+func (f *accountScopedFeed) ListEvents(
+	ctx context.Context,
+	earliestEvent *timestamppb.Timestamp,
+	pToken *pagination.StreamToken,
+) ([]*v2.Event, *pagination.StreamState, annotations.Annotations, error) {
+	cursor := accountScopedFeedCursor{}
+	if pToken.Cursor != "" {
+		if err := json.Unmarshal([]byte(pToken.Cursor), &cursor); err != nil {
+			return nil, nil, nil, fmt.Errorf("baton-snowflake: invalid event cursor: %w", err)
+		}
+	}
+	if cursor.Cursors == nil {
+		cursor.Cursors = map[string]string{}
+	}
+	if len(cursor.Pending) == 0 {
+		accounts, err := f.org.listAccounts(ctx)
+		if err != nil {
+			return nil, nil, nil, wrapError(err, "failed to list organization accounts")
+		}
+		for _, account := range accounts {
+			cursor.Pending = append(cursor.Pending, account.AccountLocator)
+		}
+		if len(cursor.Pending) == 0 {
+			return nil, &pagination.StreamState{Cursor: pToken.Cursor}, nil, nil
+		}
+	}
+
+	locator := cursor.Pending[0]
+	var events []*v2.Event
+	var annos annotations.Annotations
+	client, err := f.org.scopedClient(ctx, locator)
+	switch {
+	case status.Code(err) == codes.NotFound:
+		// The account has left the organization since the pass started.
+		delete(cursor.Cursors, locator)
+		cursor.Pending = cursor.Pending[1:]
+	case err != nil:
+		return nil, nil, nil, err
+	default:
+		var state *pagination.StreamState
+		events, state, annos, err = f.newFeed(client).ListEvents(ctx, earliestEvent, &pagination.StreamToken{
+			Size:   pToken.Size,
+			Cursor: cursor.Cursors[locator],
+		})
+		if err != nil {
+			return nil, nil, annos, err
+		}
+		for i, event := range events {
+			events[i] = scopeEvent(locator, event)
+		}
+		cursor.Cursors[locator] = state.Cursor
+		if !state.HasMore {
+			cursor.Pending = cursor.Pending[1:]
+		}
+	}
+
+	next, err := json.Marshal(cursor)
+	if err != nil {
+		return nil, nil, nil, wrapError(err, "failed to create event cursor")
+	}
+	return events, &pagination.StreamState{Cursor: string(next), HasMore: len(cursor.Pending) > 0}, annos, nil
+}
+
+// scopeEvent copies an event read in the account with the locator, with its id and the resources,
+// entitlements and principals it refers to scoped to the account.
+func scopeEvent(locator string, event *v2.Event) *v2.Event {
+	scoped := proto.Clone(event).(*v2.Event)
+	scoped.Id = scopeID(locator, event.GetId())
+	switch e := scoped.GetEvent().(type) {
+	case *v2.Event_UsageEvent:
+		e.UsageEvent.TargetResource = scopeAccountResource(locator, e.UsageEvent.GetTargetResource())
+		e.UsageEvent.ActorResource = scopeAccountResource(locator, e.UsageEvent.GetActorResource())
+	case *v2.Event_CreateGrantEvent:
+		e.CreateGrantEvent.Entitlement = scopeEntitlement(locator, e.CreateGrantEvent.GetEntitlement())
+		e.CreateGrantEvent.Principal = scopeResource(locator, e.CreateGrantEvent.GetPrincipal())
+	case *v2.Event_CreateRevokeEvent:
+		e.CreateRevokeEvent.Entitlement = scopeEntitlement(locator, e.CreateRevokeEvent.GetEntitlement())
+		e.CreateRevokeEvent.Principal = scopeResource(locator, e.CreateRevokeEvent.GetPrincipal())
+	}
+	return scoped
+}
+
+// accountSyncOpAttrs scopes the sync's session store to the account, so the account builders'
+// caches, keyed by names such as role names, do not collide across accounts.
+func accountSyncOpAttrs(locator string, opts rs.SyncOpAttrs) rs.SyncOpAttrs {
+	if opts.Session != nil {
+		opts.Session = &accountSessionStore{SessionStore: opts.Session, locator: locator}
+	}
+	return opts
+}
+
+// scopeID is the id of the resource with the id within an account.
+func scopeID(locator, id string) string {
+	return locator + accountIDSeparator + id
+}
+
+func scopeResourceID(locator string, id *v2.ResourceId) *v2.ResourceId {
+	if id == nil {
+		return nil
+	}
+	scoped := proto.Clone(id).(*v2.ResourceId)
+	scoped.Resource = scopeID(locator, id.Resource)
+	return scoped
+}
+
+// unscopeResourceID splits a scoped resource id into the account locator and the id within the
+// account.
+func unscopeResourceID(id *v2.ResourceId) (string, *v2.ResourceId, error) {
+	locator, resource, ok := strings.Cut(id.GetResource(), accountIDSeparator)
+	if !ok || locator == "" {
+		return "", nil, status.Errorf(codes.InvalidArgument, "baton-snowflake: resource id %q is not scoped to an account", id.GetResource())
+	}
+	inner := proto.Clone(id).(*v2.ResourceId)
+	inner.Resource = resource
+	return locator, inner, nil
+}
+
+// scopeResource copies the account's resource with its id, and its parent's, scoped to the account.
+func scopeResource(locator string, resource *v2.Resource) *v2.Resource {
+	if resource == nil {
+		return nil
+	}
+	scoped := proto.Clone(resource).(*v2.Resource)
+	scoped.Id = scopeResourceID(locator, resource.Id)
+	scoped.ParentResourceId = scopeResourceID(locator, resource.ParentResourceId)
+	return scoped
+}
+
+// scopeAccountResource scopes the account's resource like scopeResource, listing it under the
+// account when it has no parent within the account.
+func scopeAccountResource(locator string, resource *v2.Resource) *v2.Resource {
+	scoped := scopeResource(locator, resource)
+	if scoped != nil && scoped.ParentResourceId == nil {
+		scoped.ParentResourceId = accountPrincipalID(locator)
+	}
+	return scoped
+}
+
+// unscopeResource copies a scoped resource as its account's builder built it.
+func unscopeResource(resource *v2.Resource) (string, *v2.Resource, error) {
+	locator, id, err := unscopeResourceID(resource.GetId())
+	if err != nil {
+		return "", nil, err
+	}
+	inner := proto.Clone(resource).(*v2.Resource)
+	inner.Id = id
+	inner.ParentResourceId = nil
+	if parent := resource.GetParentResourceId(); parent != nil && parent.ResourceType != accountResourceType.Id {
+		if _, inner.ParentResourceId, err = unscopeResourceID(parent); err != nil {
+			return "", nil, err
+		}
+	}
+	return locator, inner, nil
+}
+
+// scopeEntitlementID scopes an entitlement id, TYPE:RESOURCE:SLUG, to the account.
+func scopeEntitlementID(locator, id string) string {
+	resourceType, rest, ok := strings.Cut(id, ":")
+	if !ok {
+		return id
+	}
+	return resourceType + ":" + scopeID(locator, rest)
+}
+
+// unscopeEntitlementID splits a scoped entitlement id into the account locator and the id within
+// the account.
+func unscopeEntitlementID(id string) (string, string, error) {
+	resourceType, rest, _ := strings.Cut(id, ":")
+	locator, inner, ok := strings.Cut(rest, accountIDSeparator)
+	if !ok || locator == "" {
+		return "", "", status.Errorf(codes.InvalidArgument, "baton-snowflake: entitlement id %q is not scoped to an account", id)
+	}
+	return locator, resourceType + ":" + inner, nil
+}
+
+func scopeEntitlement(locator string, entitlement *v2.Entitlement) *v2.Entitlement {
+	scoped := proto.Clone(entitlement).(*v2.Entitlement)
+	scoped.Id = scopeEntitlementID(locator, entitlement.Id)
+	scoped.Resource = scopeResource(locator, entitlement.Resource)
+	return scoped
+}
+
+// unscopeEntitlement copies a scoped entitlement as its account's builder built it.
+func unscopeEntitlement(entitlement *v2.Entitlement) (string, *v2.Entitlement, error) {
+	locator, resource, err := unscopeResource(entitlement.GetResource())
+	if err != nil {
+		return "", nil, err
+	}
+	entitlementLocator, id, err := unscopeEntitlementID(entitlement.GetId())
+	if err != nil {
+		return "", nil, err
+	}
+	if entitlementLocator != locator {
+		return "", nil, status.Errorf(codes.InvalidArgument, "baton-snowflake: entitlement %q does not belong to account %s", entitlement.GetId(), locator)
+	}
+	inner := proto.Clone(entitlement).(*v2.Entitlement)
+	inner.Id = id
+	inner.Resource = resource
+	return locator, inner, nil
+}
+
+// unscopePrincipal copies a scoped principal as the builders of the account with the locator built
+// it. A principal in another account cannot be granted the account's entitlements.
+func unscopePrincipal(locator string, principal *v2.Resource) (*v2.Resource, error) {
+	if principal.GetId().GetResourceType() == accountResourceType.Id {
+		return principal, nil
+	}
+	principalLocator, inner, err := unscopeResource(principal)
+	if err != nil {
+		return nil, err
+	}
+	if principalLocator != locator {
+		return nil, status.Errorf(codes.InvalidArgument, "baton-snowflake: principal %q is not in account %s", principal.GetId().GetResource(), locator)
+	}
+	return inner, nil
+}
+
+// unscopeGrant copies a scoped grant as its account's builder built it.
+func unscopeGrant(grant *v2.Grant) (string, *v2.Grant, error) {
+	locator, entitlement, err := unscopeEntitlement(grant.GetEntitlement())
+	if err != nil {
+		return "", nil, err
+	}
+	principal, err := unscopePrincipal(locator, grant.GetPrincipal())
+	if err != nil {
+		return "", nil, err
+	}
+	inner := proto.Clone(grant).(*v2.Grant)
+	inner.Entitlement = entitlement
+	inner.Principal = principal
+	inner.Id = fmt.Sprintf("%s:%s:%s", entitlement.GetId(), principal.GetId().GetResourceType(), principal.GetId().GetResource())
+	return locator, inner, nil
+}
+
+// scopeGrant scopes the grant's entitlement and principal to the account, along with the
+// entitlements it expands. Account principals keep their locator id.
+func scopeGrant(locator string, grant *v2.Grant) (*v2.Grant, error) {
+	scoped := proto.Clone(grant).(*v2.Grant)
+	scoped.Entitlement = scopeEntitlement(locator, grant.Entitlement)
+	if grant.GetPrincipal().GetId().GetResourceType() != accountResourceType.Id {
+		scoped.Principal = scopeResource(locator, grant.Principal)
+	}
+	scoped.Id = fmt.Sprintf("%s:%s:%s", scoped.Entitlement.GetId(), scoped.Principal.GetId().GetResourceType(), scoped.Principal.GetId().GetResource())
+
+	annos := annotations.Annotations(scoped.Annotations)
+	expandable := &v2.GrantExpandable{}
+	ok, err := annos.Pick(expandable)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		for i, id := range expandable.EntitlementIds {
+			expandable.EntitlementIds[i] = scopeEntitlementID(locator, id)
+		}
+		annos.Update(expandable)
+		scoped.Annotations = annos
+	}
+	return scoped, nil
+}
+
+// accountSessionStore prefixes every key in the session store with the account locator.
+type accountSessionStore struct {
+	sessions.SessionStore
+	locator string
+}
+
+func (s *accountSessionStore) options(opt []sessions.SessionStoreOption) []sessions.SessionStoreOption {
+	return append(slices.Clone(opt), func(ctx context.Context, bag *sessions.SessionStoreBag) error {
+		bag.Prefix = scopeID(s.locator, bag.Prefix)
+		return nil
+	})
+}
+
+func (s *accountSessionStore) Get(ctx context.Context, key string, opt ...sessions.SessionStoreOption) ([]byte, bool, error) {
+	return s.SessionStore.Get(ctx, key, s.options(opt)...)
+}
+
+func (s *accountSessionStore) GetMany(ctx context.Context, keys []string, opt ...sessions.SessionStoreOption) (map[string][]byte, []string, error) {
+	return s.SessionStore.GetMany(ctx, keys, s.options(opt)...)
+}
+
+func (s *accountSessionStore) Set(ctx context.Context, key string, value []byte, opt ...sessions.SessionStoreOption) error {
+	return s.SessionStore.Set(ctx, key, value, s.options(opt)...)
+}
+
+func (s *accountSessionStore) SetMany(ctx context.Context, values map[string][]byte, opt ...sessions.SessionStoreOption) error {
+	return s.SessionStore.SetMany(ctx, values, s.options(opt)...)
+}
+
+func (s *accountSessionStore) Delete(ctx context.Context, key string, opt ...sessions.SessionStoreOption) error {
+	return s.SessionStore.Delete(ctx, key, s.options(opt)...)
+}
+
+func (s *accountSessionStore) Clear(ctx context.Context, opt ...sessions.SessionStoreOption) error {
+	return s.SessionStore.Clear(ctx, s.options(opt)...)
+}
+
+func (s *accountSessionStore) GetAll(ctx context.Context, pageToken string, opt ...sessions.SessionStoreOption) (map[string][]byte, string, error) {
+	return s.SessionStore.GetAll(ctx, pageToken, s.options(opt)...)
+}
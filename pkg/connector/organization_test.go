@@ -0,0 +1,384 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+// accountRoleSyncer stands in for one account's account role builder: it lists one role, granted
+// to one user, and records what the organization wrapper passed it.
+type accountRoleSyncer struct {
+	listParent *v2.ResourceId
+	granted    *v2.Resource
+	// provisioned records the entitlement and principal the wrapper passed to Grant or Revoke.
+	provisioned []string
+}
+
+func (s *accountRoleSyncer) ResourceType(context.Context) *v2.ResourceType {
+	return accountRoleResourceType
+}
+
+func (s *accountRoleSyncer) List(ctx context.Context, parent *v2.ResourceId, opts rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	s.listParent = parent
+	if err := opts.Session.Set(ctx, "ANALYST", []byte("cached"), sessions.WithPrefix("account_role")); err != nil {
+		return nil, nil, err
+	}
+	role, err := accountRoleResource(&snowflake.AccountRole{Name: "ANALYST"})
+	if err != nil {
+		return nil, nil, err
+	}
+	return []*v2.Resource{role}, &rs.SyncOpResults{}, nil
+}
+
+func (s *accountRoleSyncer) Entitlements(context.Context, *v2.Resource, rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return nil, &rs.SyncOpResults{}, nil
+}
+
+func (s *accountRoleSyncer) Grants(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	s.granted = resource
+	g := grant.NewGrant(resource, assignedEntitlement,
+		&v2.ResourceId{ResourceType: userResourceType.Id, Resource: "ALICE"},
+		grant.WithAnnotation(&v2.GrantExpandable{EntitlementIds: []string{"account_role:PARENT:assigned"}}),
+	)
+	return []*v2.Grant{g}, &rs.SyncOpResults{}, nil
+}
+
+func (s *accountRoleSyncer) Grant(_ context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	s.provisioned = append(s.provisioned, "grant "+entitlement.Id+" "+entitlement.Resource.Id.Resource+" "+principal.Id.Resource)
+	return nil, nil
+}
+
+func (s *accountRoleSyncer) Revoke(_ context.Context, g *v2.Grant) (annotations.Annotations, error) {
+	s.provisioned = append(s.provisioned, "revoke "+g.Id)
+	return nil, nil
+}
+
+func newTestOrganization(syncer connectorbuilder.ResourceSyncerV2) *organization {
+	org := newOrganization(nil, nil, accountCredentials{}, nil, nil)
+	org.accounts = map[string]snowflake.OrganizationAccount{"AB12345": {AccountLocator: "AB12345"}}
+	org.scoped["AB12345"] = map[string]connectorbuilder.ResourceSyncerV2{accountRoleResourceType.Id: syncer}
+	return org
+}
+
+func TestAccountScopedBuilder_ListsUnderAccount(t *testing.T) {
+	inner := &accountRoleSyncer{}
+	builder := &accountScopedBuilder{resourceType: accountRoleResourceType, org: newTestOrganization(inner), topLevel: true}
+	store := &memorySessionStore{data: map[string][]byte{}}
+	accountID := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: "AB12345"}
+
+	resources, _, err := builder.List(context.Background(), nil, rs.SyncOpAttrs{Session: store})
+	require.NoError(t, err)
+	assert.Empty(t, resources, "account-scoped types are only listed under an account")
+
+	resources, _, err = builder.List(context.Background(), accountID, rs.SyncOpAttrs{Session: store})
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Nil(t, inner.listParent, "the account's builder lists its top-level resources without a parent")
+	assert.Equal(t, "AB12345/ANALYST", resources[0].Id.Resource)
+	assert.Equal(t, accountID, resources[0].ParentResourceId)
+	assert.Contains(t, store.data, "/AB12345/account_roleANALYST", "session keys are scoped to the account")
+}
+
+func TestAccountScopedBuilder_GrantsAreScopedToAccount(t *testing.T) {
+	inner := &accountRoleSyncer{}
+	builder := &accountScopedBuilder{resourceType: accountRoleResourceType, org: newTestOrganization(inner), topLevel: true}
+	role := &v2.Resource{
+		Id:               &v2.ResourceId{ResourceType: accountRoleResourceType.Id, Resource: "AB12345/ANALYST"},
+		ParentResourceId: &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: "AB12345"},
+	}
+
+	grants, _, err := builder.Grants(context.Background(), role, rs.SyncOpAttrs{})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+
+	assert.Equal(t, "ANALYST", inner.granted.Id.Resource)
+	assert.Nil(t, inner.granted.ParentResourceId)
+
+	g := grants[0]
+	assert.Equal(t, "account_role:AB12345/ANALYST:assigned", g.Entitlement.Id)
+	assert.Equal(t, "AB12345/ANALYST", g.Entitlement.Resource.Id.Resource)
+	assert.Equal(t, "AB12345/ALICE", g.Principal.Id.Resource)
+	assert.Equal(t, "account_role:AB12345/ANALYST:assigned:user:AB12345/ALICE", g.Id)
+
+	expandable := &v2.GrantExpandable{}
+	annos := annotations.Annotations(g.Annotations)
+	ok, err := annos.Pick(expandable)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []string{"account_role:AB12345/PARENT:assigned"}, expandable.EntitlementIds)
+}
+
+func TestAccountScopedBuilder_RejectsUnscopedResource(t *testing.T) {
+	builder := &accountScopedBuilder{resourceType: accountRoleResourceType, org: newTestOrganization(&accountRoleSyncer{}), topLevel: true}
+	role := &v2.Resource{Id: &v2.ResourceId{ResourceType: accountRoleResourceType.Id, Resource: "ANALYST"}}
+
+	_, _, err := builder.Entitlements(context.Background(), role, rs.SyncOpAttrs{})
+	require.Error(t, err)
+}
+
+func TestScopeEntitlementID(t *testing.T) {
+	role := &v2.Resource{Id: &v2.ResourceId{ResourceType: tableResourceType.Id, Resource: "DB.PUBLIC.T"}}
+	id := ent.NewEntitlementID(role, "select")
+	assert.Equal(t, "table:AB12345/DB.PUBLIC.T:select", scopeEntitlementID("AB12345", id))
+}
+
+func TestOrganization_ResourceSyncers(t *testing.T) {
+	c := &Connector{}
+	org := newOrganization(nil, nil, accountCredentials{}, nil, nil)
+
+	var accounts *accountBuilder
+	scoped := map[string]bool{}
+	syncers := map[string]connectorbuilder.ResourceSyncerV2{}
+	for _, syncer := range org.resourceSyncers(context.Background(), c.accountSyncers(context.Background(), nil, nil)) {
+		syncers[syncer.ResourceType(context.Background()).Id] = syncer
+		if builder, ok := syncer.(*accountBuilder); ok {
+			accounts = builder
+			continue
+		}
+		if wrapped, ok := syncer.(interface{ accountScoped() *accountScopedBuilder }); ok {
+			scoped[wrapped.accountScoped().resourceType.Id] = wrapped.accountScoped().topLevel
+			continue
+		}
+		assert.True(t, organizationWideResourceTypeIDs[syncer.ResourceType(context.Background()).Id], "only organization-wide types are left unwrapped")
+	}
+
+	require.NotNil(t, accounts)
+	assert.True(t, scoped[userResourceType.Id])
+	assert.False(t, scoped[tableResourceType.Id], "tables stay under their database")
+	assert.Implements(t, (*connectorbuilder.AccountManagerLimited)(nil), syncers[userResourceType.Id])
+	assert.Implements(t, (*connectorbuilder.ResourceDeleterV2Limited)(nil), syncers[userResourceType.Id])
+	assert.Implements(t, (*connectorbuilder.CredentialManagerLimited)(nil), syncers[userResourceType.Id])
+	assert.NotImplements(t, (*connectorbuilder.ResourceProvisionerLimited)(nil), syncers[userResourceType.Id])
+	assert.Implements(t, (*connectorbuilder.ResourceProvisionerLimited)(nil), syncers[accountRoleResourceType.Id])
+	assert.NotImplements(t, (*connectorbuilder.ResourceProvisionerLimited)(nil), syncers[databaseResourceType.Id])
+	var children []string
+	for _, resourceType := range accounts.childResourceTypes {
+		children = append(children, resourceType.Id)
+	}
+	assert.Contains(t, children, databaseResourceType.Id)
+	assert.NotContains(t, children, tableResourceType.Id)
+}
+
+// accountScopedCapabilitiesOf reports the provisioning interfaces syncer implements.
+func accountScopedCapabilitiesOf(syncer connectorbuilder.ResourceSyncerV2) accountScopedCapabilities {
+	var capabilities accountScopedCapabilities
+	if _, ok := syncer.(connectorbuilder.ResourceProvisionerLimited); ok {
+		capabilities |= canProvision
+	}
+	if _, ok := syncer.(connectorbuilder.ResourceDeleterV2Limited); ok {
+		capabilities |= canDelete
+	}
+	if _, ok := syncer.(connectorbuilder.CredentialManagerLimited); ok {
+		capabilities |= canRotate
+	}
+	if _, ok := syncer.(connectorbuilder.AccountManagerLimited); ok {
+		capabilities |= canCreateAccount
+	}
+	if _, ok := syncer.(connectorbuilder.CredentialIssuerLimited); ok {
+		capabilities |= canIssue
+	}
+	return capabilities
+}
+
+// TestComposeAccountScoped_KeepsEveryCapability checks each combination of capabilities composes
+// into a syncer with exactly those, still listing through the account-scoped builder.
+func TestComposeAccountScoped_KeepsEveryCapability(t *testing.T) {
+	scoped := &accountScopedBuilder{resourceType: userResourceType}
+	wrappers := &accountScopedWrappers{
+		provisioner:       &accountScopedProvisioner{scoped},
+		deleter:           &accountScopedDeleter{scoped},
+		credentialManager: &accountScopedCredentialManager{accountScopedBuilder: scoped},
+		accountManager:    &accountScopedAccountManager{accountScopedBuilder: scoped},
+		credentialIssuer:  &accountScopedCredentialIssuer{accountScopedBuilder: scoped},
+	}
+
+	for capabilities := accountScopedCapabilities(0); capabilities < canIssue<<1; capabilities++ {
+		syncer := composeAccountScoped(scoped, capabilities, wrappers)
+		assert.Equal(t, capabilities, accountScopedCapabilitiesOf(syncer), "capabilities %05b", capabilities)
+		assert.Same(t, scoped, syncer.(interface{ accountScoped() *accountScopedBuilder }).accountScoped())
+	}
+}
+
+func TestParseAccountCredentials(t *testing.T) {
+	_, err := parseAccountCredentials([]string{"PROD"})
+	require.Error(t, err)
+	_, err = parseAccountCredentials([]string{"PROD=SVC"})
+	require.Error(t, err)
+	_, err = parseAccountCredentials([]string{"PROD=SVC:/does/not/exist.p8"})
+	require.Error(t, err)
+
+	credentials, err := parseAccountCredentials(nil)
+	require.NoError(t, err)
+	assert.Empty(t, credentials)
+}
+
+func TestScopeGrant_KeepsAccountPrincipal(t *testing.T) {
+	policy := &v2.Resource{Id: &v2.ResourceId{ResourceType: passwordPolicyResourceType.Id, Resource: "SECURITY.PUBLIC.STRICT"}}
+	scoped, err := scopeGrant("AB12345", grant.NewGrant(policy, attachedEntitlement, accountPrincipalID("AB12345")))
+	require.NoError(t, err)
+	assert.Equal(t, "AB12345", scoped.Principal.Id.Resource)
+	assert.Equal(t, "password_policy:AB12345/SECURITY.PUBLIC.STRICT:attached:account:AB12345", scoped.Id)
+}
+
+func TestAccountScopedProvisioner_ReachesTheEntitlementsAccount(t *testing.T) {
+	prod, dev := &accountRoleSyncer{}, &accountRoleSyncer{}
+	org := newOrganization(nil, nil, accountCredentials{}, nil, nil)
+	org.accounts = map[string]snowflake.OrganizationAccount{
+		"AB12345": {AccountLocator: "AB12345", AccountName: "PROD"},
+		"CD67890": {AccountLocator: "CD67890", AccountName: "DEV"},
+	}
+	org.scoped["AB12345"] = map[string]connectorbuilder.ResourceSyncerV2{accountRoleResourceType.Id: prod}
+	org.scoped["CD67890"] = map[string]connectorbuilder.ResourceSyncerV2{accountRoleResourceType.Id: dev}
+
+	syncer := accountScopedSyncer(&accountScopedBuilder{resourceType: accountRoleResourceType, org: org, topLevel: true}, prod)
+	provisioner, ok := syncer.(connectorbuilder.ResourceProvisionerLimited)
+	require.True(t, ok, "account roles keep their provisioning in organization sync")
+
+	role := &v2.Resource{
+		Id:               &v2.ResourceId{ResourceType: accountRoleResourceType.Id, Resource: "CD67890/ANALYST"},
+		ParentResourceId: accountPrincipalID("CD67890"),
+	}
+	entitlement := scopeEntitlement("CD67890", ent.NewAssignmentEntitlement(&v2.Resource{
+		Id: &v2.ResourceId{ResourceType: accountRoleResourceType.Id, Resource: "ANALYST"},
+	}, assignedEntitlement))
+	entitlement.Resource = role
+	alice := &v2.Resource{Id: &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "CD67890/ALICE"}}
+
+	_, err := provisioner.Grant(context.Background(), alice, entitlement)
+	require.NoError(t, err)
+	_, err = provisioner.Revoke(context.Background(), grant.NewGrant(role, assignedEntitlement, alice.Id))
+	require.NoError(t, err)
+
+	assert.Empty(t, prod.provisioned)
+	assert.Equal(t, []string{
+		"grant account_role:ANALYST:assigned ANALYST ALICE",
+		"revoke account_role:ANALYST:assigned:user:ALICE",
+	}, dev.provisioned)
+
+	bob := &v2.Resource{Id: &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "AB12345/BOB"}}
+	_, err = provisioner.Grant(context.Background(), bob, entitlement)
+	require.Error(t, err, "a user cannot be granted another account's role")
+}
+
+func TestAccountScopedAccountManager_CreatesUserInNamedAccount(t *testing.T) {
+	var createBody snowflake.CreateUserRequest
+	server := newCreateUserMockServer(t, &createBody)
+	defer server.Close()
+
+	org := newOrganization(nil, nil, accountCredentials{}, nil, nil)
+	org.accounts = map[string]snowflake.OrganizationAccount{"CD67890": {AccountLocator: "CD67890", AccountName: "DEV"}}
+	users := newUserBuilder(newTestConnector(t, server.URL).Client, false, false, false, profileLookups{})
+	org.scoped["CD67890"] = map[string]connectorbuilder.ResourceSyncerV2{userResourceType.Id: users}
+
+	syncer := accountScopedSyncer(&accountScopedBuilder{resourceType: userResourceType, org: org, topLevel: true}, users)
+	manager, ok := syncer.(connectorbuilder.AccountManagerLimited)
+	require.True(t, ok, "users keep account creation in organization sync")
+	opts := &v2.LocalCredentialOptions{}
+	opts.SetNoPassword(&v2.LocalCredentialOptions_NoPassword{})
+
+	accountInfo := serviceUserAccountInfo(t, "SERVICE", false)
+	_, _, _, err := manager.CreateAccount(context.Background(), accountInfo, opts)
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "organization sync needs the account to create the user in")
+
+	accountInfo.Profile.Fields[profileKeyAccount] = structpb.NewStringValue("dev")
+	response, _, _, err := manager.CreateAccount(context.Background(), accountInfo, opts)
+	require.NoError(t, err)
+	require.NotEmpty(t, createBody.Name, "the user is created through the account's own client")
+
+	result, ok := response.(*v2.CreateAccountResponse_SuccessResult)
+	require.True(t, ok)
+	assert.Equal(t, "CD67890/SVC_ETL", result.Resource.Id.Resource)
+	assert.Equal(t, accountPrincipalID("CD67890"), result.Resource.ParentResourceId)
+}
+
+// accountFeed stands in for one account's event feed: it returns one usage event per page, two
+// pages in all, and records the cursors it was given.
+type accountFeed struct {
+	user    string
+	cursors *[]string
+}
+
+func (f *accountFeed) EventFeedMetadata(context.Context) *v2.EventFeedMetadata {
+	return &v2.EventFeedMetadata{Id: loginHistoryFeedID}
+}
+
+func (f *accountFeed) ListEvents(
+	_ context.Context,
+	_ *timestamppb.Timestamp,
+	pToken *pagination.StreamToken,
+) ([]*v2.Event, *pagination.StreamState, annotations.Annotations, error) {
+	*f.cursors = append(*f.cursors, f.user+":"+pToken.Cursor)
+	user := eventUserResource(f.user)
+	event := &v2.Event{
+		Id:    "login:" + f.user + pToken.Cursor,
+		Event: &v2.Event_UsageEvent{UsageEvent: &v2.UsageEvent{TargetResource: user, ActorResource: user}},
+	}
+	return []*v2.Event{event}, &pagination.StreamState{Cursor: "1", HasMore: pToken.Cursor == ""}, nil, nil
+}
+
+func TestAccountScopedFeed_ReadsEveryAccount(t *testing.T) {
+	org := newOrganization(nil, nil, accountCredentials{}, nil, nil)
+	org.accounts = map[string]snowflake.OrganizationAccount{
+		"AB12345": {AccountLocator: "AB12345"},
+		"CD67890": {AccountLocator: "CD67890"},
+	}
+	prod, err := snowflake.New("https://prod.example.com", snowflake.JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+	dev, err := snowflake.New("https://dev.example.com", snowflake.JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+	org.clients["AB12345"], org.clients["CD67890"] = prod, dev
+
+	var cursors []string
+	feed := &accountScopedFeed{org: org, newFeed: func(client *snowflake.Client) connectorbuilder.EventFeed {
+		if client == prod {
+			return &accountFeed{user: "ALICE", cursors: &cursors}
+		}
+		return &accountFeed{user: "BOB", cursors: &cursors}
+	}}
+	pending := accountScopedFeedCursor{Pending: []string{"AB12345", "CD67890"}}
+	start, err := json.Marshal(pending)
+	require.NoError(t, err)
+
+	var ids, targets []string
+	token := &pagination.StreamToken{Cursor: string(start)}
+	for {
+		events, state, _, err := feed.ListEvents(context.Background(), nil, token)
+		require.NoError(t, err)
+		for _, event := range events {
+			ids = append(ids, event.Id)
+			targets = append(targets, event.GetUsageEvent().GetTargetResource().GetId().GetResource())
+		}
+		token = &pagination.StreamToken{Cursor: state.Cursor}
+		if !state.HasMore {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"ALICE:", "ALICE:1", "BOB:", "BOB:1"}, cursors, "each account's feed resumes from its own cursor")
+	assert.Equal(t, []string{"AB12345/login:ALICE", "AB12345/login:ALICE1", "CD67890/login:BOB", "CD67890/login:BOB1"}, ids)
+	assert.Equal(t, []string{"AB12345/ALICE", "AB12345/ALICE", "CD67890/BOB", "CD67890/BOB"}, targets)
+
+	var next accountScopedFeedCursor
+	require.NoError(t, json.Unmarshal([]byte(token.Cursor), &next))
+	assert.Empty(t, next.Pending, "the pass is over")
+	assert.Equal(t, map[string]string{"AB12345": "1", "CD67890": "1"}, next.Cursors)
+}
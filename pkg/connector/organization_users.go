@@ -0,0 +1,246 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+const (
+	memberEntitlement = "member"
+
+	// profileKeyOrganizationUser names the organization user an account user was imported from.
+	profileKeyOrganizationUser = "organization_user"
+	// profileKeyImported lists the accounts that have imported the organization user group.
+	profileKeyImported = "imported"
+)
+
+// organizationUserBuilder syncs the organization users defined in the organization account. Like
+// licenses, they are skipped when the account is not the organization account or the connector's
+// role lacks GLOBALORGADMIN.
+type organizationUserBuilder struct {
+	client *snowflake.Client
+}
+
+func (o *organizationUserBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return organizationUserResourceType
+}
+
+func organizationUserResource(user *snowflake.OrganizationUser) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		profileKeyName:    user.Name,
+		"login":           user.LoginName,
+		"display_name":    user.DisplayName,
+		"first_name":      user.FirstName,
+		"last_name":       user.LastName,
+		"email":           user.Email,
+		profileKeyComment: user.Comment,
+	}
+
+	traits := []rs.UserTraitOption{
+		rs.WithUserLogin(user.LoginName),
+	}
+	if user.Email != "" {
+		traits = append(traits, rs.WithEmail(user.Email, true))
+	}
+
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = user.Name
+	}
+
+	return rs.NewUserResource(
+		displayName,
+		organizationUserResourceType,
+		user.Name,
+		traits,
+		rs.WithResourceProfile(profile),
+	)
+}
+
+func (o *organizationUserBuilder) List(ctx context.Context, _ *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	users, statusCode, err := o.client.ListOrganizationUsers(ctx)
+	if err != nil {
+		if snowflake.IsOrganizationUnavailable(statusCode, err) {
+			ctxzap.Extract(ctx).Debug("skipping organization user sync: organization users unavailable for this account", zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list organization users")
+	}
+
+	var resources []*v2.Resource
+	for _, user := range users {
+		resource, err := organizationUserResource(&user) // #nosec G601
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create organization user resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, &rs.SyncOpResults{}, nil
+}
+
+func (o *organizationUserBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return nil, &rs.SyncOpResults{}, nil
+}
+
+func (o *organizationUserBuilder) Grants(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	return nil, &rs.SyncOpResults{}, nil
+}
+
+func newOrganizationUserBuilder(client *snowflake.Client) *organizationUserBuilder {
+	return &organizationUserBuilder{
+		client: client,
+	}
+}
+
+// organizationUserGroupBuilder syncs the organization user groups, with their members as grants
+// of the member entitlement.
+type organizationUserGroupBuilder struct {
+	client *snowflake.Client
+	// org is set in organization sync, where every account's imports are reported rather than
+	// only the connected account's.
+	org *organization
+}
+
+func (o *organizationUserGroupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return organizationUserGroupResourceType
+}
+
+// organizationUserGroupResource builds the group. imported names the accounts that have imported
+// it.
+func organizationUserGroupResource(group *snowflake.OrganizationUserGroup, imported []string) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		profileKeyName:     group.Name,
+		profileKeyComment:  group.Comment,
+		"visibility":       group.Visibility,
+		profileKeyImported: stringListProfileValue(imported),
+	}
+
+	return rs.NewGroupResource(group.Name, organizationUserGroupResourceType, group.Name, nil, rs.WithResourceProfile(profile))
+}
+
+func (o *organizationUserGroupBuilder) List(ctx context.Context, _ *v2.ResourceId, opts rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	l := ctxzap.Extract(ctx)
+
+	groups, statusCode, err := o.client.ListOrganizationUserGroups(ctx)
+	if err != nil {
+		if snowflake.IsOrganizationUnavailable(statusCode, err) {
+			l.Debug("skipping organization user group sync: organization user groups unavailable for this account", zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list organization user groups")
+	}
+
+	imported, err := o.importedGroups(ctx, opts.Session)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to list the accounts' organization user groups")
+	}
+
+	var resources []*v2.Resource
+	for _, group := range groups {
+		resource, err := organizationUserGroupResource(&group, imported[group.Name]) // #nosec G601
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create organization user group resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, &rs.SyncOpResults{}, nil
+}
+
+// importedGroups returns, by organization user group name, the names of the accounts that have
+// imported the group: every account of the organization in organization sync, each through its own
+// client, and otherwise the connected account. An account only reports this under the connector's
+// own role; one that cannot list its organization user groups is left out.
+func (o *organizationUserGroupBuilder) importedGroups(ctx context.Context, ss sessions.SessionStore) (map[string][]string, error) {
+	clients := map[string]*snowflake.Client{}
+	if o.org == nil {
+		account, err := o.client.GetCurrentAccount(ctx, ss)
+		if err != nil {
+			return nil, err
+		}
+		clients[account.AccountName] = o.client
+	} else {
+		accounts, err := o.org.listAccounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range accounts {
+			client, err := o.org.scopedClient(ctx, account.AccountLocator)
+			if err != nil {
+				return nil, err
+			}
+			clients[account.AccountName] = client
+		}
+	}
+
+	imported := map[string][]string{}
+	for _, name := range slices.Sorted(maps.Keys(clients)) {
+		groups, statusCode, err := clients[name].ListAvailableOrganizationUserGroups(ctx)
+		if err != nil {
+			if snowflake.IsOrganizationUnavailable(statusCode, err) {
+				ctxzap.Extract(ctx).Debug("cannot list the account's organization user groups, syncing without its imports",
+					zap.String("account", name), zap.Error(err))
+				continue
+			}
+			return nil, err
+		}
+		for _, group := range groups {
+			if group.IsImported {
+				imported[group.Name] = append(imported[group.Name], name)
+			}
+		}
+	}
+	return imported, nil
+}
+
+func (o *organizationUserGroupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return []*v2.Entitlement{
+		ent.NewAssignmentEntitlement(
+			resource,
+			memberEntitlement,
+			ent.WithGrantableTo(organizationUserResourceType),
+			ent.WithDescription(fmt.Sprintf("Is a member of the %s organization user group", resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s organization user group %s", resource.DisplayName, memberEntitlement)),
+		),
+	}, &rs.SyncOpResults{}, nil
+}
+
+func (o *organizationUserGroupBuilder) Grants(ctx context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	members, err := o.client.ListOrganizationUserGroupMembers(ctx, resource.Id.Resource)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			ctxzap.Extract(ctx).Debug("cannot list organization user group members: insufficient privileges, skipping",
+				zap.String("organization_user_group", resource.Id.Resource), zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, fmt.Sprintf("failed to list members of organization user group %q", resource.Id.Resource))
+	}
+
+	var grants []*v2.Grant
+	for _, member := range members {
+		memberID := &v2.ResourceId{ResourceType: organizationUserResourceType.Id, Resource: member.Name}
+		grants = append(grants, grant.NewGrant(resource, memberEntitlement, memberID))
+	}
+
+	return grants, &rs.SyncOpResults{}, nil
+}
+
+func newOrganizationUserGroupBuilder(client *snowflake.Client, org *organization) *organizationUserGroupBuilder {
+	return &organizationUserGroupBuilder{
+		client: client,
+		org:    org,
+	}
+}
@@ -0,0 +1,162 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+var organizationUserColumns = []string{"name", "login_name", "display_name", "first_name", "last_name", "email", "comment"}
+
+func TestOrganizationUserBuilder_List_SkipsOutsideOrganizationAccount(t *testing.T) {
+	// A regular account cannot run SHOW ORGANIZATION USERS and Snowflake rejects it with 400.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{"message": "Unsupported feature 'ORGANIZATION USERS'."})
+	}))
+	defer server.Close()
+
+	builder := newOrganizationUserBuilder(newTestConnector(t, server.URL).Client)
+	resources, _, err := builder.List(context.Background(), nil, rs.SyncOpAttrs{})
+	require.NoError(t, err)
+	assert.Empty(t, resources)
+}
+
+func TestOrganizationUserBuilder_List(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, organizationUserColumns, [][]string{
+		{"ALICE", "alice@example.com", "Alice", "Alice", "Smith", "alice@example.com", ""},
+	}, &statements)
+	defer server.Close()
+
+	builder := newOrganizationUserBuilder(newTestConnector(t, server.URL).Client)
+	resources, _, err := builder.List(context.Background(), nil, rs.SyncOpAttrs{})
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, []string{"SHOW ORGANIZATION USERS;"}, statements)
+	assert.Equal(t, "ALICE", resources[0].Id.Resource)
+	assert.Equal(t, "Alice", resources[0].DisplayName)
+
+	trait, err := rs.GetUserTrait(resources[0])
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", trait.Login)
+}
+
+var organizationUserGroupColumns = []string{"name", "comment", "visibility", "is_imported"}
+
+// organizationUserGroupImports collects the accounts each listed group reports as importing it.
+func organizationUserGroupImports(t *testing.T, resources []*v2.Resource) map[string][]string {
+	t.Helper()
+	imported := map[string][]string{}
+	for _, resource := range resources {
+		value, ok := rs.GetProfile(resource).GetFields()[profileKeyImported]
+		require.True(t, ok)
+		accounts := []string{}
+		for _, account := range value.GetListValue().GetValues() {
+			accounts = append(accounts, account.GetStringValue())
+		}
+		imported[resource.Id.Resource] = accounts
+	}
+	return imported
+}
+
+func TestOrganizationUserGroupBuilder_List_ReportsImportingAccount(t *testing.T) {
+	// Both the organization-wide listing and the connected account's listing return the groups.
+	server := newStatementDispatchServer(t, func(statement string) statementResult {
+		if strings.HasPrefix(statement, "SELECT CURRENT_ORGANIZATION_NAME()") {
+			return statementResult{[]string{"org", "account", "locator", "region"}, [][]string{{"ACME", "PROD", "AB12345", "AWS_US_WEST_2"}}}
+		}
+		return statementResult{organizationUserGroupColumns, [][]string{
+			{"ENGINEERING", "", "ALL", "true"},
+			{"FINANCE", "", "ALL", "false"},
+		}}
+	})
+	defer server.Close()
+
+	builder := newOrganizationUserGroupBuilder(newTestConnector(t, server.URL).Client, nil)
+	resources, _, err := builder.List(context.Background(), nil, rs.SyncOpAttrs{})
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, map[string][]string{"ENGINEERING": {"PROD"}, "FINANCE": {}}, organizationUserGroupImports(t, resources))
+}
+
+func TestOrganizationUserGroupBuilder_List_ReportsEveryImportingAccount(t *testing.T) {
+	prod := newStatementDispatchServer(t, func(statement string) statementResult {
+		if statement == "SHOW ORGANIZATION ACCOUNTS;" {
+			return statementResult{
+				[]string{"organization_name", "account_name", "snowflake_region", "edition", "account_url", "account_locator"},
+				[][]string{
+					{"ACME", "PROD", "AWS_US_WEST_2", "ENTERPRISE", "", "AB12345"},
+					{"ACME", "DEV", "AWS_US_WEST_2", "ENTERPRISE", "", "CD67890"},
+				},
+			}
+		}
+		return statementResult{organizationUserGroupColumns, [][]string{
+			{"ENGINEERING", "", "ALL", "true"},
+			{"FINANCE", "", "ALL", "false"},
+		}}
+	})
+	defer prod.Close()
+	dev := newStatementDispatchServer(t, func(string) statementResult {
+		return statementResult{organizationUserGroupColumns, [][]string{
+			{"ENGINEERING", "", "", "true"},
+			{"FINANCE", "", "", "true"},
+		}}
+	})
+	defer dev.Close()
+
+	client := newTestConnector(t, prod.URL).Client
+	org := newOrganization(client, nil, accountCredentials{}, nil, nil)
+	org.clients["AB12345"] = client
+	org.clients["CD67890"] = newTestConnector(t, dev.URL).Client
+
+	builder := newOrganizationUserGroupBuilder(client, org)
+	resources, _, err := builder.List(context.Background(), nil, rs.SyncOpAttrs{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"ENGINEERING": {"DEV", "PROD"}, "FINANCE": {"DEV"}}, organizationUserGroupImports(t, resources))
+}
+
+func TestOrganizationUserGroupBuilder_Grants(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, organizationUserColumns, [][]string{
+		{"ALICE", "alice", "", "", "", "", ""},
+		{"BOB", "bob", "", "", "", "", ""},
+	}, &statements)
+	defer server.Close()
+
+	builder := newOrganizationUserGroupBuilder(newTestConnector(t, server.URL).Client, nil)
+	group := &v2.Resource{Id: &v2.ResourceId{ResourceType: organizationUserGroupResourceType.Id, Resource: `DATA "ENG"`}}
+
+	grants, _, err := builder.Grants(context.Background(), group, rs.SyncOpAttrs{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`SHOW ORGANIZATION USERS IN ORGANIZATION USER GROUP "DATA ""ENG""";`}, statements)
+	require.Len(t, grants, 2)
+	for i, name := range []string{"ALICE", "BOB"} {
+		assert.Equal(t, organizationUserResourceType.Id, grants[i].Principal.Id.ResourceType)
+		assert.Equal(t, name, grants[i].Principal.Id.Resource)
+		assert.Equal(t, "organization_user_group:DATA \"ENG\":member", grants[i].Entitlement.Id)
+	}
+}
+
+func TestUserResourceWithPolicies_LinksImportedOrganizationUser(t *testing.T) {
+	builder := newUserBuilder(nil, false, false, true, profileLookups{})
+	imported := map[string]bool{"ALICE": true}
+
+	for name, want := range map[string]string{"ALICE": "ALICE", "LOCAL_ADMIN": ""} {
+		resource, err := builder.userResourceWithPolicies(context.Background(), &snowflake.User{Username: name, Login: name}, "", &snowflake.PolicyAttachments{}, imported)
+		require.NoError(t, err)
+		got, _ := rs.GetProfileStringValue(rs.GetProfile(resource), profileKeyOrganizationUser)
+		assert.Equal(t, want, got, name)
+	}
+}
@@ -0,0 +1,167 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+// userPolicyProfileKeys are the user profile fields reporting the policy of each kind that
+// applies to the user.
+var userPolicyProfileKeys = map[string]string{
+	snowflake.PolicyKindAuthentication: "authentication_policy",
+	snowflake.PolicyKindPassword:       "password_policy",
+	snowflake.PolicyKindSession:        "session_policy",
+}
+
+// mfaEnforcingEnrollments are the MFA_ENROLLMENT values of an authentication policy that make
+// users enroll in MFA: always, or when they sign in with a password (anywhere or in Snowsight).
+var mfaEnforcingEnrollments = map[string]bool{
+	"REQUIRED":                            true,
+	"REQUIRED_PASSWORD_ONLY":              true,
+	"REQUIRED_SNOWFLAKE_UI_PASSWORD_ONLY": true,
+}
+
+// policyBuilder syncs one kind of user-level policy. Policies are attached to users and to the
+// account; both are grants of the "attached" entitlement, the account attachment to the account
+// resource, and it also sets the attached_to_account profile field.
+type policyBuilder struct {
+	resourceType *v2.ResourceType
+	kind         string
+	client       *snowflake.Client
+}
+
+func (o *policyBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+func policyResource(resourceType *v2.ResourceType, policy *snowflake.Policy, attachments *snowflake.PolicyAttachments) (*v2.Resource, error) {
+	fqName := policy.FullyQualifiedName()
+	profile := map[string]interface{}{
+		profileKeyName:        policy.Name,
+		"database":            policy.DatabaseName,
+		"schema":              policy.SchemaName,
+		"owner":               policy.Owner,
+		profileKeyComment:     policy.Comment,
+		"attached_to_account": attachments != nil && attachments.Account[policy.Kind] == fqName,
+	}
+	if policy.Kind == snowflake.PolicyKindAuthentication && attachments != nil {
+		profile["mfa_enrollment"] = attachments.MFAEnrollment[fqName]
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithResourceProfile(profile),
+	}
+	if !policy.CreatedOn.IsZero() {
+		opts = append(opts, rs.WithResourceCreatedAt(policy.CreatedOn))
+	}
+
+	return rs.NewResource(policy.Name, resourceType, fqName, opts...)
+}
+
+// userPolicyProfile returns the effective policy fields for a user's profile. mfa_enforced is
+// true only when the effective authentication policy requires MFA enrollment, for any sign-in or
+// for password sign-ins; has_mfa on its own only says whether the user has enrolled.
+func userPolicyProfile(username string, attachments *snowflake.PolicyAttachments) map[string]interface{} {
+	profile := make(map[string]interface{}, len(userPolicyProfileKeys)+1)
+	for kind, key := range userPolicyProfileKeys {
+		profile[key] = attachments.ForUser(username, kind)
+	}
+
+	authenticationPolicy := attachments.ForUser(username, snowflake.PolicyKindAuthentication)
+	profile["mfa_enforced"] = authenticationPolicy != "" &&
+		mfaEnforcingEnrollments[strings.ToUpper(attachments.MFAEnrollment[authenticationPolicy])]
+
+	return profile
+}
+
+func (o *policyBuilder) List(ctx context.Context, _ *v2.ResourceId, opts rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	l := ctxzap.Extract(ctx)
+
+	policies, err := o.client.ListPolicies(ctx, o.kind)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			l.Debug("ListPolicies: insufficient privileges, skipping", zap.String("kind", o.kind), zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, fmt.Sprintf("failed to list %s policies", o.resourceType.DisplayName))
+	}
+	if len(policies) == 0 {
+		return nil, nil, nil
+	}
+
+	attachments, err := o.client.GetPolicyAttachments(ctx, opts.Session)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to get policy attachments")
+	}
+
+	var resources []*v2.Resource
+	for _, policy := range policies {
+		resource, err := policyResource(o.resourceType, &policy, attachments) // #nosec G601
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create policy resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *policyBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return []*v2.Entitlement{
+		ent.NewAssignmentEntitlement(
+			resource,
+			attachedEntitlement,
+			ent.WithGrantableTo(userResourceType, accountResourceType),
+			ent.WithDescription(fmt.Sprintf("Has %s %s attached", o.resourceType.DisplayName, resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s %s attached", o.resourceType.DisplayName, resource.DisplayName)),
+		),
+	}, nil, nil
+}
+
+func (o *policyBuilder) Grants(ctx context.Context, resource *v2.Resource, opts rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	attachments, err := o.client.GetPolicyAttachments(ctx, opts.Session)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to get policy attachments")
+	}
+
+	users := attachments.UsersAttachedTo(o.kind, resource.Id.Resource)
+	sort.Strings(users)
+
+	var grants []*v2.Grant
+	for _, username := range users {
+		userID, err := rs.NewResourceID(userResourceType, username)
+		if err != nil {
+			return nil, nil, wrapError(err, "unable to create user resource id")
+		}
+		grants = append(grants, grant.NewGrant(resource, attachedEntitlement, userID))
+	}
+
+	if attachments.Account[o.kind] == resource.Id.Resource {
+		account, err := o.client.GetCurrentAccount(ctx, opts.Session)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to get current account")
+		}
+		grants = append(grants, grant.NewGrant(resource, attachedEntitlement, accountPrincipalID(account.AccountLocator)))
+	}
+
+	return grants, nil, nil
+}
+
+func newPolicyBuilder(client *snowflake.Client, resourceType *v2.ResourceType, kind string) *policyBuilder {
+	return &policyBuilder{
+		resourceType: resourceType,
+		kind:         kind,
+		client:       client,
+	}
+}
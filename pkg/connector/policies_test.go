@@ -0,0 +1,86 @@
+package connector
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+func TestUserPolicyProfile_MFAEnforced(t *testing.T) {
+	attachments := &snowflake.PolicyAttachments{
+		Account: map[string]string{snowflake.PolicyKindAuthentication: "SECURITY.PUBLIC.MFA_OPTIONAL"},
+		Users: map[string]map[string]string{
+			"ALICE": {snowflake.PolicyKindAuthentication: "SECURITY.PUBLIC.MFA_REQUIRED"},
+			"CAROL": {snowflake.PolicyKindAuthentication: "SECURITY.PUBLIC.MFA_FOR_PASSWORDS"},
+		},
+		MFAEnrollment: map[string]string{
+			"SECURITY.PUBLIC.MFA_REQUIRED":      "REQUIRED",
+			"SECURITY.PUBLIC.MFA_FOR_PASSWORDS": "REQUIRED_PASSWORD_ONLY",
+			"SECURITY.PUBLIC.MFA_OPTIONAL":      "OPTIONAL",
+		},
+	}
+
+	alice := userPolicyProfile("ALICE", attachments)
+	require.Equal(t, "SECURITY.PUBLIC.MFA_REQUIRED", alice["authentication_policy"])
+	require.Equal(t, true, alice["mfa_enforced"])
+
+	bob := userPolicyProfile("BOB", attachments)
+	require.Equal(t, "SECURITY.PUBLIC.MFA_OPTIONAL", bob["authentication_policy"])
+	require.Equal(t, false, bob["mfa_enforced"])
+	require.Equal(t, "", bob["password_policy"])
+
+	carol := userPolicyProfile("CAROL", attachments)
+	require.Equal(t, true, carol["mfa_enforced"], "MFA required for password sign-ins is enforced")
+}
+
+func TestPolicyResource_AttachedToAccount(t *testing.T) {
+	policy := &snowflake.Policy{DatabaseName: "SECURITY", SchemaName: "PUBLIC", Name: "STRICT", Kind: snowflake.PolicyKindPassword}
+	attachments := &snowflake.PolicyAttachments{
+		Account: map[string]string{snowflake.PolicyKindPassword: "SECURITY.PUBLIC.STRICT"},
+	}
+
+	resource, err := policyResource(passwordPolicyResourceType, policy, attachments)
+	require.NoError(t, err)
+	require.Equal(t, "SECURITY.PUBLIC.STRICT", resource.GetId().GetResource())
+	require.Equal(t, true, resource.GetProfile().AsMap()["attached_to_account"])
+}
+
+func TestPolicyBuilder_Grants_AccountAttachment(t *testing.T) {
+	server := newStatementDispatchServer(t, func(statement string) statementResult {
+		switch {
+		case strings.HasPrefix(statement, "SHOW PASSWORD POLICIES"):
+			return statementResult{policyColumns, [][]string{{"", "STRICT", "SECURITY", "PUBLIC", "SECURITYADMIN", ""}}}
+		case strings.Contains(statement, "POLICY_REFERENCES"):
+			return statementResult{
+				[]string{"POLICY_DB", "POLICY_SCHEMA", "POLICY_NAME", "POLICY_KIND", "REF_DATABASE_NAME", "REF_SCHEMA_NAME", "REF_ENTITY_NAME", "REF_ENTITY_DOMAIN", "REF_COLUMN_NAME"},
+				[][]string{
+					{"SECURITY", "PUBLIC", "STRICT", "PASSWORD_POLICY", "", "", "ALICE", "USER", ""},
+					{"SECURITY", "PUBLIC", "STRICT", "PASSWORD_POLICY", "", "", "AB12345", "ACCOUNT", ""},
+				},
+			}
+		case strings.HasPrefix(statement, "SELECT CURRENT_ORGANIZATION_NAME()"):
+			return statementResult{[]string{"ORG", "ACCOUNT", "LOCATOR", "REGION"}, [][]string{{"ACME", "PROD", "AB12345", "AWS_US_WEST_2"}}}
+		default:
+			return statementResult{policyColumns, nil}
+		}
+	})
+	defer server.Close()
+
+	builder := newPolicyBuilder(newTestConnector(t, server.URL).Client, passwordPolicyResourceType, snowflake.PolicyKindPassword)
+	policy, err := policyResource(passwordPolicyResourceType, &snowflake.Policy{DatabaseName: "SECURITY", SchemaName: "PUBLIC", Name: "STRICT", Kind: snowflake.PolicyKindPassword}, nil)
+	require.NoError(t, err)
+
+	grants, _, err := builder.Grants(context.Background(), policy, rs.SyncOpAttrs{})
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	require.Equal(t, userResourceType.Id, grants[0].Principal.Id.ResourceType)
+	require.Equal(t, "ALICE", grants[0].Principal.Id.Resource)
+	require.Equal(t, accountResourceType.Id, grants[1].Principal.Id.ResourceType)
+	require.Equal(t, "AB12345", grants[1].Principal.Id.Resource)
+	require.Equal(t, "password_policy:SECURITY.PUBLIC.STRICT:attached", grants[1].Entitlement.Id)
+}
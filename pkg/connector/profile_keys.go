@@ -3,4 +3,6 @@ package connector
 const (
 	profileKeyName    = "name"
 	profileKeyComment = "comment"
+	profileKeyKeyPair = "key_pair"
+	profileKeyAccount = "account"
 )
@@ -0,0 +1,311 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+const (
+	// programmaticAccessTokenMaxDays is the longest DAYS_TO_EXPIRY Snowflake accepts.
+	programmaticAccessTokenMaxDays = 365
+	// programmaticAccessTokenDefaultDays is the DAYS_TO_EXPIRY Snowflake applies when none is set.
+	programmaticAccessTokenDefaultDays = 15
+	// programmaticAccessTokenExpiryMargin is held back from a requested expiry before it is rounded
+	// down to whole days, so the expiry Snowflake computes from its own clock cannot pass it.
+	programmaticAccessTokenExpiryMargin = time.Hour
+	// plaintextKeyToken is the name of the PlaintextData carrying a token secret.
+	plaintextKeyToken = "token"
+)
+
+// programmaticAccessTokenBuilder syncs each user's programmatic access tokens (PATs). A PAT
+// authenticates as its user without MFA, so tokens on service users are worth reviewing.
+type programmaticAccessTokenBuilder struct {
+	client *snowflake.Client
+}
+
+func (o *programmaticAccessTokenBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return programmaticAccessTokenResourceType
+}
+
+// programmaticAccessTokenID is the resource ID of a token: token names are only unique per user.
+func programmaticAccessTokenID(username, tokenName string) string {
+	return fmt.Sprintf("%s/%s", username, tokenName)
+}
+
+// splitProgrammaticAccessTokenID reverses programmaticAccessTokenID. Token names are Snowflake
+// generated or chosen by an admin and, unlike user names, rarely contain a slash, so the ID is
+// split at the last one.
+func splitProgrammaticAccessTokenID(id string) (string, string, error) {
+	idx := strings.LastIndex(id, "/")
+	if idx <= 0 || idx == len(id)-1 {
+		return "", "", status.Errorf(codes.InvalidArgument, "baton-snowflake: invalid programmatic access token id %q", id)
+	}
+	return id[:idx], id[idx+1:], nil
+}
+
+func programmaticAccessTokenResource(token *snowflake.ProgrammaticAccessToken, userID *v2.ResourceId) (*v2.Resource, error) {
+	secretTraits := []rs.SecretTraitOption{
+		rs.WithSecretIdentityID(userID),
+		rs.WithSecretType(v2.SecretTrait_CREDENTIAL_TYPE_STATIC_SECRET),
+		rs.WithSecretDetail("snowflake.programmatic_access_token"),
+	}
+	if !token.CreatedOn.IsZero() {
+		secretTraits = append(secretTraits, rs.WithSecretCreatedAt(token.CreatedOn))
+	}
+	if !token.ExpiresAt.IsZero() {
+		secretTraits = append(secretTraits, rs.WithSecretExpiresAt(token.ExpiresAt))
+	}
+	if token.CreatedBy != "" {
+		createdByID, err := rs.NewResourceID(userResourceType, token.CreatedBy)
+		if err != nil {
+			return nil, err
+		}
+		secretTraits = append(secretTraits, rs.WithSecretCreatedByID(createdByID))
+	}
+
+	profile := map[string]interface{}{
+		profileKeyName:     token.Name,
+		"user_name":        userID.Resource,
+		"role_restriction": token.RoleRestriction,
+		"status":           token.Status,
+		profileKeyComment:  token.Comment,
+	}
+
+	status := v2.Status_RESOURCE_STATUS_ENABLED
+	detailedStatus := ""
+	if !token.IsActive() {
+		status = v2.Status_RESOURCE_STATUS_DISABLED
+		detailedStatus = strings.ToLower(token.Status)
+	}
+
+	return rs.NewSecretResource(
+		token.Name,
+		programmaticAccessTokenResourceType,
+		programmaticAccessTokenID(userID.Resource, token.Name),
+		secretTraits,
+		rs.WithParentResourceID(userID),
+		rs.WithResourceProfile(profile),
+		rs.WithResourceStatus(status, detailedStatus),
+	)
+}
+
+func (o *programmaticAccessTokenBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	if parentResourceID == nil {
+		return nil, nil, nil
+	}
+
+	if parentResourceID.ResourceType != userResourceType.Id {
+		return nil, nil, fmt.Errorf("invalid parent resource type: %s", parentResourceID.ResourceType)
+	}
+
+	tokens, err := o.client.ListProgrammaticAccessTokens(ctx, parentResourceID.Resource)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			ctxzap.Extract(ctx).Debug("skipping programmatic access tokens: insufficient privileges on user",
+				zap.String("username", parentResourceID.Resource))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list programmatic access tokens")
+	}
+
+	var resources []*v2.Resource
+	for i := range tokens {
+		resource, err := programmaticAccessTokenResource(&tokens[i], parentResourceID)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create programmatic access token resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *programmaticAccessTokenBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+func (o *programmaticAccessTokenBuilder) Grants(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+// Delete revokes the token (ALTER USER ... REMOVE PROGRAMMATIC ACCESS TOKEN).
+func (o *programmaticAccessTokenBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (annotations.Annotations, error) {
+	username, tokenName, err := splitProgrammaticAccessTokenID(resourceId.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.client.RemoveProgrammaticAccessToken(ctx, username, tokenName); err != nil {
+		return nil, wrapError(err, "failed to remove programmatic access token")
+	}
+
+	ctxzap.Extract(ctx).Debug("programmatic access token removed",
+		zap.String("username", username),
+		zap.String("token_name", tokenName),
+	)
+	return nil, nil
+}
+
+// RotateCapabilityDetails advertises rotation without a credential option: Snowflake generates
+// the new secret itself.
+func (o *programmaticAccessTokenBuilder) RotateCapabilityDetails(_ context.Context) (*v2.CredentialDetailsCredentialRotation, annotations.Annotations, error) {
+	return generatedCredentialRotationDetails(), nil, nil
+}
+
+// Rotate replaces the token's secret (ALTER USER ... ROTATE PROGRAMMATIC ACCESS TOKEN) and returns
+// the new one. The previous secret keeps working for 24 hours.
+func (o *programmaticAccessTokenBuilder) Rotate(
+	ctx context.Context,
+	resourceId *v2.ResourceId,
+	_ *v2.LocalCredentialOptions,
+) ([]*v2.PlaintextData, annotations.Annotations, error) {
+	username, tokenName, err := splitProgrammaticAccessTokenID(resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret, err := o.client.RotateProgrammaticAccessToken(ctx, username, tokenName)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to rotate programmatic access token")
+	}
+
+	return programmaticAccessTokenPlaintext(secret), nil, nil
+}
+
+func programmaticAccessTokenPlaintext(secret *snowflake.ProgrammaticAccessTokenSecret) []*v2.PlaintextData {
+	return []*v2.PlaintextData{
+		{
+			Name:        plaintextKeyToken,
+			Description: fmt.Sprintf("Secret of programmatic access token %s", secret.Name),
+			Bytes:       []byte(secret.Secret),
+		},
+	}
+}
+
+// programmaticAccessTokenDaysToExpiry converts a requested expiry to DAYS_TO_EXPIRY, rounding down
+// so the token never outlives the request.
+func programmaticAccessTokenDaysToExpiry(expiresAt, now time.Time) (int, error) {
+	days := int(expiresAt.Sub(now.Add(programmaticAccessTokenExpiryMargin)) / (24 * time.Hour))
+	if days < 1 || days > programmaticAccessTokenMaxDays {
+		return 0, status.Errorf(codes.InvalidArgument,
+			"baton-snowflake: programmatic access token expiry must be between 1 and %d days", programmaticAccessTokenMaxDays)
+	}
+	return days, nil
+}
+
+// programmaticAccessTokenIssueName names an issued token after the issuance request, so a retried
+// request fails on the existing name instead of minting a second token.
+func programmaticAccessTokenIssueName(requestID string) string {
+	return fmt.Sprintf("BATON_%s", strings.ToUpper(strings.ReplaceAll(requestID, "-", "_")))
+}
+
+// tokenIssuingUserBuilder is the user builder registered when secrets are synced. It adds
+// programmatic access token issuance, which needs the programmatic_access_token resource type
+// (and its Delete) to be registered for the tokens it returns.
+type tokenIssuingUserBuilder struct {
+	*userBuilder
+}
+
+// IssueCapabilityDetails advertises token issuance. The optional scope is the token's role
+// restriction; Snowflake accepts at most one role.
+func (o *tokenIssuingUserBuilder) IssueCapabilityDetails(_ context.Context) (*v2.CredentialDetailsCredentialIssue, annotations.Annotations, error) {
+	return v2.CredentialDetailsCredentialIssue_builder{
+		Options: []*v2.CredentialIssueOptionDescriptor{
+			v2.CredentialIssueOptionDescriptor_builder{
+				Option:              v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_TOKEN,
+				CustomScopesAllowed: true,
+				Expiry: v2.IssuanceExpiryCapability_builder{
+					Min: durationpb.New(24*time.Hour + programmaticAccessTokenExpiryMargin),
+					Max: durationpb.New(programmaticAccessTokenMaxDays*24*time.Hour + programmaticAccessTokenExpiryMargin),
+				}.Build(),
+				ResourceMode:         v2.CredentialResourceMode_CREDENTIAL_RESOURCE_MODE_DISCOVERABLE,
+				SecretResourceTypeId: programmaticAccessTokenResourceType.Id,
+			}.Build(),
+		},
+		PreferredOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_TOKEN,
+	}.Build(), nil, nil
+}
+
+// Issue adds a programmatic access token to the user (ALTER USER ... ADD PROGRAMMATIC ACCESS
+// TOKEN) and returns it as a secret resource along with its secret. The statement returns only
+// the token's name and secret, so the resource is built from those and the properties the token
+// was added with; its expiry is computed from the connector's clock until the next sync.
+func (o *tokenIssuingUserBuilder) Issue(ctx context.Context, input *connectorbuilder.CredentialIssueInput) (*connectorbuilder.CredentialIssueOutput, error) {
+	username := input.IdentityID.GetResource()
+	now := time.Now()
+	opts := &snowflake.AddProgrammaticAccessTokenOptions{
+		Comment: fmt.Sprintf("Issued by baton-snowflake for request %s", input.RequestID),
+	}
+
+	scopes := input.CredentialOptions.GetToken().GetScopes()
+	if len(scopes) > 1 {
+		return nil, status.Error(codes.InvalidArgument, "baton-snowflake: a programmatic access token can be restricted to at most one role")
+	}
+	if len(scopes) == 1 {
+		opts.RoleRestriction = scopes[0]
+	}
+
+	if input.ExpiresAt != nil {
+		days, err := programmaticAccessTokenDaysToExpiry(input.ExpiresAt.AsTime(), now)
+		if err != nil {
+			return nil, err
+		}
+		opts.DaysToExpiry = days
+	}
+
+	secret, err := o.client.AddProgrammaticAccessToken(ctx, username, programmaticAccessTokenIssueName(input.RequestID), opts)
+	if err != nil {
+		return nil, wrapError(err, "failed to add programmatic access token")
+	}
+
+	days := opts.DaysToExpiry
+	if days == 0 {
+		days = programmaticAccessTokenDefaultDays
+	}
+	token := &snowflake.ProgrammaticAccessToken{
+		CreatedOn:       now,
+		Name:            secret.Name,
+		UserName:        username,
+		RoleRestriction: opts.RoleRestriction,
+		ExpiresAt:       now.AddDate(0, 0, days),
+		Status:          snowflake.ProgrammaticAccessTokenStatusActive,
+		Comment:         opts.Comment,
+	}
+
+	resource, err := programmaticAccessTokenResource(token, input.IdentityID)
+	if err != nil {
+		return nil, wrapError(err, "failed to create programmatic access token resource")
+	}
+
+	return &connectorbuilder.CredentialIssueOutput{
+		Secret:        resource,
+		PlaintextData: programmaticAccessTokenPlaintext(secret),
+		ResourceMode:  v2.CredentialResourceMode_CREDENTIAL_RESOURCE_MODE_DISCOVERABLE,
+	}, nil
+}
+
+func newTokenIssuingUserBuilder(client *snowflake.Client, syncMfaMethods, linkOrganizationUsers bool, lookups profileLookups) *tokenIssuingUserBuilder {
+	return &tokenIssuingUserBuilder{
+		userBuilder: newUserBuilder(client, true, syncMfaMethods, linkOrganizationUsers, lookups),
+	}
+}
+
+func newProgrammaticAccessTokenBuilder(client *snowflake.Client) *programmaticAccessTokenBuilder {
+	return &programmaticAccessTokenBuilder{
+		client: client,
+	}
+}
@@ -0,0 +1,107 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+func TestProgrammaticAccessTokenResource(t *testing.T) {
+	userID := &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "SVC_ETL"}
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	resource, err := programmaticAccessTokenResource(&snowflake.ProgrammaticAccessToken{
+		Name:            "ETL_TOKEN",
+		RoleRestriction: "LOADER",
+		ExpiresAt:       expiresAt,
+		Status:          "DISABLED",
+		CreatedBy:       "ADMIN",
+	}, userID)
+	require.NoError(t, err)
+
+	require.Equal(t, "SVC_ETL/ETL_TOKEN", resource.GetId().GetResource())
+	require.Equal(t, "LOADER", resource.GetProfile().AsMap()["role_restriction"])
+	require.Equal(t, v2.Status_RESOURCE_STATUS_DISABLED, resource.GetStatus().GetStatus())
+
+	trait := &v2.SecretTrait{}
+	found := false
+	for _, a := range resource.GetAnnotations() {
+		if a.MessageIs(trait) {
+			require.NoError(t, a.UnmarshalTo(trait))
+			found = true
+		}
+	}
+	require.True(t, found)
+	require.Equal(t, expiresAt, trait.GetExpiresAt().AsTime())
+	require.Equal(t, "SVC_ETL", trait.GetIdentityId().GetResource())
+	require.Equal(t, "ADMIN", trait.GetCreatedById().GetResource())
+}
+
+func TestSplitProgrammaticAccessTokenID(t *testing.T) {
+	username, tokenName, err := splitProgrammaticAccessTokenID(programmaticAccessTokenID("SVC/ETL", "ETL_TOKEN"))
+	require.NoError(t, err)
+	require.Equal(t, "SVC/ETL", username)
+	require.Equal(t, "ETL_TOKEN", tokenName)
+
+	for _, id := range []string{"", "SVC_ETL", "/ETL_TOKEN", "SVC_ETL/"} {
+		_, _, err := splitProgrammaticAccessTokenID(id)
+		require.Error(t, err, id)
+	}
+}
+
+func TestProgrammaticAccessTokenDaysToExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	days, err := programmaticAccessTokenDaysToExpiry(now.Add(30*24*time.Hour), now)
+	require.NoError(t, err)
+	require.Equal(t, 29, days, "rounds down after the margin so Snowflake's expiry stays within the request")
+
+	days, err = programmaticAccessTokenDaysToExpiry(now.Add(30*24*time.Hour+programmaticAccessTokenExpiryMargin), now)
+	require.NoError(t, err)
+	require.Equal(t, 30, days)
+
+	_, err = programmaticAccessTokenDaysToExpiry(now.Add(12*time.Hour), now)
+	require.Error(t, err)
+
+	_, err = programmaticAccessTokenDaysToExpiry(now.Add(400*24*time.Hour), now)
+	require.Error(t, err)
+}
+
+func TestIssue_BuildsTokenFromAddResponse(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, []string{"token_name", "token_secret"}, [][]string{{"BATON_REQ_1", "s3cr3t"}}, &statements)
+	defer server.Close()
+
+	builder := newTokenIssuingUserBuilder(newTestConnector(t, server.URL).Client, false, false, profileLookups{})
+	userID := &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "SVC_ETL"}
+	output, err := builder.Issue(context.Background(), &connectorbuilder.CredentialIssueInput{
+		IdentityID: userID,
+		CredentialOptions: v2.CredentialIssueOptions_builder{
+			Token: v2.CredentialIssueOptions_Token_builder{Scopes: []string{"ANALYST"}}.Build(),
+		}.Build(),
+		RequestID: "req-1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{`ALTER USER "SVC_ETL" ADD PROGRAMMATIC ACCESS TOKEN "BATON_REQ_1" ROLE_RESTRICTION = 'ANALYST' COMMENT = 'Issued by baton-snowflake for request req-1';`}, statements,
+		"the token is not re-listed after it is added")
+
+	require.Equal(t, "SVC_ETL/BATON_REQ_1", output.Secret.Id.Resource)
+	profile := output.Secret.GetProfile().AsMap()
+	require.Equal(t, "ANALYST", profile["role_restriction"])
+	require.Equal(t, snowflake.ProgrammaticAccessTokenStatusActive, profile["status"])
+	require.Equal(t, []byte("s3cr3t"), output.PlaintextData[0].Bytes)
+
+	trait := &v2.SecretTrait{}
+	annos := annotations.Annotations(output.Secret.GetAnnotations())
+	ok, err := annos.Pick(trait)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().AddDate(0, 0, programmaticAccessTokenDefaultDays), trait.GetExpiresAt().AsTime(), time.Minute)
+}
@@ -0,0 +1,80 @@
+package connector
+
+import (
+	"context"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+// readerAccountBuilder syncs the reader accounts this account manages. They are the principals
+// of share "consumer" grants.
+type readerAccountBuilder struct {
+	resourceType *v2.ResourceType
+	client       *snowflake.Client
+}
+
+func (o *readerAccountBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+func readerAccountResource(account *snowflake.ManagedAccount) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		profileKeyName:    account.Name,
+		"locator":         account.Locator,
+		"cloud":           account.Cloud,
+		"region":          account.Region,
+		"url":             account.URL,
+		profileKeyComment: account.Comment,
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithResourceProfile(profile),
+	}
+	if !account.CreatedOn.IsZero() {
+		opts = append(opts, rs.WithResourceCreatedAt(account.CreatedOn))
+	}
+
+	return rs.NewResource(account.Name, readerAccountResourceType, account.Name, opts...)
+}
+
+func (o *readerAccountBuilder) List(ctx context.Context, _ *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	accounts, err := o.client.ListReaderAccounts(ctx)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			ctxzap.Extract(ctx).Debug("ListReaderAccounts: insufficient privileges, skipping", zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list reader accounts")
+	}
+
+	var resources []*v2.Resource
+	for i := range accounts {
+		resource, err := readerAccountResource(&accounts[i])
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create reader account resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *readerAccountBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+func (o *readerAccountBuilder) Grants(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+func newReaderAccountBuilder(client *snowflake.Client) *readerAccountBuilder {
+	return &readerAccountBuilder{
+		resourceType: readerAccountResourceType,
+		client:       client,
+	}
+}
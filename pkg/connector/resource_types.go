@@ -39,12 +39,87 @@ var (
 		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_SECRET},
 		Annotations: getSkipEntitlementsAnnotation(),
 	}
+	programmaticAccessTokenResourceType = &v2.ResourceType{
+		Id:          "programmatic_access_token",
+		DisplayName: "Programmatic Access Token",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_SECRET},
+		Annotations: getSkipEntitlementsAnnotation(),
+	}
 	integrationResourceType = &v2.ResourceType{
 		Id:          "integration",
 		DisplayName: "Integration",
 		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_APP},
 		Annotations: getSkipEntitlementsAnnotation(),
 	}
+	networkPolicyResourceType = &v2.ResourceType{
+		Id:          "network_policy",
+		DisplayName: "Network Policy",
+		Annotations: getSkipEntitlementsAnnotation(),
+	}
+	networkRuleResourceType = &v2.ResourceType{
+		Id:          "network_rule",
+		DisplayName: "Network Rule",
+		Annotations: getSkipEntitlementsAnnotation(),
+	}
+	authenticationPolicyResourceType = &v2.ResourceType{
+		Id:          "authentication_policy",
+		DisplayName: "Authentication Policy",
+	}
+	passwordPolicyResourceType = &v2.ResourceType{
+		Id:          "password_policy",
+		DisplayName: "Password Policy",
+	}
+	sessionPolicyResourceType = &v2.ResourceType{
+		Id:          "session_policy",
+		DisplayName: "Session Policy",
+	}
+	maskingPolicyResourceType = &v2.ResourceType{
+		Id:          "masking_policy",
+		DisplayName: "Masking Policy",
+	}
+	rowAccessPolicyResourceType = &v2.ResourceType{
+		Id:          "row_access_policy",
+		DisplayName: "Row Access Policy",
+	}
+	tagResourceType = &v2.ResourceType{
+		Id:          "tag",
+		DisplayName: "Tag",
+	}
+	shareResourceType = &v2.ResourceType{
+		Id:          "share",
+		DisplayName: "Share",
+	}
+	readerAccountResourceType = &v2.ResourceType{
+		Id:          "reader_account",
+		DisplayName: "Reader Account",
+		Annotations: getSkipEntitlementsAnnotation(),
+	}
+	applicationResourceType = &v2.ResourceType{
+		Id:          "application",
+		DisplayName: "Application",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_APP},
+	}
+	applicationRoleResourceType = &v2.ResourceType{
+		Id:          "application_role",
+		DisplayName: "Application Role",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_ROLE},
+	}
+	accountResourceType = &v2.ResourceType{
+		Id:          "account",
+		DisplayName: "Account",
+		Annotations: getSkipEntitlementsAnnotation(),
+	}
+	organizationUserResourceType = &v2.ResourceType{
+		Id:          "organization_user",
+		DisplayName: "Organization User",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_USER},
+		Annotations: getSkipEntitlementsAnnotation(),
+	}
+	organizationUserGroupResourceType = &v2.ResourceType{
+		Id:          "organization_user_group",
+		DisplayName: "Organization User Group",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+	}
 	licenseResourceType = &v2.ResourceType{
 		Id:          "license",
 		DisplayName: "License",
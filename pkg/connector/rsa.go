@@ -2,7 +2,13 @@ package connector
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"strings"
 	"time"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -19,10 +25,69 @@ const (
 	RsaIndex2 RsaIndex = 2
 )
 
+// rsaKeyBits is the size of the key pairs the connector generates.
+const rsaKeyBits = 2048
+
 func (i RsaIndex) String() string {
 	return fmt.Sprintf("rsa_%d", i)
 }
 
+// property is the user property holding the slot's public key.
+func (i RsaIndex) property() string {
+	if i == RsaIndex2 {
+		return snowflake.RsaPublicKey2Property
+	}
+	return snowflake.RsaPublicKeyProperty
+}
+
+// rotationRsaIndex picks the slot a rotated key goes into: an empty slot if there is one,
+// otherwise the slot that was set longest ago.
+func rotationRsaIndex(user *snowflake.UserRsa) RsaIndex {
+	switch {
+	case user.RsaPublicKeyLastSetTime == nil:
+		return RsaIndex1
+	case user.RsaPublicKeyLastSetTime2 == nil:
+		return RsaIndex2
+	case user.RsaPublicKeyLastSetTime2.Before(*user.RsaPublicKeyLastSetTime):
+		return RsaIndex2
+	default:
+		return RsaIndex1
+	}
+}
+
+// generateRsaKeyPair returns a new private key as PKCS#8 PEM, and its public key as the base64
+// DER body (no PEM armor) that RSA_PUBLIC_KEY takes.
+func generateRsaKeyPair(bits int) (string, string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return "", "", err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})
+	return string(privatePEM), base64.StdEncoding.EncodeToString(publicDER), nil
+}
+
+// generatedCredentialRotationDetails describes rotations where the connector or Snowflake
+// generates the new credential. The SDK reports one credential manager's details for the whole
+// connector, so every credential manager returns these.
+func generatedCredentialRotationDetails() *v2.CredentialDetailsCredentialRotation {
+	return &v2.CredentialDetailsCredentialRotation{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+	}
+}
+
 type rsaBuilder struct {
 	client *snowflake.Client
 }
@@ -33,12 +98,17 @@ func (o *rsaBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
 
 func rsaResource(_ context.Context, user *snowflake.UserRsa, rsaIdx RsaIndex, id *v2.ResourceId) (*v2.Resource, error) {
 	var rsaTime *time.Time
+	var publicKey, fingerprint string
 
 	switch rsaIdx {
 	case RsaIndex1:
 		rsaTime = user.RsaPublicKeyLastSetTime
+		publicKey = user.RsaPublicKey
+		fingerprint = user.RsaPublicKeyFingerprint
 	case RsaIndex2:
 		rsaTime = user.RsaPublicKeyLastSetTime2
+		publicKey = user.RsaPublicKey2
+		fingerprint = user.RsaPublicKeyFingerprint2
 	default:
 		return nil, fmt.Errorf("invalid rsa index: %d", rsaIdx)
 	}
@@ -49,11 +119,22 @@ func rsaResource(_ context.Context, user *snowflake.UserRsa, rsaIdx RsaIndex, id
 
 	secretTraits := []rs.SecretTraitOption{
 		rs.WithSecretLastUsedAt(*rsaTime),
+		rs.WithSecretCreatedAt(*rsaTime),
 		rs.WithSecretCreatedByID(id),
 		rs.WithSecretType(v2.SecretTrait_CREDENTIAL_TYPE_ASYMMETRIC_KEY),
 		rs.WithSecretDetail("snowflake.rsa_key_pair"),
 	}
 
+	// SecretTrait has no fields for these, so they are reported in the resource profile.
+	profile := map[string]interface{}{
+		"property":     rsaIdx.property(),
+		"fingerprint":  fingerprint,
+		"key_age_days": int(time.Since(*rsaTime).Hours() / 24),
+	}
+	if bits := rsaPublicKeyBits(publicKey); bits > 0 {
+		profile["key_size_bits"] = bits
+	}
+
 	rsaId := fmt.Sprintf("%s-%s", user.Username, rsaIdx.String())
 
 	resource, err := rs.NewSecretResource(
@@ -62,6 +143,7 @@ func rsaResource(_ context.Context, user *snowflake.UserRsa, rsaIdx RsaIndex, id
 		rsaId,
 		secretTraits,
 		rs.WithParentResourceID(id),
+		rs.WithResourceProfile(profile),
 	)
 
 	if err != nil {
@@ -71,6 +153,28 @@ func rsaResource(_ context.Context, user *snowflake.UserRsa, rsaIdx RsaIndex, id
 	return resource, nil
 }
 
+// rsaPublicKeyBits returns the modulus size of an RSA public key as DESCRIBE USER shows it (base64
+// DER, with or without PEM armor), or 0 when it cannot be parsed.
+func rsaPublicKeyBits(publicKey string) int {
+	body := publicKey
+	if block, _ := pem.Decode([]byte(publicKey)); block != nil {
+		body = base64.StdEncoding.EncodeToString(block.Bytes)
+	}
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return 0
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return 0
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return 0
+	}
+	return rsaKey.N.BitLen()
+}
+
 // List returns all the users from the database as resource objects.
 // Users include a UserTrait because they are the 'shape' of a standard user.
 func (o *rsaBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
@@ -0,0 +1,92 @@
+package connector
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+func TestRotationRsaIndex(t *testing.T) {
+	older := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(90 * 24 * time.Hour)
+
+	require.Equal(t, RsaIndex1, rotationRsaIndex(&snowflake.UserRsa{}))
+	require.Equal(t, RsaIndex1, rotationRsaIndex(&snowflake.UserRsa{RsaPublicKeyLastSetTime2: &newer}))
+	require.Equal(t, RsaIndex2, rotationRsaIndex(&snowflake.UserRsa{RsaPublicKeyLastSetTime: &newer}))
+	require.Equal(t, RsaIndex2, rotationRsaIndex(&snowflake.UserRsa{RsaPublicKeyLastSetTime: &newer, RsaPublicKeyLastSetTime2: &older}))
+	require.Equal(t, RsaIndex1, rotationRsaIndex(&snowflake.UserRsa{RsaPublicKeyLastSetTime: &older, RsaPublicKeyLastSetTime2: &newer}))
+}
+
+func TestGenerateRsaKeyPair(t *testing.T) {
+	privatePEM, publicKey, err := generateRsaKeyPair(rsaKeyBits)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode([]byte(privatePEM))
+	require.NotNil(t, block)
+	require.Equal(t, "PRIVATE KEY", block.Type)
+	_, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	require.NoError(t, err)
+
+	publicDER, err := base64.StdEncoding.DecodeString(publicKey)
+	require.NoError(t, err)
+	_, err = x509.ParsePKIXPublicKey(publicDER)
+	require.NoError(t, err)
+}
+
+func TestUserRotate_SetsOlderSlot(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t,
+		[]string{"property", "value", "default", "description"},
+		[][]string{
+			{"NAME", "SVC_ETL", "null", ""},
+			{"RSA_PUBLIC_KEY_LAST_SET_TIME", "2025-06-01 00:00:00.000", "null", ""},
+			{"RSA_PUBLIC_KEY_2_LAST_SET_TIME", "2025-01-01 00:00:00.000", "null", ""},
+		},
+		&statements,
+	)
+	defer server.Close()
+
+	u := newUserBuilder(newTestConnector(t, server.URL).Client, false, false, false, profileLookups{})
+	plaintexts, _, err := u.Rotate(context.Background(), &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "SVC_ETL"}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, statements, 2)
+	require.Equal(t, `DESCRIBE USER "SVC_ETL";`, statements[0])
+	require.True(t, strings.HasPrefix(statements[1], `ALTER USER "SVC_ETL" SET RSA_PUBLIC_KEY_2 = '`), statements[1])
+
+	require.Len(t, plaintexts, 1)
+	require.Equal(t, "private_key", plaintexts[0].GetName())
+	require.Contains(t, string(plaintexts[0].GetBytes()), "BEGIN PRIVATE KEY")
+}
+
+func TestRsaResource_ProfileReportsFingerprintAgeAndSize(t *testing.T) {
+	_, publicKey, err := generateRsaKeyPair(rsaKeyBits)
+	require.NoError(t, err)
+	setAt := time.Now().Add(-91 * 24 * time.Hour)
+	userID := &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "SVC_ETL"}
+
+	resource, err := rsaResource(context.Background(), &snowflake.UserRsa{
+		Username:                 "SVC_ETL",
+		RsaPublicKeyLastSetTime2: &setAt,
+		RsaPublicKey2:            publicKey,
+		RsaPublicKeyFingerprint2: "SHA256:abc=",
+	}, RsaIndex2, userID)
+	require.NoError(t, err)
+
+	profile := resource.GetProfile().AsMap()
+	require.Equal(t, "RSA_PUBLIC_KEY_2", profile["property"])
+	require.Equal(t, "SHA256:abc=", profile["fingerprint"])
+	require.Equal(t, float64(91), profile["key_age_days"])
+	require.Equal(t, float64(rsaKeyBits), profile["key_size_bits"])
+
+	require.Zero(t, rsaPublicKeyBits("not a key"))
+}
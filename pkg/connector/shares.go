@@ -0,0 +1,193 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+// sharedTableKinds are the SHOW GRANTS TO SHARE granted_on values that expose table data.
+var sharedTableKinds = []string{"TABLE", "VIEW", "MATERIALIZED_VIEW", "EXTERNAL_TABLE", "DYNAMIC_TABLE", "ICEBERG_TABLE"}
+
+// shareBuilder syncs outbound shares: the data this account exposes to other Snowflake accounts.
+// Consumers that are reader accounts of this account are grants of the "consumer" entitlement;
+// every consumer, including accounts in other organizations, is listed in consumer_accounts.
+type shareBuilder struct {
+	resourceType *v2.ResourceType
+	client       *snowflake.Client
+}
+
+func (o *shareBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+func shareResource(share *snowflake.Share, objectGrants []snowflake.TableGrant) (*v2.Resource, error) {
+	sharedObjects := make([]string, 0, len(objectGrants))
+	sharedTables := make([]string, 0, len(objectGrants))
+	for _, og := range objectGrants {
+		kind := strings.ReplaceAll(strings.ToUpper(og.GrantedOn), " ", "_")
+		name := snowflake.SplitQualifiedName(og.Name)
+		sharedObjects = append(sharedObjects, fmt.Sprintf("%s %s", kind, strings.Join(name, ".")))
+		if snowflake.Contains(sharedTableKinds, kind) {
+			sharedTables = append(sharedTables, strings.Join(name, "."))
+		}
+	}
+	sort.Strings(sharedObjects)
+	sort.Strings(sharedTables)
+
+	profile := map[string]interface{}{
+		profileKeyName:      share.Name,
+		"database":          share.DatabaseName,
+		"owner":             share.Owner,
+		"owner_account":     share.OwnerAccount,
+		profileKeyComment:   share.Comment,
+		"consumer_accounts": stringListProfileValue(share.ConsumerAccounts()),
+		"shared_objects":    stringListProfileValue(sharedObjects),
+		"shared_tables":     stringListProfileValue(sharedTables),
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithResourceProfile(profile),
+	}
+	if !share.CreatedOn.IsZero() {
+		opts = append(opts, rs.WithResourceCreatedAt(share.CreatedOn))
+	}
+
+	return rs.NewResource(share.Name, shareResourceType, share.Name, opts...)
+}
+
+func (o *shareBuilder) List(ctx context.Context, _ *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	l := ctxzap.Extract(ctx)
+
+	shares, err := o.client.ListOutboundShares(ctx)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			l.Debug("ListOutboundShares: insufficient privileges, skipping", zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list shares")
+	}
+
+	var resources []*v2.Resource
+	for i := range shares {
+		share := &shares[i]
+		objectGrants, err := o.client.ListShareGrants(ctx, share.Name)
+		if err != nil {
+			if !snowflake.IsInsufficientPrivileges(err) {
+				return nil, nil, wrapError(err, "failed to list grants to share")
+			}
+			l.Debug("ListShareGrants: insufficient privileges, syncing without shared objects",
+				zap.String("share", share.Name), zap.Error(err))
+		}
+
+		resource, err := shareResource(share, objectGrants)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create share resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *shareBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return []*v2.Entitlement{
+		ent.NewAssignmentEntitlement(
+			resource,
+			consumerEntitlement,
+			ent.WithGrantableTo(readerAccountResourceType),
+			ent.WithDescription(fmt.Sprintf("Consumes share %s", resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s consumer", resource.DisplayName)),
+		),
+		ent.NewAssignmentEntitlement(
+			resource,
+			ownerEntitlement,
+			ent.WithGrantableTo(accountRoleResourceType),
+			ent.WithDescription(fmt.Sprintf("Is owned by %s", resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("Is owner of %s", resource.DisplayName)),
+		),
+	}, nil, nil
+}
+
+func (o *shareBuilder) Grants(ctx context.Context, resource *v2.Resource, opts rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	l := ctxzap.Extract(ctx)
+
+	share, err := o.client.GetOutboundShare(ctx, resource.Id.Resource)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			l.Debug("skipping share grants: insufficient privileges to show share", zap.String("share", resource.Id.Resource))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to get share")
+	}
+	if share == nil {
+		l.Debug("share not resolvable, skipping grants", zap.String("share", resource.Id.Resource))
+		return nil, nil, nil
+	}
+
+	var grants []*v2.Grant
+	if share.Owner != "" {
+		roleID, err := rs.NewResourceID(accountRoleResourceType, share.Owner)
+		if err != nil {
+			return nil, nil, wrapError(err, "unable to create role resource id")
+		}
+		grants = append(grants, grant.NewGrant(resource, ownerEntitlement, roleID, addExpandableOpts(share.Owner)...))
+	}
+
+	consumers := share.ConsumerAccounts()
+	if len(consumers) == 0 {
+		return grants, nil, nil
+	}
+
+	readers, err := o.client.ListReaderAccounts(ctx)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			l.Debug("skipping share consumer grants: insufficient privileges to show managed accounts",
+				zap.String("share", resource.Id.Resource))
+			return grants, nil, nil
+		}
+		return nil, nil, wrapError(err, "failed to list reader accounts")
+	}
+	if len(readers) == 0 {
+		return grants, nil, nil
+	}
+
+	account, err := o.client.GetCurrentAccount(ctx, opts.Session)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to get current account")
+	}
+
+	for i := range readers {
+		reader := &readers[i]
+		for _, consumer := range consumers {
+			if !reader.MatchesConsumer(consumer, account.OrganizationName) {
+				continue
+			}
+			readerID, err := rs.NewResourceID(readerAccountResourceType, reader.Name)
+			if err != nil {
+				return nil, nil, wrapError(err, "unable to create reader account resource id")
+			}
+			grants = append(grants, grant.NewGrant(resource, consumerEntitlement, readerID))
+			break
+		}
+	}
+
+	return grants, nil, nil
+}
+
+func newShareBuilder(client *snowflake.Client) *shareBuilder {
+	return &shareBuilder{
+		resourceType: shareResourceType,
+		client:       client,
+	}
+}
@@ -0,0 +1,29 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+func TestShareResource_ListsSharedTables(t *testing.T) {
+	resource, err := shareResource(
+		&snowflake.Share{Name: "SALES_S", DatabaseName: "SALES_DB", To: "ACME.READER1, PARTNER.ACCT"},
+		[]snowflake.TableGrant{
+			{Privilege: "USAGE", GrantedOn: "DATABASE", Name: "SALES_DB"},
+			{Privilege: "SELECT", GrantedOn: "TABLE", Name: "SALES_DB.PUBLIC.ORDERS"},
+			{Privilege: "SELECT", GrantedOn: "VIEW", Name: `SALES_DB.PUBLIC."Q1.SUMMARY"`},
+		},
+	)
+	require.NoError(t, err)
+
+	profile := resource.GetProfile().AsMap()
+	require.Equal(t, []interface{}{"ACME.READER1", "PARTNER.ACCT"}, profile["consumer_accounts"])
+	require.Equal(t, []interface{}{"SALES_DB.PUBLIC.ORDERS", "SALES_DB.PUBLIC.Q1.SUMMARY"}, profile["shared_tables"])
+	require.Equal(t,
+		[]interface{}{"DATABASE SALES_DB", "TABLE SALES_DB.PUBLIC.ORDERS", "VIEW SALES_DB.PUBLIC.Q1.SUMMARY"},
+		profile["shared_objects"],
+	)
+}
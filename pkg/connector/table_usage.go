@@ -0,0 +1,134 @@
+package connector
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	tableUsageFeedID = "table_usage"
+
+	// ACCESS_HISTORY keeps a year of queries. The default covers the 90 days after which an unused
+	// table grant is usually considered stale.
+	defaultAccessHistoryLookbackDays = 90
+	maxAccessHistoryLookbackDays     = 365
+)
+
+// tableUsageFeed emits a usage event for every table a query read or wrote, from
+// SNOWFLAKE.ACCOUNT_USAGE.ACCESS_HISTORY. Tables behind a view count as used too, but the view
+// itself does not: table resources come from SHOW TABLES, which does not list views, so a usage
+// event for a view would have no resource to target.
+type tableUsageFeed struct {
+	client   *snowflake.Client
+	lookback time.Duration
+}
+
+func (f *tableUsageFeed) EventFeedMetadata(ctx context.Context) *v2.EventFeedMetadata {
+	return &v2.EventFeedMetadata{
+		Id:                  tableUsageFeedID,
+		SupportedEventTypes: []v2.EventType{v2.EventType_EVENT_TYPE_USAGE},
+	}
+}
+
+func (f *tableUsageFeed) ListEvents(
+	ctx context.Context,
+	earliestEvent *timestamppb.Timestamp,
+	pToken *pagination.StreamToken,
+) ([]*v2.Event, *pagination.StreamState, annotations.Annotations, error) {
+	position, err := parseEventCursor(pToken.Cursor)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	r, size := eventRange(earliestEvent, position, snowflake.AccessHistoryLatency, pToken.Size)
+	if floor := time.Now().Add(-f.lookback); r.Since.Before(floor) {
+		r.Since = floor
+	}
+
+	accesses, _, err := f.client.ListAccessHistory(ctx, r, size)
+	if err != nil {
+		return nil, nil, nil, wrapError(err, "failed to list access history")
+	}
+
+	var events []*v2.Event
+	var last *snowflake.AccountUsagePosition
+	for i := range accesses {
+		queryEvents, err := tableUsageEvents(&accesses[i])
+		if err != nil {
+			return nil, nil, nil, wrapError(err, "failed to create table usage events")
+		}
+		events = append(events, queryEvents...)
+		last = accesses[i].Position()
+	}
+
+	cursor, err := nextEventCursor(pToken.Cursor, last)
+	if err != nil {
+		return nil, nil, nil, wrapError(err, "failed to create access history cursor")
+	}
+
+	return events, &pagination.StreamState{Cursor: cursor, HasMore: len(accesses) == size}, nil, nil
+}
+
+// tableUsageEvents returns one event per table the query accessed, with the user who ran it as
+// actor. The role it ran as is attached as a struct annotation, since a table grant is to a role.
+func tableUsageEvents(access *snowflake.ObjectAccess) ([]*v2.Event, error) {
+	occurredAt, err := snowflake.ParseAccountUsageTime(access.QueryStartTime)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := access.AccessedObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := structpb.NewStruct(map[string]interface{}{
+		"query_id":  access.QueryID,
+		"role_name": access.RoleName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*v2.Event
+	for _, object := range objects {
+		// Only the Table domain names a synced resource; views, stages and the other domains
+		// ACCESS_HISTORY records are skipped.
+		if !strings.EqualFold(object.ObjectDomain, snowflake.AccessedObjectDomainTable) {
+			continue
+		}
+		parts := snowflake.SplitQualifiedName(object.ObjectName)
+		if len(parts) != 3 {
+			continue
+		}
+		table := eventTableResource(parts[0], parts[1], parts[2])
+		events = append(events, &v2.Event{
+			Id:         tableUsageFeedID + ":" + access.QueryID + ":" + table.GetId().GetResource(),
+			OccurredAt: timestamppb.New(occurredAt),
+			Event: &v2.Event_UsageEvent{UsageEvent: &v2.UsageEvent{
+				TargetResource: table,
+				ActorResource:  eventUserResource(access.UserName),
+			}},
+			Annotations: annotations.New(details),
+		})
+	}
+	return events, nil
+}
+
+// newTableUsageFeed reads lookbackDays of access history when it has no cursor. Zero means the
+// default; larger values than ACCESS_HISTORY keeps are capped.
+func newTableUsageFeed(client *snowflake.Client, lookbackDays int) *tableUsageFeed {
+	if lookbackDays <= 0 {
+		lookbackDays = defaultAccessHistoryLookbackDays
+	}
+	if lookbackDays > maxAccessHistoryLookbackDays {
+		lookbackDays = maxAccessHistoryLookbackDays
+	}
+	return &tableUsageFeed{client: client, lookback: time.Duration(lookbackDays) * 24 * time.Hour}
+}
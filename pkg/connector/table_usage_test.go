@@ -0,0 +1,53 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTableUsageFeed_ListEvents(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t,
+		[]string{"QUERY_ID", "QUERY_START_TIME", "USER_NAME", "ROLE_NAME", "DIRECT_OBJECTS_ACCESSED", "BASE_OBJECTS_ACCESSED"},
+		[][]string{{
+			"01b2", "2025-01-02 03:04:05.000000000 +00:00", "JDOE", "ANALYST",
+			`[{"objectDomain":"View","objectName":"SALES.PUBLIC.ORDERS_V"}]`,
+			`[{"objectDomain":"Table","objectName":"SALES.PUBLIC.ORDERS"}]`,
+		}},
+		&statements,
+	)
+	defer server.Close()
+
+	feed := newTableUsageFeed(newTestConnector(t, server.URL).Client, 0)
+	events, state, _, err := feed.ListEvents(context.Background(), timestamppb.New(time.Unix(0, 0)), &pagination.StreamToken{})
+	require.NoError(t, err)
+	assert.False(t, state.HasMore)
+	require.Len(t, events, 1, "views are not table resources")
+
+	usage := events[0].GetUsageEvent()
+	assert.Equal(t, "table_usage:01b2:SALES.PUBLIC.ORDERS", events[0].GetId())
+	assert.Equal(t, tableResourceType.Id, usage.GetTargetResource().GetId().GetResourceType())
+	assert.Equal(t, "SALES.PUBLIC.ORDERS", usage.GetTargetResource().GetId().GetResource())
+	assert.Equal(t, "SALES", usage.GetTargetResource().GetParentResourceId().GetResource())
+	assert.Equal(t, "JDOE", usage.GetActorResource().GetId().GetResource())
+
+	details := &structpb.Struct{}
+	require.NoError(t, events[0].GetAnnotations()[0].UnmarshalTo(details))
+	assert.Equal(t, "ANALYST", details.GetFields()["role_name"].GetStringValue())
+
+	// The epoch earliest event is raised to the lookback window.
+	floor := time.Now().Add(-defaultAccessHistoryLookbackDays * 24 * time.Hour).UTC().Format("2006-01-02")
+	assert.Contains(t, statements[0], "a.QUERY_START_TIME >= TO_TIMESTAMP_TZ('"+floor)
+}
+
+func TestNewTableUsageFeed_CapsLookback(t *testing.T) {
+	assert.Equal(t, maxAccessHistoryLookbackDays*24*time.Hour, newTableUsageFeed(nil, 1000).lookback)
+	assert.Equal(t, 7*24*time.Hour, newTableUsageFeed(nil, 7).lookback)
+}
@@ -3,14 +3,18 @@ package connector
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
 	"github.com/conductorone/baton-snowflake/pkg/snowflake"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
@@ -78,7 +82,23 @@ func (o *tableBuilder) isDBSharedOrSystem(ctx context.Context, resource *v2.Reso
 }
 
 type tableBuilder struct {
-	client *snowflake.Client
+	client      *snowflake.Client
+	incremental *incrementalSync
+	objects     *objectFilter
+	lookups     profileLookups
+}
+
+// grantsStore is the session store table grants are cached in: the one kept between syncs with
+// incremental sync on, otherwise the sync's own.
+func (o *tableBuilder) grantsStore(ctx context.Context, opts rs.SyncOpAttrs) (sessions.SessionStore, error) {
+	ss, err := o.incremental.store(ctx, opts)
+	if err != nil {
+		return nil, wrapError(err, "failed to prepare incremental sync")
+	}
+	if ss == nil {
+		return opts.Session, nil
+	}
+	return ss, nil
 }
 
 func (o *tableBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -116,6 +136,33 @@ func tableResource(_ context.Context, table *snowflake.Table, id *v2.ResourceId,
 	return resource, nil
 }
 
+// tablePolicyProfile reports the masking policy on each column, the row access policy, and
+// whether the table's owner role also owns one of those masking policies - an owner who controls
+// both the data and its masking can unmask it for themselves.
+func tablePolicyProfile(table *snowflake.Table, attachments *snowflake.DataPolicyAttachments) map[string]interface{} {
+	columnPolicies := map[string]interface{}{}
+	rowAccessPolicy := ""
+	ownsMaskingPolicy := false
+
+	if policies := attachments.ForTable(table.DatabaseName, table.SchemaName, table.Name); policies != nil {
+		for column, policy := range policies.ColumnMaskingPolicies {
+			columnPolicies[column] = policy
+		}
+		rowAccessPolicy = policies.RowAccessPolicy
+		for _, owner := range attachments.MaskingPolicyOwners(policies) {
+			if table.Owner != "" && owner == table.Owner {
+				ownsMaskingPolicy = true
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"column_masking_policies":             columnPolicies,
+		"row_access_policy":                   rowAccessPolicy,
+		"masking_policy_owned_by_table_owner": ownsMaskingPolicy,
+	}
+}
+
 func (o *tableBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, opts rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
 	if parentResourceID == nil {
 		return nil, &rs.SyncOpResults{}, nil
@@ -168,10 +215,11 @@ func (o *tableBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId
 			sharedFlag = "shared"
 		}
 
-		// Skip INFORMATION_SCHEMA — it contains system views with no manageable grants.
+		// Skip INFORMATION_SCHEMA — it contains system views with no manageable grants — and
+		// filtered-out schemas, so their tables are never listed.
 		pushed := 0
 		for _, schema := range schemas {
-			if strings.EqualFold(schema.Name, "INFORMATION_SCHEMA") {
+			if strings.EqualFold(schema.Name, "INFORMATION_SCHEMA") || !o.objects.includesSchema(schema.Name) {
 				continue
 			}
 			bag.Push(pagination.PageState{
@@ -217,12 +265,34 @@ func (o *tableBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId
 		return nil, nil, wrapError(err, "failed to list tables in schema")
 	}
 
+	// Drop filtered-out tables before anything is fetched for them. The cursor still comes from
+	// the unfiltered page, so a page with none left advances like any other.
+	tables = slices.DeleteFunc(tables, func(table snowflake.Table) bool {
+		return !o.objects.includesTable(table.Name)
+	})
+
+	var dataPolicies *snowflake.DataPolicyAttachments
+	var objectTags *snowflake.ObjectTags
+	if !isSharedOrSystemDB && len(tables) > 0 {
+		if o.lookups.dataPolicies {
+			dataPolicies, err = o.client.GetDataPolicyAttachments(ctx, opts.Session)
+			if err != nil {
+				return nil, nil, wrapError(err, "failed to get masking and row access policy attachments")
+			}
+		}
+		if o.lookups.tags {
+			objectTags, err = o.client.GetObjectTags(ctx, opts.Session)
+			if err != nil {
+				return nil, nil, wrapError(err, "failed to get object tags")
+			}
+		}
+	}
+
 	var resources []*v2.Resource
 	for i := range tables {
-		t := &tables[i]
-		resource, err := tableResource(ctx, t, parentResourceID, isSharedOrSystemDB)
+		resource, err := tableResourceWithProfile(ctx, &tables[i], parentResourceID, isSharedOrSystemDB, dataPolicies, objectTags)
 		if err != nil {
-			return nil, nil, wrapError(err, "failed to create table resource")
+			return nil, nil, err
 		}
 		resources = append(resources, resource)
 	}
@@ -237,6 +307,85 @@ func (o *tableBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId
 	return resources, &rs.SyncOpResults{NextPageToken: nextToken}, nil
 }
 
+// tableResourceWithProfile is the table resource with the policy and tag profile fields List
+// adds. dataPolicies and objectTags are nil for tables in shared and system databases, and when
+// their resource types are not synced; their fields are left out then.
+func tableResourceWithProfile(
+	ctx context.Context,
+	table *snowflake.Table,
+	parentResourceID *v2.ResourceId,
+	isSharedOrSystemDB bool,
+	dataPolicies *snowflake.DataPolicyAttachments,
+	objectTags *snowflake.ObjectTags,
+) (*v2.Resource, error) {
+	resource, err := tableResource(ctx, table, parentResourceID, isSharedOrSystemDB)
+	if err != nil {
+		return nil, wrapError(err, "failed to create table resource")
+	}
+	if dataPolicies != nil {
+		if err := addProfileFields(resource, tablePolicyProfile(table, dataPolicies)); err != nil {
+			return nil, wrapError(err, "failed to add table policy profile fields")
+		}
+	}
+	if objectTags != nil && !objectTags.Unavailable {
+		if err := addProfileFields(resource, tableTagProfile(table, objectTags)); err != nil {
+			return nil, wrapError(err, "failed to add table tag profile fields")
+		}
+	}
+	return resource, nil
+}
+
+// Get returns the table as List builds it, or nil if it does not exist, is filtered out, or the
+// connector's role cannot see it. The id is DATABASE.SCHEMA.NAME, split as parseTableResourceID does.
+func (o *tableBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	databaseName, schemaName, tableName, err := parseTableResourceID(&v2.Resource{Id: resourceId})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !o.objects.includesDatabase(databaseName) || !o.objects.includesSchema(schemaName) || !o.objects.includesTable(tableName) {
+		return nil, nil, nil
+	}
+
+	table, err := o.client.GetTable(ctx, databaseName, schemaName, tableName)
+	if errors.Is(err, snowflake.ErrTableNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, wrapError(err, fmt.Sprintf("failed to get table %s", resourceId.Resource))
+	}
+	if table == nil {
+		return nil, nil, nil
+	}
+
+	parentDB, statusCode, err := o.client.GetDatabase(ctx, databaseName)
+	if err != nil && !snowflake.IsUnprocessableEntity(statusCode, err) {
+		return nil, nil, wrapError(err, "failed to get parent database")
+	}
+	isSharedOrSystemDB := snowflake.IsUnprocessableEntity(statusCode, err) || (parentDB != nil && parentDB.IsSharedOrSystem())
+
+	var dataPolicies *snowflake.DataPolicyAttachments
+	var objectTags *snowflake.ObjectTags
+	if !isSharedOrSystemDB && o.lookups.dataPolicies {
+		dataPolicies, err = o.client.GetTableDataPolicyAttachments(ctx, databaseName, schemaName, tableName)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to get masking and row access policy attachments")
+		}
+	}
+	if !isSharedOrSystemDB && o.lookups.tags {
+		objectTags, err = o.client.GetTableTags(ctx, databaseName, schemaName, tableName)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to get object tags")
+		}
+	}
+
+	parentResourceID := &v2.ResourceId{ResourceType: databaseResourceType.Id, Resource: databaseName}
+	resource, err := tableResourceWithProfile(ctx, table, parentResourceID, isSharedOrSystemDB, dataPolicies, objectTags)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resource, nil, nil
+}
+
 func parseTableResourceID(resource *v2.Resource) (string, string, string, error) {
 	// Prefer profile fields — they store the raw names without delimiter ambiguity.
 	// This correctly handles periods in database, schema, or table names.
@@ -331,8 +480,13 @@ func (o *tableBuilder) Entitlements(ctx context.Context, resource *v2.Resource,
 		return nil, nil, wrapError(err, "failed to get next page offset")
 	}
 
+	ss, err := o.grantsStore(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	objectKind := getObjectKind(resource)
-	tableGrants, nextCursor, err := o.client.ListTableGrants(ctx, opts.Session, databaseName, schemaName, tableName, objectKind, cursor)
+	tableGrants, nextCursor, err := o.client.ListTableGrants(ctx, ss, databaseName, schemaName, tableName, objectKind, cursor)
 	if err != nil {
 		// A table whose grants the role cannot read exposes the same surface as a shared/system
 		// table above: the owner entitlement, which is derived from the table itself.
@@ -396,8 +550,13 @@ func (o *tableBuilder) Grants(ctx context.Context, resource *v2.Resource, opts r
 		return nil, nil, wrapError(err, "failed to decode table grants page state")
 	}
 
+	ss, err := o.grantsStore(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	objectKind := getObjectKind(resource)
-	tableGrants, nextCursor, err := o.client.ListTableGrants(ctx, opts.Session, databaseName, schemaName, tableName, objectKind, state.Cursor)
+	tableGrants, nextCursor, err := o.client.ListTableGrants(ctx, ss, databaseName, schemaName, tableName, objectKind, state.Cursor)
 	if err != nil {
 		// Mirrors the shared/system short-circuit above: no visible grants rather than a failure.
 		if snowflake.IsInsufficientPrivileges(err) {
@@ -526,8 +685,11 @@ func (o *tableBuilder) Grants(ctx context.Context, resource *v2.Resource, opts r
 	return grants, &rs.SyncOpResults{}, nil
 }
 
-func newTableBuilder(client *snowflake.Client) *tableBuilder {
+func newTableBuilder(client *snowflake.Client, incremental *incrementalSync, objects *objectFilter, lookups profileLookups) *tableBuilder {
 	return &tableBuilder{
-		client: client,
+		client:      client,
+		incremental: incremental,
+		objects:     objects,
+		lookups:     lookups,
 	}
 }
@@ -372,6 +372,7 @@ func serveUnresolvedParentDatabase(t *testing.T) *httptest.Server {
 	t.Helper()
 	const schemasHandle = "schemas-handle"
 	const tablesHandle = "tables-handle"
+	const emptyHandle = "empty-handle"
 
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -402,6 +403,9 @@ func serveUnresolvedParentDatabase(t *testing.T) *httptest.Server {
 				_ = enc.Encode(map[string]interface{}{"statementHandle": schemasHandle})
 			case strings.Contains(body.Statement, "SHOW TABLES IN SCHEMA"):
 				_ = enc.Encode(map[string]interface{}{"statementHandle": tablesHandle})
+			case strings.Contains(body.Statement, " POLICIES IN ACCOUNT"),
+				strings.Contains(body.Statement, "ACCOUNT_USAGE.TAG_REFERENCES"):
+				_ = enc.Encode(map[string]interface{}{"statementHandle": emptyHandle})
 			default:
 				t.Errorf("unexpected statement: %s", body.Statement)
 				w.WriteHeader(http.StatusBadRequest)
@@ -439,6 +443,18 @@ func serveUnresolvedParentDatabase(t *testing.T) *httptest.Server {
 					},
 					"data": [][]string{{"1700000000.000000000", "MYTABLE", "SCHEMA", "DB", "TABLE", "", "SYSADMIN"}},
 				})
+			case emptyHandle:
+				// No masking or row access policies or tags in the account.
+				_ = enc.Encode(map[string]interface{}{
+					"statementHandle": emptyHandle,
+					"resultSetMetadata": map[string]interface{}{
+						"numRows": 0,
+						"rowType": []map[string]interface{}{
+							{keyName: colName, keyType: colText},
+						},
+					},
+					"data": [][]string{},
+				})
 			default:
 				t.Errorf("unexpected statement handle: %s", handle)
 				w.WriteHeader(http.StatusBadRequest)
@@ -478,7 +494,7 @@ func TestTableBuilder_List_ToleratesUnresolvedParentDatabase(t *testing.T) {
 	client, err := snowflake.New(server.URL, snowflake.JWTConfig{}, &http.Client{})
 	require.NoError(t, err)
 
-	builder := &tableBuilder{client: client}
+	builder := &tableBuilder{client: client, lookups: profileLookups{dataPolicies: true, tags: true}}
 	parentResourceID := &v2.ResourceId{ResourceType: databaseResourceType.Id, Resource: "DB"}
 
 	resources, results, err := builder.List(context.Background(), parentResourceID, rs.SyncOpAttrs{PageToken: pagination.Token{}})
@@ -502,7 +518,7 @@ func TestDatabaseBuilder_Grants_ToleratesUnresolvedDatabase(t *testing.T) {
 	require.NoError(t, err)
 
 	builder := &databaseBuilder{resourceType: databaseResourceType, client: client}
-	resource, err := databaseResource(&snowflake.Database{Name: "DB"}, false)
+	resource, err := databaseResource(&snowflake.Database{Name: "DB"}, false, true)
 	require.NoError(t, err)
 
 	grants, results, err := builder.Grants(context.Background(), resource, rs.SyncOpAttrs{PageToken: pagination.Token{}})
@@ -1026,3 +1042,55 @@ func makePartialProfileResource(t *testing.T, dbName, schemaName, tableName stri
 	require.NoError(t, err)
 	return resource
 }
+
+func TestTableBuilder_Get_ReturnsNilForMissingTable(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, []string{"name", "database_name", "schema_name", "kind", "owner"}, [][]string{}, &statements)
+	defer server.Close()
+
+	client, err := snowflake.New(server.URL, snowflake.JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	builder := newTableBuilder(client, nil, nil, profileLookups{})
+	resource, _, err := builder.Get(context.Background(), &v2.ResourceId{ResourceType: tableResourceType.Id, Resource: "DB.PUBLIC.GONE"}, nil)
+
+	require.NoError(t, err)
+	assert.Nil(t, resource)
+	require.Len(t, statements, 1)
+	assert.Contains(t, statements[0], `IN SCHEMA "DB"."PUBLIC"`)
+}
+
+func TestTableBuilder_Get_RejectsMalformedID(t *testing.T) {
+	builder := newTableBuilder(nil, nil, nil, profileLookups{})
+	_, _, err := builder.Get(context.Background(), &v2.ResourceId{ResourceType: tableResourceType.Id, Resource: "GONE"}, nil)
+	require.Error(t, err)
+}
+
+func TestTableBuilder_List_SkipsFilteredSchemas(t *testing.T) {
+	var listed []string
+	server := newSchemaListMockServer(t, schemaListMock{
+		schemasStatus: http.StatusOK,
+		schemaNames:   []string{"DEV_SCRATCH", publicSchema},
+		listedSchemas: &listed,
+	})
+	defer server.Close()
+
+	client, err := snowflake.New(server.URL, snowflake.JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	builder := newTableBuilder(client, nil, newObjectFilter(objectFilterConfig{ExcludedSchemas: []string{"*_scratch"}}), profileLookups{})
+	parentID := &v2.ResourceId{ResourceType: databaseResourceType.Id, Resource: "DB"}
+
+	_, _, err = builder.List(context.Background(), parentID, rs.SyncOpAttrs{})
+
+	require.NoError(t, err)
+	require.Len(t, listed, 1, "an excluded schema's tables are never listed")
+	assert.Contains(t, listed[0], `"DB"."PUBLIC"`)
+}
+
+func TestTableBuilder_Get_SkipsFilteredTable(t *testing.T) {
+	builder := newTableBuilder(nil, nil, newObjectFilter(objectFilterConfig{ExcludedTables: []string{"TMP_%"}}), profileLookups{})
+	resource, _, err := builder.Get(context.Background(), &v2.ResourceId{ResourceType: tableResourceType.Id, Resource: "DB.PUBLIC.TMP_LOAD"}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, resource)
+}
@@ -0,0 +1,131 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+// tagPrivileges are the SHOW GRANTS ON TAG privileges synced as entitlements. APPLY lets a
+// role set or unset the tag on objects, which changes how tag-based policies classify them.
+var tagPrivileges = []string{privilegeApply, privilegeOwner}
+
+type tagBuilder struct {
+	resourceType *v2.ResourceType
+	client       *snowflake.Client
+}
+
+func (o *tagBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+func tagResource(tag *snowflake.Tag) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		profileKeyName:    tag.Name,
+		"database":        tag.DatabaseName,
+		"schema":          tag.SchemaName,
+		"owner":           tag.Owner,
+		profileKeyComment: tag.Comment,
+		"allowed_values":  stringListProfileValue(tag.AllowedValueList()),
+	}
+
+	opts := []rs.ResourceOption{
+		rs.WithResourceProfile(profile),
+	}
+	if !tag.CreatedOn.IsZero() {
+		opts = append(opts, rs.WithResourceCreatedAt(tag.CreatedOn))
+	}
+
+	return rs.NewResource(tag.Name, tagResourceType, tag.FullyQualifiedName(), opts...)
+}
+
+// tableTagProfile reports the tags on a table, on each of its columns, and on its parent schema
+// and database, each keyed by the tag's DB.SCHEMA.NAME. Snowflake tags are inherited down that
+// hierarchy, so a table in a RESTRICTED schema is restricted even with no tag of its own.
+func tableTagProfile(table *snowflake.Table, tags *snowflake.ObjectTags) map[string]interface{} {
+	columnTags := map[string]interface{}{}
+	for column, values := range tags.ForColumns(table.DatabaseName, table.SchemaName, table.Name) {
+		columnTags[column] = stringMapProfileValue(values)
+	}
+
+	return map[string]interface{}{
+		"tags":          stringMapProfileValue(tags.ForTable(table.DatabaseName, table.SchemaName, table.Name)),
+		"column_tags":   columnTags,
+		"schema_tags":   stringMapProfileValue(tags.ForSchema(table.DatabaseName, table.SchemaName)),
+		"database_tags": stringMapProfileValue(tags.ForDatabase(table.DatabaseName)),
+	}
+}
+
+func (o *tagBuilder) List(ctx context.Context, _ *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	tags, err := o.client.ListTags(ctx)
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			ctxzap.Extract(ctx).Debug("ListTags: insufficient privileges, skipping", zap.Error(err))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list tags")
+	}
+
+	var resources []*v2.Resource
+	for i := range tags {
+		resource, err := tagResource(&tags[i])
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to create tag resource")
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, nil, nil
+}
+
+func (o *tagBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+	for _, privilege := range tagPrivileges {
+		rv = append(rv, ent.NewAssignmentEntitlement(
+			resource,
+			privilege,
+			ent.WithGrantableTo(accountRoleResourceType),
+			ent.WithDescription(fmt.Sprintf("%s privilege on tag %s", strings.ToUpper(privilege), resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, strings.ToUpper(privilege))),
+		))
+	}
+	return rv, nil, nil
+}
+
+func (o *tagBuilder) Grants(ctx context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	parts, err := schemaObjectNameParts(resource)
+	if err != nil {
+		return nil, nil, wrapError(err, "expected format: database.schema.tag")
+	}
+
+	tagGrants, err := o.client.ListTagGrants(ctx, &snowflake.Tag{
+		DatabaseName: parts[0],
+		SchemaName:   parts[1],
+		Name:         parts[2],
+	})
+	if err != nil {
+		if snowflake.IsInsufficientPrivileges(err) {
+			ctxzap.Extract(ctx).Debug("skipping tag grants: insufficient privileges to show grants",
+				zap.String("tag", resource.Id.Resource))
+			return nil, &rs.SyncOpResults{}, nil
+		}
+		return nil, nil, wrapError(err, "failed to list tag grants")
+	}
+
+	return privilegeRoleGrants(resource, tagGrants, tagPrivileges)
+}
+
+func newTagBuilder(client *snowflake.Client) *tagBuilder {
+	return &tagBuilder{
+		resourceType: tagResourceType,
+		client:       client,
+	}
+}
@@ -0,0 +1,38 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+)
+
+func TestTableTagProfile_IncludesInheritedTags(t *testing.T) {
+	tags := &snowflake.ObjectTags{
+		Databases: map[string]map[string]string{"FINANCE": {"GOV.TAGS.SENSITIVITY": "RESTRICTED"}},
+		Tables:    map[string]map[string]string{"FINANCE.PUBLIC.CUSTOMERS": {"GOV.TAGS.OWNER_TEAM": "CRM"}},
+		Columns: map[string]map[string]map[string]string{
+			"FINANCE.PUBLIC.CUSTOMERS": {"SSN": {"GOV.TAGS.PII": "TRUE"}},
+		},
+	}
+
+	profile := tableTagProfile(&snowflake.Table{DatabaseName: "FINANCE", SchemaName: "PUBLIC", Name: "CUSTOMERS"}, tags)
+	require.Equal(t, map[string]interface{}{"GOV.TAGS.OWNER_TEAM": "CRM"}, profile["tags"])
+	require.Equal(t, map[string]interface{}{"SSN": map[string]interface{}{"GOV.TAGS.PII": "TRUE"}}, profile["column_tags"])
+	require.Equal(t, map[string]interface{}{}, profile["schema_tags"])
+	require.Equal(t, map[string]interface{}{"GOV.TAGS.SENSITIVITY": "RESTRICTED"}, profile["database_tags"])
+}
+
+func TestTagResource_Profile(t *testing.T) {
+	resource, err := tagResource(&snowflake.Tag{
+		Name:          "SENSITIVITY",
+		DatabaseName:  "GOV",
+		SchemaName:    "TAGS",
+		AllowedValues: `["INTERNAL","RESTRICTED"]`,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "GOV.TAGS.SENSITIVITY", resource.GetId().GetResource())
+	require.Equal(t, []interface{}{"INTERNAL", "RESTRICTED"}, resource.GetProfile().AsMap()["allowed_values"])
+}
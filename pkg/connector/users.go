@@ -13,6 +13,7 @@ import (
 	connectorbuilder "github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/crypto"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
 	"github.com/conductorone/baton-snowflake/pkg/snowflake"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
@@ -20,10 +21,18 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// profileKeyMfaMethods lists the types of the MFA methods a user has enrolled.
+const profileKeyMfaMethods = "mfa_methods"
+
 type userBuilder struct {
 	resourceType *v2.ResourceType
 	client       *snowflake.Client
 	syncSecrets  bool
+	// syncMfaMethods lists each user's enrolled MFA methods, one statement per user.
+	syncMfaMethods bool
+	// linkOrganizationUsers adds the organization user each imported user came from to its profile.
+	linkOrganizationUsers bool
+	lookups               profileLookups
 }
 
 func (o *userBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -32,17 +41,18 @@ func (o *userBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
 
 func userResource(_ context.Context, user *snowflake.User, syncSecrets bool) (*v2.Resource, error) {
 	profile := map[string]interface{}{
-		"email":           user.Email,
-		"login":           user.Login,
-		"display_name":    user.DisplayName,
-		"first_name":      user.FirstName,
-		"last_name":       user.LastName,
-		profileKeyComment: user.Comment,
+		"email":                 user.Email,
+		"login":                 user.Login,
+		"display_name":          user.DisplayName,
+		"first_name":            user.FirstName,
+		"last_name":             user.LastName,
+		profileKeyComment:       user.Comment,
+		profileKeyMfaMethods:    stringListProfileValue(mfaMethodTypes(user.MfaMethods)),
 	}
 
 	userTraits := []rs.UserTraitOption{
 		rs.WithUserLogin(user.Login),
-		rs.WithMFAStatus(&v2.UserTrait_MFAStatus{MfaEnabled: user.HasMfa}),
+		rs.WithMFAStatus(&v2.UserTrait_MFAStatus{MfaEnabled: user.HasMfa || len(user.MfaMethods) > 0}),
 		rs.WithAccountType(getUserAccountType(user)),
 	}
 
@@ -67,7 +77,10 @@ func userResource(_ context.Context, user *snowflake.User, syncSecrets bool) (*v
 		rs.WithResourceStatus(getUserStatus(user), getUserDetailedStatus(user)),
 	}
 	if syncSecrets {
-		opts = append(opts, rs.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: rsaPublicKeyResourceType.Id}))
+		opts = append(opts,
+			rs.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: rsaPublicKeyResourceType.Id}),
+			rs.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: programmaticAccessTokenResourceType.Id}),
+		)
 	}
 	if nhiType, nhiDetail, isNHI := classifyUserNHI(user.Type); isNHI {
 		opts = append(opts, rs.WithNHIType(nhiType, nhiDetail))
@@ -88,13 +101,8 @@ func userResource(_ context.Context, user *snowflake.User, syncSecrets bool) (*v
 	return resource, nil
 }
 
-// https://docs.snowflake.com/en/sql-reference/sql/create-user#label-user-type-property
-//	TYPE = { PERSON | SERVICE | SERVICE_AGENT | LEGACY_SERVICE }
+// NHI details of the snowflake.UserType values that are not people.
 const (
-	userTypeService       = "SERVICE"
-	userTypeServiceAgent  = "SERVICE_AGENT"
-	userTypeLegacyService = "LEGACY_SERVICE"
-
 	nhiDetailService       = "snowflake.user.service"
 	nhiDetailServiceAgent  = "snowflake.user.service_agent"
 	nhiDetailLegacyService = "snowflake.user.legacy_service"
@@ -102,7 +110,7 @@ const (
 
 func getUserAccountType(user *snowflake.User) v2.UserTrait_AccountType {
 	switch strings.ToUpper(strings.TrimSpace(user.Type)) {
-	case userTypeService, userTypeServiceAgent, userTypeLegacyService:
+	case snowflake.UserTypeService, snowflake.UserTypeServiceAgent, snowflake.UserTypeLegacyService:
 		return v2.UserTrait_ACCOUNT_TYPE_SERVICE
 	}
 	return v2.UserTrait_ACCOUNT_TYPE_HUMAN
@@ -112,11 +120,11 @@ func getUserAccountType(user *snowflake.User) v2.UserTrait_AccountType {
 // SERVICE, SERVICE_AGENT, and LEGACY_SERVICE users hold a self-custodied standing credential (app registration); other types get no NHI trait.
 func classifyUserNHI(userType string) (v2.NonHumanIdentityTrait_NhiType, string, bool) {
 	switch strings.ToUpper(strings.TrimSpace(userType)) {
-	case userTypeService:
+	case snowflake.UserTypeService:
 		return v2.NonHumanIdentityTrait_NHI_TYPE_APP_REGISTRATION, nhiDetailService, true
-	case userTypeServiceAgent:
+	case snowflake.UserTypeServiceAgent:
 		return v2.NonHumanIdentityTrait_NHI_TYPE_APP_REGISTRATION, nhiDetailServiceAgent, true
-	case userTypeLegacyService:
+	case snowflake.UserTypeLegacyService:
 		return v2.NonHumanIdentityTrait_NHI_TYPE_APP_REGISTRATION, nhiDetailLegacyService, true
 	default:
 		return v2.NonHumanIdentityTrait_NHI_TYPE_UNSPECIFIED, "", false
@@ -185,6 +193,9 @@ func extractProfileFields(accountInfo *v2.AccountInfo, createReq *snowflake.Crea
 	if defaultSecondaryRolesStr, ok := pMap["default_secondary_roles"].(string); ok && defaultSecondaryRolesStr != "" {
 		createReq.DefaultSecondaryRoles = defaultSecondaryRolesStr
 	}
+	if typeStr, ok := pMap["type"].(string); ok && typeStr != "" {
+		createReq.Type = strings.ToUpper(strings.TrimSpace(typeStr))
+	}
 }
 
 // List returns all the users from the database as resource objects.
@@ -204,17 +215,42 @@ func (o *userBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 		return nil, nil, nil
 	}
 
+	if err := o.describeUsers(ctx, opts.Session, users); err != nil {
+		return nil, nil, err
+	}
+
+	if err := o.listMfaMethods(ctx, users); err != nil {
+		return nil, nil, err
+	}
+
 	if err := o.client.CacheUsers(ctx, opts.Session, users); err != nil {
 		return nil, nil, wrapError(err, "failed to seed user cache")
 	}
 
+	accountPolicy, err := o.accountNetworkPolicy(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var policyAttachments *snowflake.PolicyAttachments
+	if o.lookups.policies {
+		policyAttachments, err = o.client.GetPolicyAttachments(ctx, opts.Session)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to get policy attachments")
+		}
+	}
+
+	organizationUsers, err := o.importedOrganizationUsers(ctx, opts.Session)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var resources []*v2.Resource
 	for _, user := range users {
-		resource, err := userResource(ctx, &user, o.syncSecrets) // #nosec G601
+		resource, err := o.userResourceWithPolicies(ctx, &user, accountPolicy, policyAttachments, organizationUsers) // #nosec G601
 		if err != nil {
-			return nil, nil, wrapError(err, "failed to create user resource")
+			return nil, nil, err
 		}
-
 		resources = append(resources, resource)
 	}
 
@@ -230,6 +266,182 @@ func (o *userBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 	return resources, &rs.SyncOpResults{NextPageToken: nextCursor}, nil
 }
 
+// userResourceWithPolicies is the user resource with the policy and organization user profile
+// fields List adds. The policy fields are left out when policyAttachments is nil, and the network
+// policy fields when user network policies are not synced.
+func (o *userBuilder) userResourceWithPolicies(
+	ctx context.Context,
+	user *snowflake.User,
+	accountPolicy string,
+	policyAttachments *snowflake.PolicyAttachments,
+	organizationUsers map[string]bool,
+) (*v2.Resource, error) {
+	resource, err := userResource(ctx, user, o.syncSecrets)
+	if err != nil {
+		return nil, wrapError(err, "failed to create user resource")
+	}
+
+	extra := map[string]interface{}{}
+	if policyAttachments != nil {
+		extra = userPolicyProfile(user.Username, policyAttachments)
+	}
+	if o.lookups.networkPolicies {
+		extra[profileKeyNetworkPolicy] = user.NetworkPolicy
+		extra[profileKeyEffectiveNetworkPolicy] = effectiveNetworkPolicy(user.NetworkPolicy, accountPolicy)
+	}
+	if organizationUsers[user.Username] {
+		extra[profileKeyOrganizationUser] = user.Username
+	}
+	if err := addProfileFields(resource, extra); err != nil {
+		return nil, wrapError(err, "failed to add user policy profile fields")
+	}
+	return resource, nil
+}
+
+// Get returns the user as List builds it, or nil if there is no such user the connector's role
+// can see.
+func (o *userBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	// The user is read with SHOW USERS, as List reads it, so both build the same resource.
+	user, statusCode, err := o.client.FindUser(ctx, resourceId.Resource)
+	if err != nil {
+		if snowflake.IsUnprocessableEntity(statusCode, err) {
+			return nil, nil, nil
+		}
+		return nil, nil, wrapError(err, fmt.Sprintf("failed to get user %q", resourceId.Resource))
+	}
+	if user == nil {
+		return nil, nil, nil
+	}
+
+	users := []snowflake.User{*user}
+	if err := o.describeUsers(ctx, nil, users); err != nil {
+		return nil, nil, err
+	}
+	if err := o.listMfaMethods(ctx, users); err != nil {
+		return nil, nil, err
+	}
+
+	accountPolicy, err := o.accountNetworkPolicy(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var policyAttachments *snowflake.PolicyAttachments
+	if o.lookups.policies {
+		policyAttachments, err = o.client.GetUserPolicyAttachments(ctx, users[0].Username)
+		if err != nil {
+			return nil, nil, wrapError(err, "failed to get policy attachments")
+		}
+	}
+
+	organizationUsers, err := o.importedOrganizationUsers(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resource, err := o.userResourceWithPolicies(ctx, &users[0], accountPolicy, policyAttachments, organizationUsers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resource, nil, nil
+}
+
+// importedOrganizationUsers returns the names of the account users imported from organization
+// users, or nil when they are not linked.
+func (o *userBuilder) importedOrganizationUsers(ctx context.Context, ss sessions.SessionStore) (map[string]bool, error) {
+	if !o.linkOrganizationUsers {
+		return nil, nil
+	}
+	imported, err := o.client.GetImportedOrganizationUsers(ctx, ss)
+	if err != nil {
+		return nil, wrapError(err, "failed to list imported organization users")
+	}
+	return imported, nil
+}
+
+// describeUsers fills in the DESCRIBE USER-only fields SHOW USERS lacks (the user-level network
+// policy) when sync-user-network-policies is set. Users the connector's role cannot describe keep
+// their SHOW USERS data. Describes go through the session's user cache, so users already described
+// in this sync (by a grant lookup) are not described again.
+func (o *userBuilder) describeUsers(ctx context.Context, ss sessions.SessionStore, users []snowflake.User) error {
+	if !o.lookups.networkPolicies {
+		return nil
+	}
+
+	l := ctxzap.Extract(ctx)
+
+	for i := range users {
+		described, statusCode, err := o.client.GetUser(ctx, ss, users[i].Username)
+		if err != nil {
+			if snowflake.IsUnprocessableEntity(statusCode, err) {
+				l.Debug("cannot describe user, syncing without network policy",
+					zap.String("user_name", users[i].Username), zap.Error(err))
+				continue
+			}
+			return wrapError(err, fmt.Sprintf("failed to describe user %q", users[i].Username))
+		}
+		users[i].NetworkPolicy = described.NetworkPolicy
+	}
+
+	return nil
+}
+
+// listMfaMethods fills in the MFA methods each user has enrolled when sync-mfa-methods is set. Users
+// the connector's role cannot inspect keep only SHOW USERS' has_mfa flag.
+func (o *userBuilder) listMfaMethods(ctx context.Context, users []snowflake.User) error {
+	if !o.syncMfaMethods {
+		return nil
+	}
+
+	l := ctxzap.Extract(ctx)
+
+	for i := range users {
+		methods, statusCode, err := o.client.ListUserMfaMethods(ctx, users[i].Username)
+		if err != nil {
+			if snowflake.IsUnprocessableEntity(statusCode, err) {
+				l.Debug("cannot list mfa methods of user, syncing without them",
+					zap.String("user_name", users[i].Username), zap.Error(err))
+				continue
+			}
+			return wrapError(err, fmt.Sprintf("failed to list mfa methods of user %q", users[i].Username))
+		}
+		users[i].MfaMethods = methods
+	}
+
+	return nil
+}
+
+// mfaMethodTypes returns the type of each enrolled MFA method, e.g. PASSKEY or TOTP.
+func mfaMethodTypes(methods []snowflake.MfaMethod) []string {
+	types := make([]string, 0, len(methods))
+	for _, method := range methods {
+		types = append(types, method.Type)
+	}
+	return types
+}
+
+// accountNetworkPolicy returns the account-level network policy the user network policy fields
+// fall back to, or "" when they are not synced.
+func (o *userBuilder) accountNetworkPolicy(ctx context.Context) (string, error) {
+	if !o.lookups.networkPolicies {
+		return "", nil
+	}
+	policy, err := accountNetworkPolicy(ctx, o.client)
+	if err != nil {
+		return "", wrapError(err, "failed to get account network policy")
+	}
+	return policy, nil
+}
+
+// effectiveNetworkPolicy is the policy Snowflake enforces for a user: its own if set, otherwise
+// the account's. An empty result means the user is not IP-restricted.
+func effectiveNetworkPolicy(userPolicy, accountPolicy string) string {
+	if userPolicy != "" {
+		return userPolicy
+	}
+	return accountPolicy
+}
+
 // Entitlements always returns an empty slice for users.
 func (o *userBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
 	return nil, nil, nil
@@ -240,12 +452,16 @@ func (o *userBuilder) Grants(ctx context.Context, resource *v2.Resource, _ rs.Sy
 	return nil, nil, nil
 }
 
+// userTypes are the values the account creation "type" field accepts.
+var userTypes = []string{snowflake.UserTypePerson, snowflake.UserTypeService, snowflake.UserTypeLegacyService}
+
 // CreateAccountCapabilityDetails returns the capability details for user account provisioning.
 func (o *userBuilder) CreateAccountCapabilityDetails(ctx context.Context) (*v2.CredentialDetailsAccountProvisioning, annotations.Annotations, error) {
 	return &v2.CredentialDetailsAccountProvisioning{
 		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
 			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
 			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_ENCRYPTED_PASSWORD,
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
 		},
 		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
 	}, nil, nil
@@ -284,9 +500,36 @@ func (o *userBuilder) CreateAccount(
 	// Extract optional fields from profile (login and email are optional - only set if provided in profile)
 	extractProfileFields(accountInfo, createReq)
 
+	if createReq.Type != "" && !snowflake.Contains(userTypes, createReq.Type) {
+		return nil, nil, nil, status.Errorf(codes.InvalidArgument,
+			"baton-snowflake: type must be PERSON, SERVICE or LEGACY_SERVICE, got %q", createReq.Type)
+	}
+
 	// Handle password generation
+	// Key-pair credentials have no LocalCredentialOptions variant of their own, so they are requested
+	// explicitly with the key_pair field alongside the no-password option.
+	keyPair := accountInfo.GetProfile().GetFields()[profileKeyKeyPair].GetBoolValue()
 	var plaintextData []*v2.PlaintextData
-	if credentialOptions != nil {
+	if keyPair {
+		if credentialOptions != nil && credentialOptions.GetNoPassword() == nil {
+			return nil, nil, nil, status.Error(codes.InvalidArgument,
+				"baton-snowflake: key_pair requires the no-password credential option")
+		}
+		privateKey, publicKey, err := generateRsaKeyPair(rsaKeyBits)
+		if err != nil {
+			return nil, nil, nil, wrapError(err, "failed to generate rsa key pair")
+		}
+		createReq.RsaPublicKey = publicKey
+		plaintextData = append(plaintextData, &v2.PlaintextData{
+			Name:        "private_key",
+			Description: "Private key for the user's RSA_PUBLIC_KEY",
+			Bytes:       []byte(privateKey),
+		})
+	} else if credentialOptions != nil && credentialOptions.GetNoPassword() == nil {
+		if createReq.Type == snowflake.UserTypeService {
+			return nil, nil, nil, status.Error(codes.InvalidArgument,
+				"baton-snowflake: SERVICE users cannot have a password; use key_pair or the no-password credential option")
+		}
 		createReq.MustChangePassword = credentialOptions.GetForceChangeAtNextLogin()
 		// Generate password if random password is requested
 		if credentialOptions.GetRandomPassword() == nil && credentialOptions.GetPlaintextPassword() == nil {
@@ -450,10 +693,63 @@ func (o *userBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId, par
 	return nil, nil
 }
 
-func newUserBuilder(client *snowflake.Client, syncSecrets bool) *userBuilder {
+// RotateCapabilityDetails advertises key-pair rotation. The connector generates the key pair, so
+// no credential option applies.
+func (o *userBuilder) RotateCapabilityDetails(ctx context.Context) (*v2.CredentialDetailsCredentialRotation, annotations.Annotations, error) {
+	return generatedCredentialRotationDetails(), nil, nil
+}
+
+// Rotate generates a new RSA key pair and sets its public key into the user's empty or older
+// key-pair slot, so the key in the other slot keeps working until it is cleared with the
+// unset_rsa_public_key action. The private key is returned as PlaintextData.
+func (o *userBuilder) Rotate(
+	ctx context.Context,
+	resourceId *v2.ResourceId,
+	_ *v2.LocalCredentialOptions,
+) ([]*v2.PlaintextData, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	userName := resourceId.Resource
+	if userName == "" {
+		return nil, nil, fmt.Errorf("baton-snowflake: user name is required")
+	}
+
+	user, err := o.client.UserRsa(ctx, userName)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to describe user")
+	}
+	idx := rotationRsaIndex(user)
+
+	privateKey, publicKey, err := generateRsaKeyPair(rsaKeyBits)
+	if err != nil {
+		return nil, nil, wrapError(err, "failed to generate rsa key pair")
+	}
+
+	if err := o.client.SetUserRsaPublicKey(ctx, userName, idx.property(), publicKey); err != nil {
+		return nil, nil, wrapError(err, "failed to set rsa public key")
+	}
+
+	l.Debug("rsa key pair rotated",
+		zap.String("user_name", userName),
+		zap.String("property", idx.property()),
+	)
+
+	return []*v2.PlaintextData{
+		{
+			Name:        "private_key",
+			Description: fmt.Sprintf("Private key for %s", idx.property()),
+			Bytes:       []byte(privateKey),
+		},
+	}, nil, nil
+}
+
+func newUserBuilder(client *snowflake.Client, syncSecrets, syncMfaMethods, linkOrganizationUsers bool, lookups profileLookups) *userBuilder {
 	return &userBuilder{
-		resourceType: userResourceType,
-		client:       client,
-		syncSecrets:  syncSecrets,
+		resourceType:          userResourceType,
+		client:                client,
+		syncSecrets:           syncSecrets,
+		syncMfaMethods:        syncMfaMethods,
+		linkOrganizationUsers: linkOrganizationUsers,
+		lookups:               lookups,
 	}
 }
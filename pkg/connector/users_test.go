@@ -2,11 +2,20 @@ package connector
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/conductorone/baton-snowflake/pkg/snowflake"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestClassifyUserNHI(t *testing.T) {
@@ -134,3 +143,198 @@ func TestUserResourceNHIAnnotation(t *testing.T) {
 		t.Errorf("expected no NHI trait on person user, but found one")
 	}
 }
+
+func TestUserResourceMfaMethods(t *testing.T) {
+	user := &snowflake.User{
+		Username:   "alice",
+		MfaMethods: []snowflake.MfaMethod{{Name: "PASSKEY-1", Type: "PASSKEY"}, {Name: "TOTP-1", Type: "TOTP"}},
+	}
+	res, err := userResource(context.Background(), user, false)
+	require.NoError(t, err)
+
+	trait, err := rs.GetUserTrait(res)
+	require.NoError(t, err)
+	require.True(t, trait.GetMfaStatus().GetMfaEnabled(), "enrolled methods imply MFA even when has_mfa is false")
+
+	methods := res.GetProfile().GetFields()[profileKeyMfaMethods].GetListValue().AsSlice()
+	require.Equal(t, []any{"PASSKEY", "TOTP"}, methods)
+}
+
+// describeUserRows are the DESCRIBE USER property/value rows GetUser needs.
+func describeUserRows(name, userType string) [][]string {
+	return [][]string{
+		{"NAME", name}, {"LOGIN_NAME", name}, {"DISPLAY_NAME", name}, {"FIRST_NAME", "null"},
+		{"LAST_NAME", "null"}, {"EMAIL", "null"}, {"DISABLED", "false"}, {"SNOWFLAKE_LOCK", "false"},
+		{"DEFAULT_ROLE", "null"}, {"TYPE", userType}, {"HAS_MFA", "false"}, {"COMMENT", "null"},
+	}
+}
+
+// newCreateUserMockServer accepts the REST create-user call, capturing its body, and answers the
+// DESCRIBE USER that CreateAccount runs afterwards.
+func newCreateUserMockServer(t *testing.T, createBody *snowflake.CreateUserRequest) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/api/v2/users") {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(createBody))
+			_ = json.NewEncoder(w).Encode(map[string]any{"status": "ok"})
+			return
+		}
+
+		rows := describeUserRows("SVC_ETL", snowflake.UserTypeService)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"statementHandle":   "handle",
+			"resultSetMetadata": map[string]any{"numRows": len(rows)},
+			"data":              rows,
+		})
+	}))
+}
+
+func serviceUserAccountInfo(t *testing.T, userType string, keyPair bool) *v2.AccountInfo {
+	t.Helper()
+	profile, err := structpb.NewStruct(map[string]any{profileKeyName: "SVC_ETL", "type": userType, profileKeyKeyPair: keyPair})
+	require.NoError(t, err)
+	return &v2.AccountInfo{Profile: profile}
+}
+
+func TestCreateAccount_KeyPairServiceUser(t *testing.T) {
+	var createBody snowflake.CreateUserRequest
+	server := newCreateUserMockServer(t, &createBody)
+	defer server.Close()
+
+	u := newUserBuilder(newTestConnector(t, server.URL).Client, false, false, false, profileLookups{})
+	opts := &v2.LocalCredentialOptions{}
+	opts.SetNoPassword(&v2.LocalCredentialOptions_NoPassword{})
+
+	_, plaintexts, _, err := u.CreateAccount(context.Background(), serviceUserAccountInfo(t, "service", true), opts)
+	require.NoError(t, err)
+
+	require.Equal(t, snowflake.UserTypeService, createBody.Type)
+	require.NotEmpty(t, createBody.RsaPublicKey)
+	require.Empty(t, createBody.Password)
+	require.Equal(t, rsaKeyBits, rsaPublicKeyBits(createBody.RsaPublicKey))
+	require.Len(t, plaintexts, 1)
+	require.Equal(t, "private_key", plaintexts[0].GetName())
+}
+
+func TestCreateAccount_NoPasswordCreatesNoCredential(t *testing.T) {
+	var createBody snowflake.CreateUserRequest
+	server := newCreateUserMockServer(t, &createBody)
+	defer server.Close()
+
+	u := newUserBuilder(newTestConnector(t, server.URL).Client, false, false, false, profileLookups{})
+	opts := &v2.LocalCredentialOptions{}
+	opts.SetNoPassword(&v2.LocalCredentialOptions_NoPassword{})
+
+	_, plaintexts, _, err := u.CreateAccount(context.Background(), serviceUserAccountInfo(t, "SERVICE", false), opts)
+	require.NoError(t, err)
+
+	require.Empty(t, createBody.RsaPublicKey)
+	require.Empty(t, createBody.Password)
+	require.Empty(t, plaintexts)
+}
+
+func TestCreateAccount_RejectsKeyPairWithPassword(t *testing.T) {
+	u := newUserBuilder(nil, false, false, false, profileLookups{})
+	opts := &v2.LocalCredentialOptions{}
+	opts.SetRandomPassword(&v2.LocalCredentialOptions_RandomPassword{Length: 16})
+
+	_, _, _, err := u.CreateAccount(context.Background(), serviceUserAccountInfo(t, "PERSON", true), opts)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCreateAccount_RejectsPasswordForServiceUserAndUnknownType(t *testing.T) {
+	u := newUserBuilder(nil, false, false, false, profileLookups{})
+	opts := &v2.LocalCredentialOptions{}
+	opts.SetRandomPassword(&v2.LocalCredentialOptions_RandomPassword{Length: 16})
+
+	_, _, _, err := u.CreateAccount(context.Background(), serviceUserAccountInfo(t, "SERVICE", false), opts)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, _, _, err = u.CreateAccount(context.Background(), serviceUserAccountInfo(t, "ROBOT", false), opts)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDescribeUsers_ReusesSessionCache(t *testing.T) {
+	var describes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		describes++
+		rows := append(describeUserRows("ALICE", snowflake.UserTypePerson), []string{"NETWORK_POLICY", "CORP_ONLY"})
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"statementHandle":   "handle",
+			"resultSetMetadata": map[string]any{"numRows": len(rows)},
+			"data":              rows,
+		})
+	}))
+	defer server.Close()
+
+	client := newTestConnector(t, server.URL).Client
+	users := []snowflake.User{{Username: "ALICE"}}
+	require.NoError(t, newUserBuilder(client, false, false, false, profileLookups{}).describeUsers(context.Background(), nil, users))
+	require.Zero(t, describes, "users are only described when user network policies are synced")
+
+	builder := newUserBuilder(client, false, false, false, profileLookups{networkPolicies: true})
+	ss := &memorySessionStore{data: map[string][]byte{}}
+	for range 2 {
+		users := []snowflake.User{{Username: "ALICE"}}
+		require.NoError(t, builder.describeUsers(context.Background(), ss, users))
+		require.Equal(t, "CORP_ONLY", users[0].NetworkPolicy)
+	}
+	require.Equal(t, 1, describes)
+}
+
+func TestListMfaMethods_OnlyWhenEnabled(t *testing.T) {
+	var statements []string
+	server := newStatementRowsMockServer(t, []string{"name", "type"}, [][]string{{"TOTP-1", "TOTP"}}, &statements)
+	defer server.Close()
+	client := newTestConnector(t, server.URL).Client
+
+	users := []snowflake.User{{Username: "ALICE"}}
+	require.NoError(t, newUserBuilder(client, false, false, false, profileLookups{}).listMfaMethods(context.Background(), users))
+	require.Empty(t, statements)
+	require.Empty(t, users[0].MfaMethods)
+
+	require.NoError(t, newUserBuilder(client, false, true, false, profileLookups{}).listMfaMethods(context.Background(), users))
+	require.Equal(t, []string{`SHOW MFA METHODS FOR USER "ALICE";`}, statements)
+	require.Equal(t, []snowflake.MfaMethod{{Name: "TOTP-1", Type: "TOTP"}}, users[0].MfaMethods)
+}
+
+// TestUserGet_MatchesList checks a targeted Get reads the user the way List does, so the two build
+// the same resource, down to the SHOW USERS-only last login, password and key fields.
+func TestUserGet_MatchesList(t *testing.T) {
+	columns := []string{
+		"name", "login_name", "display_name", "first_name", "last_name", "email", "disabled", "snowflake_lock",
+		"default_role", "has_rsa_public_key", "has_password", "last_success_login", "type", "has_mfa", "comment",
+	}
+	users := [][]string{
+		{"ALICE_X", "ALICE_X", "Alice X", "", "", "", "false", "false", "", "false", "true", "1700000000.000000000", "PERSON", "false", ""},
+		{"ALICE", "ALICE", "Alice", "", "", "alice@example.com", "false", "false", "", "true", "true", "1700000100.000000000", "PERSON", "false", ""},
+	}
+	var statements []string
+	server := newStatementDispatchServer(t, func(statement string) statementResult {
+		statements = append(statements, statement)
+		if strings.HasPrefix(statement, "SHOW USERS") {
+			return statementResult{columns: columns, rows: users}
+		}
+		return statementResult{}
+	})
+	defer server.Close()
+
+	builder := newUserBuilder(newTestConnector(t, server.URL).Client, false, false, false, profileLookups{})
+	listed, _, err := builder.List(context.Background(), nil, rs.SyncOpAttrs{Session: &memorySessionStore{data: map[string][]byte{}}})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+
+	statements = nil
+	got, _, err := builder.Get(context.Background(), &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "ALICE"}, nil)
+	require.NoError(t, err)
+	for _, statement := range statements {
+		require.Equal(t, "SHOW USERS LIKE 'ALICE' LIMIT 50;", statement, "Get runs nothing else with the lookups off")
+	}
+	require.True(t, proto.Equal(listed[1], got), "Get builds the resource List builds")
+
+	got, _, err = builder.Get(context.Background(), &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "BOB"}, nil)
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
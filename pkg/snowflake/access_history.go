@@ -0,0 +1,119 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AccessedObjectDomainTable is the objectDomain ACCESS_HISTORY gives the tables SHOW TABLES lists.
+const AccessedObjectDomainTable = "Table"
+
+var objectAccessStructFieldToColumnMap = map[string]string{
+	"QueryID":               "QUERY_ID",
+	"QueryStartTime":        "QUERY_START_TIME",
+	"UserName":              "USER_NAME",
+	"RoleName":              "ROLE_NAME",
+	"DirectObjectsAccessed": "DIRECT_OBJECTS_ACCESSED",
+	"BaseObjectsAccessed":   "BASE_OBJECTS_ACCESSED",
+}
+
+type (
+	// ObjectAccess is a row of SNOWFLAKE.ACCOUNT_USAGE.ACCESS_HISTORY, one per query, joined with
+	// QUERY_HISTORY for the role that ran it. QueryStartTime is UTC text (see
+	// ParseAccountUsageTime); the accessed objects are the raw JSON arrays, read with
+	// AccessedObjects.
+	ObjectAccess struct {
+		QueryID               string
+		QueryStartTime        string
+		UserName              string
+		RoleName              string
+		DirectObjectsAccessed string
+		BaseObjectsAccessed   string
+	}
+
+	// AccessedObject is an element of DIRECT_OBJECTS_ACCESSED or BASE_OBJECTS_ACCESSED.
+	// ObjectName is the fully qualified DATABASE.SCHEMA.NAME.
+	AccessedObject struct {
+		ObjectDomain string `json:"objectDomain"`
+		ObjectName   string `json:"objectName"`
+	}
+
+	ListAccessHistoryRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (a *ObjectAccess) GetColumnName(fieldName string) string {
+	return objectAccessStructFieldToColumnMap[fieldName]
+}
+
+// Position is where a reader that stopped after this query resumes.
+func (a *ObjectAccess) Position() *AccountUsagePosition {
+	return &AccountUsagePosition{Time: a.QueryStartTime, ID: a.QueryID}
+}
+
+// AccessedObjects returns the objects the query named directly followed by the base objects
+// behind them (the tables under a view), each once.
+func (a *ObjectAccess) AccessedObjects() ([]AccessedObject, error) {
+	var objects []AccessedObject
+	seen := map[AccessedObject]bool{}
+	for _, column := range []string{a.DirectObjectsAccessed, a.BaseObjectsAccessed} {
+		if column == "" || column == rowNull {
+			continue
+		}
+		var accessed []AccessedObject
+		if err := json.Unmarshal([]byte(column), &accessed); err != nil {
+			return nil, fmt.Errorf("baton-snowflake: parse objects accessed by query %s: %w", a.QueryID, err)
+		}
+		for _, object := range accessed {
+			if !seen[object] {
+				seen[object] = true
+				objects = append(objects, object)
+			}
+		}
+	}
+	return objects, nil
+}
+
+func (r *ListAccessHistoryRawResponse) GetObjectAccesses() ([]ObjectAccess, error) {
+	var accesses []ObjectAccess
+	for _, row := range r.Data {
+		access := &ObjectAccess{}
+		if err := r.ResultSetMetadata.ParseRow(access, row); err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, *access)
+	}
+	return accesses, nil
+}
+
+// AccessHistoryLatency is how late Snowflake can add a row to ACCESS_HISTORY.
+const AccessHistoryLatency = 3 * time.Hour
+
+// ListAccessHistory reads up to limit queries in r, in (QUERY_START_TIME, QUERY_ID) order. The
+// QUERY_HISTORY join is bounded to the same times, so it does not scan the whole view. The views
+// need IMPORTED PRIVILEGES on the SNOWFLAKE database; ACCESS_HISTORY also needs Enterprise Edition.
+func (c *Client) ListAccessHistory(ctx context.Context, r *AccountUsageRange, limit int) ([]ObjectAccess, int, error) {
+	var response ListAccessHistoryRawResponse
+	statusCode, err := c.runStatement(ctx, "list access history", "", &response,
+		fmt.Sprintf(
+			"SELECT a.QUERY_ID, %s AS QUERY_START_TIME, a.USER_NAME, q.ROLE_NAME, "+
+				"TO_JSON(a.DIRECT_OBJECTS_ACCESSED) AS DIRECT_OBJECTS_ACCESSED, TO_JSON(a.BASE_OBJECTS_ACCESSED) AS BASE_OBJECTS_ACCESSED "+
+				"FROM SNOWFLAKE.ACCOUNT_USAGE.ACCESS_HISTORY a "+
+				"LEFT JOIN SNOWFLAKE.ACCOUNT_USAGE.QUERY_HISTORY q ON q.QUERY_ID = a.QUERY_ID AND %s WHERE %s "+
+				"ORDER BY a.QUERY_START_TIME, a.QUERY_ID LIMIT %d;",
+			selectAccountUsageTime("a.QUERY_START_TIME"),
+			r.timeBounds("q.START_TIME"),
+			r.where("a.QUERY_START_TIME", "a.QUERY_ID"),
+			limit,
+		),
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	accesses, err := response.GetObjectAccesses()
+	return accesses, statusCode, err
+}
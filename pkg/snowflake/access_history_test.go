@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectAccess_AccessedObjects(t *testing.T) {
+	access := &ObjectAccess{
+		QueryID:               "q1",
+		DirectObjectsAccessed: `[{"objectDomain":"View","objectName":"DB.PUBLIC.V"},{"objectDomain":"Table","objectName":"DB.PUBLIC.T"}]`,
+		BaseObjectsAccessed:   `[{"objectDomain":"Table","objectName":"DB.PUBLIC.T"},{"objectDomain":"Table","objectName":"DB.RAW.BASE"}]`,
+	}
+	objects, err := access.AccessedObjects()
+	require.NoError(t, err)
+	assert.Equal(t, []AccessedObject{
+		{ObjectDomain: "View", ObjectName: "DB.PUBLIC.V"},
+		{ObjectDomain: "Table", ObjectName: "DB.PUBLIC.T"},
+		{ObjectDomain: "Table", ObjectName: "DB.RAW.BASE"},
+	}, objects)
+
+	_, err = (&ObjectAccess{DirectObjectsAccessed: "not json"}).AccessedObjects()
+	require.Error(t, err)
+}
+
+func TestListAccessHistory_BuildsStatement(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, _, err = client.ListAccessHistory(context.Background(), &AccountUsageRange{
+		After: &AccountUsagePosition{Time: "2025-01-02 03:04:05.000000000 +00:00", ID: "01b2"},
+	}, 25)
+	require.NoError(t, err)
+	assert.Contains(t, capturedSQL, "LEFT JOIN SNOWFLAKE.ACCOUNT_USAGE.QUERY_HISTORY q ON q.QUERY_ID = a.QUERY_ID "+
+		"AND q.START_TIME >= TO_TIMESTAMP_TZ('2025-01-02 03:04:05.000000000 +00:00', ")
+	assert.Contains(t, capturedSQL, "a.QUERY_ID > '01b2'")
+	assert.Contains(t, capturedSQL, "ORDER BY a.QUERY_START_TIME, a.QUERY_ID LIMIT 25;")
+}
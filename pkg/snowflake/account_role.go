@@ -163,7 +163,59 @@ func decodeAccountRoleGranteesCursor(cursor string) (accountRoleGranteesCursor,
 // ListAccountRoleGrantees returns one page of grantees for the given role.
 // cursor is empty on the first call; subsequent calls pass the opaque cursor returned by the previous call.
 // The returned cursor is empty when all pages have been consumed.
-func (c *Client) ListAccountRoleGrantees(ctx context.Context, roleName string, cursor string) ([]AccountRoleGrantee, string, error) {
+//
+// With a session store, the role's full grantee list is cached there once the last page has been
+// read, the same way ListTableGrants caches table grants, and a first call with the list cached
+// returns it whole.
+func (c *Client) ListAccountRoleGrantees(ctx context.Context, ss sessions.SessionStore, roleName string, cursor string) ([]AccountRoleGrantee, string, error) {
+	if ss == nil {
+		return c.listAccountRoleGranteesPage(ctx, roleName, cursor)
+	}
+
+	if cursor == "" {
+		if cached, found, err := session.GetJSON[[]AccountRoleGrantee](ctx, ss, roleName, accountRoleGranteesNamespace); err == nil && found {
+			return cached, "", nil
+		}
+	}
+
+	grantees, nextCursor, err := c.listAccountRoleGranteesPage(ctx, roleName, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	accumulated := grantees
+	if cursor != "" {
+		// Not best-effort, as in listTableGrantsPartition: losing the earlier pages would promote
+		// a truncated list below.
+		previous, _, err := session.GetJSON[[]AccountRoleGrantee](ctx, ss, roleName, accountRoleGranteesPartialNamespace)
+		if err != nil {
+			return nil, "", fmt.Errorf("baton-snowflake: failed to read account role grantees pagination progress: %w", err)
+		}
+		accumulated = append(previous, grantees...)
+	}
+
+	if nextCursor == "" {
+		// Best-effort: a failure only costs the next lookup a cache miss.
+		_ = session.SetJSON(ctx, ss, roleName, accumulated, accountRoleGranteesNamespace)
+		if cursor != "" {
+			_ = session.DeleteJSON(ctx, ss, roleName, accountRoleGranteesPartialNamespace)
+		}
+	} else if err := session.SetJSON(ctx, ss, roleName, accumulated, accountRoleGranteesPartialNamespace); err != nil {
+		return nil, "", fmt.Errorf("baton-snowflake: failed to persist account role grantees pagination progress: %w", err)
+	}
+
+	return grantees, nextCursor, nil
+}
+
+// ForgetAccountRoleGrantees drops the grantees ListAccountRoleGrantees cached for the role.
+func (c *Client) ForgetAccountRoleGrantees(ctx context.Context, ss sessions.SessionStore, roleName string) error {
+	if err := session.DeleteJSON(ctx, ss, roleName, accountRoleGranteesNamespace); err != nil {
+		return err
+	}
+	return session.DeleteJSON(ctx, ss, roleName, accountRoleGranteesPartialNamespace)
+}
+
+func (c *Client) listAccountRoleGranteesPage(ctx context.Context, roleName string, cursor string) ([]AccountRoleGrantee, string, error) {
 	var response ListAccountRoleGranteesRawResponse
 	var apiErr SnowflakeError
 
@@ -160,7 +160,7 @@ func TestListAccountRoleGrantees_SinglePartition(t *testing.T) {
 	client, err := New(server.URL, JWTConfig{}, &http.Client{})
 	require.NoError(t, err)
 
-	grantees, nextCursor, err := client.ListAccountRoleGrantees(context.Background(), role, "")
+	grantees, nextCursor, err := client.ListAccountRoleGrantees(context.Background(), nil, role, "")
 	require.NoError(t, err)
 	assert.Empty(t, nextCursor, "single partition should produce no next cursor")
 	require.Len(t, grantees, 2)
@@ -188,7 +188,7 @@ func TestListAccountRoleGrantees_UnquotesGranteeName(t *testing.T) {
 	client, err := New(server.URL, JWTConfig{}, &http.Client{})
 	require.NoError(t, err)
 
-	grantees, nextCursor, err := client.ListAccountRoleGrantees(context.Background(), role, "")
+	grantees, nextCursor, err := client.ListAccountRoleGrantees(context.Background(), nil, role, "")
 	require.NoError(t, err)
 	assert.Empty(t, nextCursor)
 	require.Len(t, grantees, 3)
@@ -261,7 +261,7 @@ func TestListAccountRoles_MatchesUnquotedGranteeID(t *testing.T) {
 	granteesClient, err := New(granteesServer.URL, JWTConfig{}, &http.Client{})
 	require.NoError(t, err)
 
-	grantees, _, err := granteesClient.ListAccountRoleGrantees(context.Background(), "PARENT_ROLE", "")
+	grantees, _, err := granteesClient.ListAccountRoleGrantees(context.Background(), nil, "PARENT_ROLE", "")
 	require.NoError(t, err)
 	require.Len(t, grantees, 1)
 
@@ -289,7 +289,7 @@ func TestListAccountRoleGrantees_MultiPartition(t *testing.T) {
 	ctx := context.Background()
 
 	// Page 1: empty cursor → executes query, returns partition 0 + cursor.
-	page1, cursor1, err := client.ListAccountRoleGrantees(ctx, role, "")
+	page1, cursor1, err := client.ListAccountRoleGrantees(ctx, nil, role, "")
 	require.NoError(t, err)
 	require.Len(t, page1, 2)
 	assert.Equal(t, "alice", page1[0].GranteeName)
@@ -299,7 +299,7 @@ func TestListAccountRoleGrantees_MultiPartition(t *testing.T) {
 	assert.NotEmpty(t, cursor1)
 
 	// Page 2: cursor from page 1 → fetches ?partition=1, no further cursor.
-	page2, cursor2, err := client.ListAccountRoleGrantees(ctx, role, cursor1)
+	page2, cursor2, err := client.ListAccountRoleGrantees(ctx, nil, role, cursor1)
 	require.NoError(t, err)
 	require.Len(t, page2, 1)
 	assert.Equal(t, "bob", page2[0].GranteeName)
@@ -321,7 +321,7 @@ func TestListAccountRoleGrantees_EscapesRoleName(t *testing.T) {
 	client, err := New(server.URL, JWTConfig{}, &http.Client{})
 	require.NoError(t, err)
 
-	_, _, err = client.ListAccountRoleGrantees(context.Background(), role, "")
+	_, _, err = client.ListAccountRoleGrantees(context.Background(), nil, role, "")
 	require.NoError(t, err)
 	assert.Equal(t, `SHOW GRANTS OF ROLE "weird""role";`, capturedSQL)
 }
@@ -540,3 +540,40 @@ func TestListAccountRoles_EscapesCursor(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, `SHOW ROLES LIMIT 100 FROM 'o''brien';`, capturedSQL)
 }
+
+// TestListAccountRoleGrantees_CachesAcrossPartitions verifies that with a session store, the
+// partitions are accumulated into one cached list that a later first call returns whole, and that
+// ForgetAccountRoleGrantees drops it.
+func TestListAccountRoleGrantees_CachesAcrossPartitions(t *testing.T) {
+	const role = "MYROLE"
+	server := serveGrantees(t, "handle-cached",
+		[][]string{granteeRow(role, "USER", "alice")},
+		[][]string{granteeRow(role, "USER", "bob")},
+	)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ss := newFakeSessionStore()
+	page1, cursor1, err := client.ListAccountRoleGrantees(ctx, ss, role, "")
+	require.NoError(t, err)
+	require.Len(t, page1, 1)
+	require.NotEmpty(t, cursor1)
+
+	page2, cursor2, err := client.ListAccountRoleGrantees(ctx, ss, role, cursor1)
+	require.NoError(t, err)
+	require.Len(t, page2, 1, "each call returns only its own partition")
+	assert.Empty(t, cursor2)
+
+	cached, cachedCursor, err := client.ListAccountRoleGrantees(ctx, ss, role, "")
+	require.NoError(t, err)
+	assert.Empty(t, cachedCursor)
+	assert.Equal(t, []string{"alice", "bob"}, []string{cached[0].GranteeName, cached[1].GranteeName})
+
+	require.NoError(t, client.ForgetAccountRoleGrantees(ctx, ss, role))
+	_, cursor, err := client.ListAccountRoleGrantees(ctx, ss, role, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, cursor, "a forgotten role is read from Snowflake again")
+}
@@ -0,0 +1,91 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SNOWFLAKE.ACCOUNT_USAGE timestamps are selected as UTC text in accountUsageTimeFormat rather
+// than as Snowflake's float epoch, which loses the sub-microsecond precision a cursor needs to
+// resume exactly where it stopped.
+const (
+	accountUsageTimeFormat = "YYYY-MM-DD HH24:MI:SS.FF9 TZH:TZM"
+	accountUsageTimeLayout = "2006-01-02 15:04:05.000000000 -07:00"
+)
+
+// AccountUsagePosition is the place an ACCOUNT_USAGE reader stopped: the timestamp, as selected by
+// selectAccountUsageTime, and the id of the last row read. Rows are read in (timestamp, id) order,
+// so the next read starts strictly after it.
+type AccountUsagePosition struct {
+	Time string `json:"time"`
+	ID   string `json:"id"`
+}
+
+// ParseAccountUsageTime parses a timestamp selected by selectAccountUsageTime.
+func ParseAccountUsageTime(value string) (time.Time, error) {
+	return time.Parse(accountUsageTimeLayout, value)
+}
+
+// selectAccountUsageTime formats column as UTC text in accountUsageTimeFormat.
+func selectAccountUsageTime(column string) string {
+	return fmt.Sprintf("TO_VARCHAR(CONVERT_TIMEZONE('UTC', %s), '%s')", column, accountUsageTimeFormat)
+}
+
+// accountUsageTimeLiteral is the SQL timestamp for text in accountUsageTimeFormat.
+func accountUsageTimeLiteral(text string) string {
+	return fmt.Sprintf("TO_TIMESTAMP_TZ('%s', '%s')", escapeStringLiteral(text), accountUsageTimeFormat)
+}
+
+// AccountUsageRange selects the ACCOUNT_USAGE rows a reader reads next: those after After or,
+// without a position, at or after Since; and before Until. Snowflake fills the views in late, so
+// Until should trail the current time by the view's latency, or rows that land later with an
+// earlier timestamp would be skipped. Zero times are unbounded.
+type AccountUsageRange struct {
+	Since time.Time
+	Until time.Time
+	After *AccountUsagePosition
+}
+
+// where is the WHERE condition selecting the range, reading timeColumn and idColumn.
+func (r *AccountUsageRange) where(timeColumn, idColumn string) string {
+	var conditions []string
+	switch {
+	case r.After != nil && r.After.Time != "":
+		after := accountUsageTimeLiteral(r.After.Time)
+		conditions = append(conditions, fmt.Sprintf("(%s > %s OR (%s = %s AND %s > '%s'))",
+			timeColumn, after, timeColumn, after, idColumn, escapeStringLiteral(r.After.ID)))
+	case !r.Since.IsZero():
+		conditions = append(conditions, fmt.Sprintf("%s >= %s", timeColumn, accountUsageTimeLiteral(formatAccountUsageTime(r.Since))))
+	}
+	if !r.Until.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("%s < %s", timeColumn, accountUsageTimeLiteral(formatAccountUsageTime(r.Until))))
+	}
+	if len(conditions) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// timeBounds is where without the position's ID tiebreak: the condition keeping timeColumn within
+// the range's times, for bounding a joined view to the rows the range can match.
+func (r *AccountUsageRange) timeBounds(timeColumn string) string {
+	var conditions []string
+	switch {
+	case r.After != nil && r.After.Time != "":
+		conditions = append(conditions, fmt.Sprintf("%s >= %s", timeColumn, accountUsageTimeLiteral(r.After.Time)))
+	case !r.Since.IsZero():
+		conditions = append(conditions, fmt.Sprintf("%s >= %s", timeColumn, accountUsageTimeLiteral(formatAccountUsageTime(r.Since))))
+	}
+	if !r.Until.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("%s < %s", timeColumn, accountUsageTimeLiteral(formatAccountUsageTime(r.Until))))
+	}
+	if len(conditions) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+func formatAccountUsageTime(t time.Time) string {
+	return t.UTC().Format(accountUsageTimeLayout)
+}
@@ -0,0 +1,171 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+var (
+	applicationStructFieldToColumnMap = map[string]string{
+		structFieldCreatedOn: columnCreatedOn,
+		structFieldName:      columnName,
+		structFieldOwner:     columnOwner,
+		structFieldComment:   columnComment,
+		"SourceType":         "source_type",
+		"Source":             "source",
+		"Version":            "version",
+		"Label":              "label",
+	}
+
+	applicationRoleStructFieldToColumnMap = map[string]string{
+		structFieldCreatedOn: columnCreatedOn,
+		structFieldName:      columnName,
+		structFieldOwner:     columnOwner,
+		structFieldComment:   columnComment,
+	}
+)
+
+type (
+	// Application is an installed Snowflake Native App as returned by SHOW APPLICATIONS.
+	// Source is the application package or listing it was installed from.
+	Application struct {
+		CreatedOn  time.Time
+		Name       string
+		SourceType string
+		Source     string
+		Owner      string
+		Comment    string
+		Version    string
+		Label      string
+	}
+
+	// ApplicationRole is a role defined by an application, as returned by SHOW APPLICATION
+	// ROLES IN APPLICATION. Application is not part of the SHOW output and is set from the
+	// command that listed it.
+	ApplicationRole struct {
+		CreatedOn   time.Time
+		Name        string
+		Owner       string
+		Comment     string
+		Application string
+	}
+
+	ListApplicationsRawResponse struct {
+		StatementsApiResponseBase
+	}
+	ListApplicationRolesRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (a *Application) GetColumnName(fieldName string) string {
+	return applicationStructFieldToColumnMap[fieldName]
+}
+
+func (r *ApplicationRole) GetColumnName(fieldName string) string {
+	return applicationRoleStructFieldToColumnMap[fieldName]
+}
+
+func (r *ApplicationRole) unparsedFields() []string {
+	return []string{"Application"}
+}
+
+// FullyQualifiedName is the APPLICATION.ROLE form used as the application role's resource ID
+// and in SHOW GRANTS OF APPLICATION ROLE's role column.
+func (r *ApplicationRole) FullyQualifiedName() string {
+	return fmt.Sprintf("%s.%s", r.Application, r.Name)
+}
+
+func (r *ListApplicationsRawResponse) GetApplications() ([]Application, error) {
+	var applications []Application
+	for _, row := range r.Data {
+		application := &Application{}
+		if err := r.ResultSetMetadata.ParseRow(application, row); err != nil {
+			return nil, err
+		}
+		applications = append(applications, *application)
+	}
+	return applications, nil
+}
+
+func (r *ListApplicationRolesRawResponse) GetApplicationRoles(application string) ([]ApplicationRole, error) {
+	var roles []ApplicationRole
+	for _, row := range r.Data {
+		role := &ApplicationRole{}
+		if err := r.ResultSetMetadata.ParseRow(role, row); err != nil {
+			return nil, err
+		}
+		role.Application = application
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+// ListApplications enumerates the installed applications visible to the current role.
+func (c *Client) ListApplications(ctx context.Context) ([]Application, error) {
+	var response ListApplicationsRawResponse
+	_, err := c.runStatement(ctx, "list applications", "", &response, "SHOW APPLICATIONS;")
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetApplications()
+}
+
+// ListApplicationRoles enumerates the roles an application defines.
+func (c *Client) ListApplicationRoles(ctx context.Context, application string) ([]ApplicationRole, error) {
+	var response ListApplicationRolesRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list application roles in %s", application), "", &response,
+		fmt.Sprintf("SHOW APPLICATION ROLES IN APPLICATION %s;", quoteQualifiedName(application)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetApplicationRoles(application)
+}
+
+// ListApplicationRoleGrantees runs SHOW GRANTS OF APPLICATION ROLE, whose rows have the same
+// shape as SHOW GRANTS OF ROLE. Grantees are account roles (ROLE) or other roles of the same
+// application (APPLICATION_ROLE).
+func (c *Client) ListApplicationRoleGrantees(ctx context.Context, application, role string) ([]AccountRoleGrantee, error) {
+	var response ListAccountRoleGranteesRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list grants of application role %s.%s", application, role), "", &response,
+		fmt.Sprintf("SHOW GRANTS OF APPLICATION ROLE %s;", quoteQualifiedName(application, role)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetAccountRoleGrantees()
+}
+
+// ListApplicationGrants runs SHOW GRANTS TO APPLICATION: the privileges the consumer account
+// has granted the application itself, such as EXECUTE TASK or CREATE DATABASE on the account.
+// The rows have the same shape as SHOW GRANTS ON TABLE, so they are returned as TableGrants.
+func (c *Client) ListApplicationGrants(ctx context.Context, application string) ([]TableGrant, error) {
+	var response ListTableGrantsRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list grants to application %s", application), "", &response,
+		fmt.Sprintf("SHOW GRANTS TO APPLICATION %s;", quoteQualifiedName(application)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetTableGrants()
+}
+
+// GrantApplicationRole grants an application role to an account role.
+func (c *Client) GrantApplicationRole(ctx context.Context, application, role, accountRole string) error {
+	return c.execStatement(ctx, fmt.Sprintf("grant application role %s.%s", application, role), "",
+		fmt.Sprintf("GRANT APPLICATION ROLE %s TO ROLE %s;", quoteQualifiedName(application, role), quoteQualifiedName(accountRole)),
+	)
+}
+
+// RevokeApplicationRole revokes an application role from an account role.
+func (c *Client) RevokeApplicationRole(ctx context.Context, application, role, accountRole string) error {
+	return c.execStatement(ctx, fmt.Sprintf("revoke application role %s.%s", application, role), "",
+		fmt.Sprintf("REVOKE APPLICATION ROLE %s FROM ROLE %s;", quoteQualifiedName(application, role), quoteQualifiedName(accountRole)),
+	)
+}
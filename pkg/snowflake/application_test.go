@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantApplicationRole_QuotesNames(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	require.NoError(t, client.GrantApplicationRole(context.Background(), "CRM_APP", "app_viewer", `sales"ops`))
+	assert.Equal(t, `GRANT APPLICATION ROLE "CRM_APP"."app_viewer" TO ROLE "sales""ops";`, capturedSQL)
+
+	require.NoError(t, client.RevokeApplicationRole(context.Background(), "CRM_APP", "app_viewer", "SALES"))
+	assert.Equal(t, `REVOKE APPLICATION ROLE "CRM_APP"."app_viewer" FROM ROLE "SALES";`, capturedSQL)
+}
+
+func TestListApplicationRoleGrantees_ParsesRows(t *testing.T) {
+	server := serveRows(t,
+		[]string{"role", "granted_to", "grantee_name", "granted_by"},
+		[][]string{
+			{"CRM_APP.APP_VIEWER", "ROLE", "SALES", "CRM_APP"},
+			{"CRM_APP.APP_VIEWER", "APPLICATION_ROLE", "APP_ADMIN", "CRM_APP"},
+		},
+	)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	grantees, err := client.ListApplicationRoleGrantees(context.Background(), "CRM_APP", "APP_VIEWER")
+	require.NoError(t, err)
+	require.Len(t, grantees, 2)
+	assert.Equal(t, "SALES", grantees[0].GranteeName)
+	assert.Equal(t, "APPLICATION_ROLE", grantees[1].GranteeType)
+}
@@ -13,15 +13,23 @@ import (
 
 	"github.com/conductorone/baton-sdk/pkg/types/sessions"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/protoadapt"
 )
 
 var (
-	accountRoleNamespace        = sessions.WithPrefix("account_role")
-	userNamespace               = sessions.WithPrefix("user")
-	tableGrantsNamespace        = sessions.WithPrefix("table_grants")
-	tableGrantsPartialNamespace = sessions.WithPrefix("table_grants_partial")
+	accountRoleNamespace                = sessions.WithPrefix("account_role")
+	accountRoleGranteesNamespace        = sessions.WithPrefix("account_role_grantees")
+	accountRoleGranteesPartialNamespace = sessions.WithPrefix("account_role_grantees_partial")
+	userNamespace                       = sessions.WithPrefix("user")
+	tableGrantsNamespace                = sessions.WithPrefix("table_grants")
+	tableGrantsPartialNamespace         = sessions.WithPrefix("table_grants_partial")
+	policyAttachmentsNamespace          = sessions.WithPrefix("policy_attachments")
+	dataPolicyAttachmentsNamespace      = sessions.WithPrefix("data_policy_attachments")
+	objectTagsNamespace                 = sessions.WithPrefix("object_tags")
+	importedOrganizationUsersNamespace  = sessions.WithPrefix("imported_organization_users")
+	currentAccountNamespace             = sessions.WithPrefix("current_account")
 )
 
 const (
@@ -71,6 +79,8 @@ type (
 		Statement  string                      `json:"statement"`
 		Parameters StatementsRequestParameters `json:"parameters"`
 		Role       string                      `json:"role,omitempty"`
+		// Bindings are the values of the statement's ? placeholders, keyed by position from "1".
+		Bindings map[string]QueryParameter `json:"bindings,omitempty"`
 	}
 	QueryParameter struct {
 		Type  string `json:"type"`
@@ -83,6 +93,13 @@ type (
 	Parsable interface {
 		GetColumnName(fieldName string) string
 	}
+	// partiallyParsable is a Parsable with fields the parsed command does not return, set
+	// afterwards from another statement or the caller. ParseRow leaves them at their zero value
+	// instead of failing on the missing column.
+	partiallyParsable interface {
+		Parsable
+		unparsedFields() []string
+	}
 )
 
 func (m *ResultSetMetadata) FindRowTypeByName(name string) (bool, int, *RowType) {
@@ -150,8 +167,16 @@ func (m *ResultSetMetadata) ParseRow(s Parsable, row []string) error {
 		return fmt.Errorf("expected struct, got %s", reflected.Kind())
 	}
 
+	var unparsed []string
+	if p, ok := s.(partiallyParsable); ok {
+		unparsed = p.unparsedFields()
+	}
+
 	for i := 0; i < reflected.NumField(); i++ {
 		field := reflected.Type().Field(i)
+		if Contains(unparsed, field.Name) {
+			continue
+		}
 		columnName := s.GetColumnName(field.Name)
 
 		switch field.Type.Kind() {
@@ -227,6 +252,10 @@ func (c *Client) PostStatementRequestWithRole(ctx context.Context, queries []str
 		}
 	}
 
+	return c.postStatementRequest(ctx, body)
+}
+
+func (c *Client) postStatementRequest(ctx context.Context, body *StatementsApiRequestBody) (*http.Request, error) {
 	return c.NewRequest(
 		ctx,
 		http.MethodPost,
@@ -282,6 +311,134 @@ func (c *Client) GetStatementPartition(ctx context.Context, statementHandle stri
 	)
 }
 
+// statementResponse is a decoded SQL API response; every raw response embeds
+// StatementsApiResponseBase.
+type statementResponse interface {
+	statementBase() *StatementsApiResponseBase
+}
+
+func (r *StatementsApiResponseBase) statementBase() *StatementsApiResponseBase {
+	return r
+}
+
+// runStatement executes queries through the SQL API and decodes the completed result set into
+// response, using the same POST-then-GET sequence as the hand-written list methods. Rows of every
+// partition after the first are fetched and appended, so large results are read in full. action names
+// the statement in the returned error ("list network policies"); role is sent in the request body
+// and may be empty to run under the connector user's default role.
+//
+// Access-control denials and unavailable shared databases are joined with their sentinels (see
+// classifyStatementError), so callers can skip the object instead of failing the sync. The HTTP
+// status code of the last response is returned for callers that branch on it.
+func (c *Client) runStatement(ctx context.Context, action, role string, response any, queries ...string) (int, error) {
+	req, err := c.PostStatementRequestWithRole(ctx, queries, role)
+	if err != nil {
+		return 0, err
+	}
+
+	var submitted StatementsApiResponseBase
+	var apiErr SnowflakeError
+	resp1, err := c.Do(req, uhttp.WithJSONResponse(&submitted), uhttp.WithErrorResponse(&apiErr))
+	defer closeResponseBody(resp1)
+	if err != nil {
+		return statusCodeOf(resp1), classifyStatementError(resp1, &apiErr, action, err)
+	}
+
+	req, err = c.GetStatementResponse(ctx, submitted.StatementHandle)
+	if err != nil {
+		return 0, err
+	}
+	resp2, err := c.Do(req, uhttp.WithJSONResponse(response), uhttp.WithErrorResponse(&apiErr))
+	defer closeResponseBody(resp2)
+	if err != nil {
+		return statusCodeOf(resp2), classifyStatementError(resp2, &apiErr, action, err)
+	}
+
+	if r, ok := response.(statementResponse); ok {
+		base := r.statementBase()
+		for partitionID := 1; partitionID < len(base.ResultSetMetadata.PartitionInfo); partitionID++ {
+			req, err := c.GetStatementPartition(ctx, base.StatementHandle, partitionID)
+			if err != nil {
+				return 0, err
+			}
+			// Partitions after the first carry data only, no resultSetMetadata.
+			var partition StatementsApiResponseBase
+			resp, err := c.Do(req, uhttp.WithJSONResponse(&partition), uhttp.WithErrorResponse(&apiErr))
+			closeResponseBody(resp)
+			if err != nil {
+				return statusCodeOf(resp), classifyStatementError(resp, &apiErr, action, err)
+			}
+			base.Data = append(base.Data, partition.Data...)
+		}
+	}
+
+	return resp2.StatusCode, nil
+}
+
+// execStatement runs queries for their side effect only (ALTER, GRANT, ...), mirroring
+// SetUserDisabled: Snowflake completes these synchronously, so no result set is fetched.
+func (c *Client) execStatement(ctx context.Context, action, role string, queries ...string) error {
+	req, err := c.PostStatementRequestWithRole(ctx, queries, role)
+	if err != nil {
+		return fmt.Errorf("baton-snowflake: failed to %s: %w", action, err)
+	}
+	return c.exec(req, action)
+}
+
+// execBoundStatement is execStatement for one statement whose ? placeholders are bound, in order,
+// to values as TEXT, so the values are sent apart from the statement text.
+func (c *Client) execBoundStatement(ctx context.Context, action, role, statement string, values ...string) error {
+	bindings := make(map[string]QueryParameter, len(values))
+	for i, value := range values {
+		bindings[strconv.Itoa(i+1)] = QueryParameter{Type: "TEXT", Value: value}
+	}
+	req, err := c.postStatementRequest(ctx, &StatementsApiRequestBody{Statement: statement, Role: role, Bindings: bindings})
+	if err != nil {
+		return fmt.Errorf("baton-snowflake: failed to %s: %w", action, err)
+	}
+	return c.exec(req, action)
+}
+
+// exec sends a statement request built by execStatement or execBoundStatement.
+func (c *Client) exec(req *http.Request, action string) error {
+	var apiErr SnowflakeError
+	resp, err := c.Do(req, uhttp.WithErrorResponse(&apiErr))
+	defer closeResponseBody(resp)
+	if err != nil {
+		return classifyStatementError(resp, &apiErr, action, err)
+	}
+
+	return nil
+}
+
+// classifyStatementError turns a failed SQL API call into the error callers see: access-control
+// denials and unavailable shared databases carry their sentinels, everything else is passed
+// through dedupeAPIError unchanged.
+func classifyStatementError(resp *http.Response, apiErr *SnowflakeError, action string, err error) error {
+	if isAccessControlDenial(resp, apiErr) {
+		return uhttp.WrapErrors(
+			codes.PermissionDenied,
+			fmt.Sprintf("baton-snowflake: insufficient privileges to %s", action),
+			ErrInsufficientPrivileges, err,
+		)
+	}
+	if isSharedDatabaseUnavailable(resp, apiErr) {
+		return uhttp.WrapErrors(
+			codes.NotFound,
+			fmt.Sprintf("baton-snowflake: shared database unavailable to %s", action),
+			ErrSharedDatabaseUnavailable, err,
+		)
+	}
+	return fmt.Errorf("baton-snowflake: failed to %s: %w", action, dedupeAPIError(err))
+}
+
+func statusCodeOf(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
 func Contains[T comparable](ts []T, val T) bool {
 	for _, t := range ts {
 		if t == val {
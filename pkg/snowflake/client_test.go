@@ -143,3 +143,41 @@ func TestCreateUserREST_NonJSONAuthFailureBody(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, codes.Unauthenticated, status.Code(err))
 }
+
+func TestRunStatement_ReadsEveryPartition(t *testing.T) {
+	var partitions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		if r.Method == http.MethodPost {
+			_ = enc.Encode(map[string]any{"statementHandle": "handle"})
+			return
+		}
+
+		partition := r.URL.Query().Get("partition")
+		partitions = append(partitions, partition)
+		if partition != "" {
+			_ = enc.Encode(map[string]any{"data": [][]string{{"BLOCKED_IP_LIST", "10.0.0.1"}}})
+			return
+		}
+		_ = enc.Encode(map[string]any{
+			"statementHandle": "handle",
+			"resultSetMetadata": map[string]any{
+				"numRows":       2,
+				"rowType":       []map[string]any{{"name": "name", "type": rowTypeString}, {"name": "value", "type": rowTypeString}},
+				"partitionInfo": []map[string]any{{"rowCount": 1}, {"rowCount": 1}},
+			},
+			"data": [][]string{{"ALLOWED_IP_LIST", "192.168.1.1"}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	description, err := client.DescribeNetworkPolicy(context.Background(), "CORP")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "1"}, partitions)
+	assert.Equal(t, []string{"192.168.1.1"}, description.AllowedIPList)
+	assert.Equal(t, []string{"10.0.0.1"}, description.BlockedIPList)
+}
@@ -0,0 +1,250 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-sdk/pkg/session"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
+)
+
+// dataPolicyKinds are the policy kinds attached to tables, views and their columns.
+var dataPolicyKinds = []string{PolicyKindMasking, PolicyKindRowAccess}
+
+var dataPolicyDescriptionStructFieldToColumnMap = map[string]string{
+	structFieldName: columnName,
+	"Signature":     "signature",
+	"ReturnType":    "return_type",
+	"Body":          "body",
+}
+
+type (
+	// DataPolicyDescription is the DESCRIBE MASKING POLICY / DESCRIBE ROW ACCESS POLICY output.
+	// Body is the policy expression, which is where exemptions such as
+	// IS_ROLE_IN_SESSION('PII_READER') live.
+	DataPolicyDescription struct {
+		Name       string
+		Signature  string
+		ReturnType string
+		Body       string
+	}
+
+	// TablePolicies are the masking and row access policies attached to one table or view.
+	// Policy names are fully qualified (DB.SCHEMA.NAME).
+	TablePolicies struct {
+		// ColumnMaskingPolicies maps a column name to its masking policy.
+		ColumnMaskingPolicies map[string]string `json:"column_masking_policies,omitempty"`
+		RowAccessPolicy       string            `json:"row_access_policy,omitempty"`
+	}
+
+	// DataPolicyAttachments is every masking and row access policy attachment in the account,
+	// keyed by table (DB.SCHEMA.TABLE), plus the owner role of each attached policy.
+	DataPolicyAttachments struct {
+		Tables       map[string]*TablePolicies `json:"tables"`
+		PolicyOwners map[string]string         `json:"policy_owners"`
+	}
+
+	DescribeDataPolicyRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (d *DataPolicyDescription) GetColumnName(fieldName string) string {
+	return dataPolicyDescriptionStructFieldToColumnMap[fieldName]
+}
+
+func (r *DescribeDataPolicyRawResponse) GetDataPolicyDescription() (*DataPolicyDescription, error) {
+	for _, row := range r.Data {
+		description := &DataPolicyDescription{}
+		if err := r.ResultSetMetadata.ParseRow(description, row); err != nil {
+			return nil, err
+		}
+		return description, nil
+	}
+	return nil, nil
+}
+
+// ForTable returns the policies attached to database.schema.table, or nil when it has none.
+func (a *DataPolicyAttachments) ForTable(database, schema, table string) *TablePolicies {
+	if a == nil {
+		return nil
+	}
+	return a.Tables[fmt.Sprintf("%s.%s.%s", database, schema, table)]
+}
+
+// MaskingPolicyOwners returns the distinct owner roles of the masking policies attached to p.
+func (a *DataPolicyAttachments) MaskingPolicyOwners(p *TablePolicies) []string {
+	if a == nil || p == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var owners []string
+	for _, policy := range p.ColumnMaskingPolicies {
+		owner := a.PolicyOwners[policy]
+		if owner == "" || seen[owner] {
+			continue
+		}
+		seen[owner] = true
+		owners = append(owners, owner)
+	}
+	return owners
+}
+
+// DescribeDataPolicy returns a masking or row access policy's signature and body.
+func (c *Client) DescribeDataPolicy(ctx context.Context, policy *Policy) (*DataPolicyDescription, error) {
+	keyword, ok := policyShowKeywords[policy.Kind]
+	if !ok {
+		return nil, fmt.Errorf("baton-snowflake: unsupported policy kind %s", policy.Kind)
+	}
+
+	var response DescribeDataPolicyRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("describe %s policy %s", strings.ToLower(keyword), policy.FullyQualifiedName()), "", &response,
+		fmt.Sprintf("DESCRIBE %s POLICY %s;", keyword, quoteQualifiedName(policy.DatabaseName, policy.SchemaName, policy.Name)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetDataPolicyDescription()
+}
+
+// ListPolicyGrants runs SHOW GRANTS ON <kind> POLICY. The rows have the same shape as SHOW
+// GRANTS ON TABLE, so they are returned as TableGrants.
+func (c *Client) ListPolicyGrants(ctx context.Context, policy *Policy) ([]TableGrant, error) {
+	keyword, ok := policyShowKeywords[policy.Kind]
+	if !ok {
+		return nil, fmt.Errorf("baton-snowflake: unsupported policy kind %s", policy.Kind)
+	}
+
+	var response ListTableGrantsRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list grants on %s policy %s", strings.ToLower(keyword), policy.FullyQualifiedName()), "", &response,
+		fmt.Sprintf("SHOW GRANTS ON %s POLICY %s;", keyword, quoteQualifiedName(policy.DatabaseName, policy.SchemaName, policy.Name)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetTableGrants()
+}
+
+const dataPolicyAttachmentsCacheKey = "account"
+
+// GetDataPolicyAttachments collects every masking and row access policy attachment, keyed by
+// table. Like GetPolicyAttachments it costs one POLICY_REFERENCES query per policy and is
+// cached for the rest of the sync; policies the current role cannot see are left out.
+func (c *Client) GetDataPolicyAttachments(ctx context.Context, ss sessions.SessionStore) (*DataPolicyAttachments, error) {
+	if ss != nil {
+		if cached, found, err := session.GetJSON[*DataPolicyAttachments](ctx, ss, dataPolicyAttachmentsCacheKey, dataPolicyAttachmentsNamespace); err == nil && found {
+			return cached, nil
+		}
+	}
+
+	attachments := newDataPolicyAttachments()
+	for _, kind := range dataPolicyKinds {
+		policies, err := c.ListPolicies(ctx, kind)
+		if err != nil {
+			if IsInsufficientPrivileges(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for i := range policies {
+			policy := &policies[i]
+			attachments.PolicyOwners[policy.FullyQualifiedName()] = policy.Owner
+
+			references, err := c.ListPolicyReferences(ctx, policy)
+			if err != nil {
+				if IsInsufficientPrivileges(err) || IsSharedDatabaseUnavailable(err) {
+					continue
+				}
+				return nil, err
+			}
+			for j := range references {
+				attachments.add(kind, policy.FullyQualifiedName(), &references[j])
+			}
+		}
+	}
+
+	if ss != nil {
+		// Best-effort, like GetPolicyAttachments.
+		_ = session.SetJSON(ctx, ss, dataPolicyAttachmentsCacheKey, attachments, dataPolicyAttachmentsNamespace)
+	}
+
+	return attachments, nil
+}
+
+// GetTableDataPolicyAttachments is GetDataPolicyAttachments for one table: its masking and row
+// access policies, read with one POLICY_REFERENCES query on the table instead of one per policy,
+// and the owners of its masking policies. It is for reading a single table, so it is not cached.
+func (c *Client) GetTableDataPolicyAttachments(ctx context.Context, database, schema, table string) (*DataPolicyAttachments, error) {
+	attachments := newDataPolicyAttachments()
+
+	references, err := c.listEntityPolicyReferences(ctx, database, quoteQualifiedName(database, schema, table), "TABLE")
+	if err != nil {
+		if IsInsufficientPrivileges(err) || IsSharedDatabaseUnavailable(err) {
+			return attachments, nil
+		}
+		return nil, err
+	}
+
+	masked := false
+	for i := range references {
+		kind := strings.ToUpper(references[i].PolicyKind)
+		if Contains(dataPolicyKinds, kind) {
+			attachments.add(kind, references[i].PolicyFullyQualifiedName(), &references[i])
+			masked = masked || kind == PolicyKindMasking
+		}
+	}
+	if !masked {
+		return attachments, nil
+	}
+
+	policies, err := c.ListPolicies(ctx, PolicyKindMasking)
+	if err != nil {
+		if IsInsufficientPrivileges(err) {
+			return attachments, nil
+		}
+		return nil, err
+	}
+	for i := range policies {
+		attachments.PolicyOwners[policies[i].FullyQualifiedName()] = policies[i].Owner
+	}
+
+	return attachments, nil
+}
+
+func newDataPolicyAttachments() *DataPolicyAttachments {
+	return &DataPolicyAttachments{
+		Tables:       map[string]*TablePolicies{},
+		PolicyOwners: map[string]string{},
+	}
+}
+
+// add records the attachment of the named policy of kind that reference describes.
+func (a *DataPolicyAttachments) add(kind, policyName string, reference *PolicyReference) {
+	// Tag-based masking references carry the tag, not a table, until the tag is set on an
+	// object; they are reported through the tag instead.
+	if reference.RefEntityName == "" {
+		return
+	}
+	table := fmt.Sprintf("%s.%s.%s", reference.RefDatabaseName, reference.RefSchemaName, reference.RefEntityName)
+	if a.Tables[table] == nil {
+		a.Tables[table] = &TablePolicies{}
+	}
+	tablePolicies := a.Tables[table]
+
+	switch kind {
+	case PolicyKindMasking:
+		if reference.RefColumnName == "" {
+			return
+		}
+		if tablePolicies.ColumnMaskingPolicies == nil {
+			tablePolicies.ColumnMaskingPolicies = map[string]string{}
+		}
+		tablePolicies.ColumnMaskingPolicies[reference.RefColumnName] = policyName
+	case PolicyKindRowAccess:
+		tablePolicies.RowAccessPolicy = policyName
+	}
+}
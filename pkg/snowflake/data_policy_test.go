@@ -0,0 +1,107 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeDataPolicy_ParsesBody(t *testing.T) {
+	server := serveRows(t, []string{"name", "signature", "return_type", "body"}, [][]string{
+		{"SSN_MASK", "(VAL VARCHAR)", "VARCHAR(16777216)", "CASE WHEN IS_ROLE_IN_SESSION('PII_READER') THEN val ELSE '***' END"},
+	})
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	description, err := client.DescribeDataPolicy(context.Background(), &Policy{
+		DatabaseName: "SECURITY",
+		SchemaName:   "PUBLIC",
+		Name:         "SSN_MASK",
+		Kind:         PolicyKindMasking,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, description)
+	assert.Equal(t, "(VAL VARCHAR)", description.Signature)
+	assert.Contains(t, description.Body, "IS_ROLE_IN_SESSION('PII_READER')")
+}
+
+func TestListPolicyGrants_QuotesPolicyName(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, err = client.ListPolicyGrants(context.Background(), &Policy{
+		DatabaseName: "SECURITY",
+		SchemaName:   "Policies",
+		Name:         "REGION_FILTER",
+		Kind:         PolicyKindRowAccess,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SHOW GRANTS ON ROW ACCESS POLICY "SECURITY"."Policies"."REGION_FILTER";`, capturedSQL)
+}
+
+func TestDataPolicyAttachments_MaskingPolicyOwners(t *testing.T) {
+	attachments := &DataPolicyAttachments{
+		Tables: map[string]*TablePolicies{
+			"DB.PUBLIC.CUSTOMERS": {
+				ColumnMaskingPolicies: map[string]string{
+					"SSN":   "SECURITY.PUBLIC.SSN_MASK",
+					"EMAIL": "SECURITY.PUBLIC.EMAIL_MASK",
+				},
+				RowAccessPolicy: "SECURITY.PUBLIC.REGION_FILTER",
+			},
+		},
+		PolicyOwners: map[string]string{
+			"SECURITY.PUBLIC.SSN_MASK":   "SECURITYADMIN",
+			"SECURITY.PUBLIC.EMAIL_MASK": "SECURITYADMIN",
+		},
+	}
+
+	policies := attachments.ForTable("DB", "PUBLIC", "CUSTOMERS")
+	require.NotNil(t, policies)
+	assert.Equal(t, "SECURITY.PUBLIC.REGION_FILTER", policies.RowAccessPolicy)
+	assert.Equal(t, []string{"SECURITYADMIN"}, attachments.MaskingPolicyOwners(policies))
+	assert.Nil(t, attachments.ForTable("DB", "PUBLIC", "ORDERS"))
+}
+
+func TestGetTableDataPolicyAttachments_ReadsOnlyTheTable(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, err = client.GetTableDataPolicyAttachments(context.Background(), "SALES", "PUBLIC", "ORDERS")
+	require.NoError(t, err)
+	assert.Equal(t,
+		`SELECT * FROM TABLE("SALES".INFORMATION_SCHEMA.POLICY_REFERENCES(REF_ENTITY_NAME => '"SALES"."PUBLIC"."ORDERS"', REF_ENTITY_DOMAIN => 'table'));`,
+		capturedSQL,
+	)
+}
+
+func TestGetTableDataPolicyAttachments_KeepsDataPolicies(t *testing.T) {
+	server := serveRows(t,
+		[]string{"POLICY_DB", "POLICY_SCHEMA", "POLICY_NAME", "POLICY_KIND", "REF_DATABASE_NAME", "REF_SCHEMA_NAME", "REF_ENTITY_NAME", "REF_ENTITY_DOMAIN", "REF_COLUMN_NAME"},
+		[][]string{
+			{"GOV", "POLICIES", "REGION_FILTER", PolicyKindRowAccess, "SALES", "PUBLIC", "ORDERS", "TABLE", ""},
+			{"GOV", "POLICIES", "ORDERS_AGG", "AGGREGATION_POLICY", "SALES", "PUBLIC", "ORDERS", "TABLE", ""},
+		},
+	)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	attachments, err := client.GetTableDataPolicyAttachments(context.Background(), "SALES", "PUBLIC", "ORDERS")
+	require.NoError(t, err)
+	assert.Equal(t, &TablePolicies{RowAccessPolicy: "GOV.POLICIES.REGION_FILTER"}, attachments.ForTable("SALES", "PUBLIC", "ORDERS"))
+}
@@ -0,0 +1,336 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GrantChangeGrant and GrantChangeRevoke are the values of GrantChange.Change.
+const (
+	GrantChangeGrant  = "GRANT"
+	GrantChangeRevoke = "REVOKE"
+)
+
+const (
+	// GrantsLatency is how late Snowflake can add or update a row of GRANTS_TO_ROLES and
+	// GRANTS_TO_USERS.
+	GrantsLatency = 2 * time.Hour
+	// QueryHistoryLatency is how late Snowflake can add a row to QUERY_HISTORY.
+	QueryHistoryLatency = 45 * time.Minute
+)
+
+var grantChangeStructFieldToColumnMap = map[string]string{
+	"ChangeID":     "CHANGE_ID",
+	"ChangedOn":    "CHANGED_ON",
+	"Change":       "CHANGE",
+	"Privilege":    "PRIVILEGE",
+	"GrantedOn":    "GRANTED_ON",
+	"Name":         "NAME",
+	"TableCatalog": "TABLE_CATALOG",
+	"TableSchema":  "TABLE_SCHEMA",
+	"GrantedTo":    "GRANTED_TO",
+	"GranteeName":  "GRANTEE_NAME",
+}
+
+var grantStatementStructFieldToColumnMap = map[string]string{
+	"QueryID":   "QUERY_ID",
+	"StartTime": "START_TIME",
+	"QueryText": "QUERY_TEXT",
+	"UserName":  "USER_NAME",
+	"RoleName":  "ROLE_NAME",
+}
+
+type (
+	// GrantChange is a privilege or role granted or revoked. ListGrantChanges reads them from the
+	// created_on and deleted_on of GRANTS_TO_ROLES and GRANTS_TO_USERS, where a role granted to a
+	// user is USAGE on the role. ChangedOn is UTC text (see ParseAccountUsageTime) and ChangeID
+	// identifies the change among those at the same time. For tables, Name is the table and
+	// TableCatalog and TableSchema its database and schema.
+	GrantChange struct {
+		ChangeID     string
+		ChangedOn    string
+		Change       string
+		Privilege    string
+		GrantedOn    string
+		Name         string
+		TableCatalog string
+		TableSchema  string
+		GrantedTo    string
+		GranteeName  string
+	}
+
+	// GrantStatement is a successful GRANT or REVOKE statement from QUERY_HISTORY. StartTime is
+	// UTC text (see ParseAccountUsageTime).
+	GrantStatement struct {
+		QueryID   string
+		StartTime string
+		QueryText string
+		UserName  string
+		RoleName  string
+	}
+
+	ListGrantChangesRawResponse struct {
+		StatementsApiResponseBase
+	}
+	ListGrantStatementsRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (g *GrantChange) GetColumnName(fieldName string) string {
+	return grantChangeStructFieldToColumnMap[fieldName]
+}
+
+// Position is where a reader that stopped after this change resumes.
+func (g *GrantChange) Position() *AccountUsagePosition {
+	return &AccountUsagePosition{Time: g.ChangedOn, ID: g.ChangeID}
+}
+
+func (s *GrantStatement) GetColumnName(fieldName string) string {
+	return grantStatementStructFieldToColumnMap[fieldName]
+}
+
+// Position is where a reader that stopped after this statement resumes.
+func (s *GrantStatement) Position() *AccountUsagePosition {
+	return &AccountUsagePosition{Time: s.StartTime, ID: s.QueryID}
+}
+
+func (r *ListGrantChangesRawResponse) GetGrantChanges() ([]GrantChange, error) {
+	var changes []GrantChange
+	for _, row := range r.Data {
+		change := &GrantChange{}
+		if err := r.ResultSetMetadata.ParseRow(change, row); err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+	return changes, nil
+}
+
+func (r *ListGrantStatementsRawResponse) GetGrantStatements() ([]GrantStatement, error) {
+	var statements []GrantStatement
+	for _, row := range r.Data {
+		statement := &GrantStatement{}
+		if err := r.ResultSetMetadata.ParseRow(statement, row); err != nil {
+			return nil, err
+		}
+		statements = append(statements, *statement)
+	}
+	return statements, nil
+}
+
+// grantChangesQuery unions the grants and revokes recorded in GRANTS_TO_ROLES and GRANTS_TO_USERS
+// into one CHANGED_AT-ordered stream, keyed by a CHANGE_KEY that is unique per change.
+const grantChangesQuery = "SELECT g.CHANGE_KEY AS CHANGE_ID, %s AS CHANGED_ON, g.CHANGE, g.PRIVILEGE, g.GRANTED_ON, g.NAME, " +
+	"g.TABLE_CATALOG, g.TABLE_SCHEMA, g.GRANTED_TO, g.GRANTEE_NAME FROM (" +
+	"SELECT u.*, CONCAT_WS('|', u.CHANGE, u.GRANTED_TO, u.GRANTEE_NAME, u.GRANTED_ON, " +
+	"COALESCE(u.TABLE_CATALOG, ''), COALESCE(u.TABLE_SCHEMA, ''), u.NAME, u.PRIVILEGE) AS CHANGE_KEY FROM (" +
+	"SELECT 'GRANT' AS CHANGE, CREATED_ON AS CHANGED_AT, PRIVILEGE, GRANTED_ON, NAME, TABLE_CATALOG, TABLE_SCHEMA, GRANTED_TO, GRANTEE_NAME " +
+	"FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_ROLES " +
+	"UNION ALL SELECT 'REVOKE', DELETED_ON, PRIVILEGE, GRANTED_ON, NAME, TABLE_CATALOG, TABLE_SCHEMA, GRANTED_TO, GRANTEE_NAME " +
+	"FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_ROLES WHERE DELETED_ON IS NOT NULL " +
+	"UNION ALL SELECT 'GRANT', CREATED_ON, 'USAGE', 'ROLE', ROLE, NULL, NULL, GRANTED_TO, GRANTEE_NAME " +
+	"FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_USERS " +
+	"UNION ALL SELECT 'REVOKE', DELETED_ON, 'USAGE', 'ROLE', ROLE, NULL, NULL, GRANTED_TO, GRANTEE_NAME " +
+	"FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_USERS WHERE DELETED_ON IS NOT NULL" +
+	") u) g WHERE %s ORDER BY g.CHANGED_AT, g.CHANGE_KEY LIMIT %d;"
+
+// ListGrantChanges reads up to limit grants and revokes in r, in (CHANGED_ON, CHANGE_ID) order.
+// The views need IMPORTED PRIVILEGES on the SNOWFLAKE database.
+func (c *Client) ListGrantChanges(ctx context.Context, r *AccountUsageRange, limit int) ([]GrantChange, int, error) {
+	var response ListGrantChangesRawResponse
+	statusCode, err := c.runStatement(ctx, "list grant changes", "", &response,
+		fmt.Sprintf(grantChangesQuery,
+			selectAccountUsageTime("g.CHANGED_AT"),
+			r.where("g.CHANGED_AT", "g.CHANGE_KEY"),
+			limit,
+		),
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	changes, err := response.GetGrantChanges()
+	return changes, statusCode, err
+}
+
+// ListGrantStatements reads up to limit successful GRANT and REVOKE statements in r, in
+// (START_TIME, QUERY_ID) order. QUERY_HISTORY fills in sooner than the grants views, so the
+// statements show out-of-band grants before ListGrantChanges does.
+func (c *Client) ListGrantStatements(ctx context.Context, r *AccountUsageRange, limit int) ([]GrantStatement, int, error) {
+	var response ListGrantStatementsRawResponse
+	statusCode, err := c.runStatement(ctx, "list grant statements", "", &response,
+		fmt.Sprintf(
+			"SELECT q.QUERY_ID, %s AS START_TIME, q.QUERY_TEXT, q.USER_NAME, q.ROLE_NAME "+
+				"FROM SNOWFLAKE.ACCOUNT_USAGE.QUERY_HISTORY q "+
+				"WHERE q.QUERY_TYPE IN ('%s', '%s') AND q.EXECUTION_STATUS = 'SUCCESS' AND %s "+
+				"ORDER BY q.START_TIME, q.QUERY_ID LIMIT %d;",
+			selectAccountUsageTime("q.START_TIME"),
+			GrantChangeGrant, GrantChangeRevoke,
+			r.where("q.START_TIME", "q.QUERY_ID"),
+			limit,
+		),
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	statements, err := response.GetGrantStatements()
+	return statements, statusCode, err
+}
+
+// Changes parses the statement into the grant changes it made, in the shape ListGrantChanges
+// returns (without ChangeID or ChangedOn). Only the forms that map to single, named objects are
+// understood:
+//
+//	GRANT ROLE r TO { USER | ROLE } g          REVOKE ROLE r FROM { USER | ROLE } g
+//	GRANT p [, ...] ON { TABLE | DATABASE } n TO { ROLE | USER } g
+//	REVOKE p [, ...] ON { TABLE | DATABASE } n FROM { ROLE | USER } g
+//
+// Anything else (ALL privileges, ALL or FUTURE objects, database roles, tables not fully
+// qualified, REVOKE GRANT OPTION FOR) yields no changes.
+func (s *GrantStatement) Changes() []GrantChange {
+	tokens := tokenizeStatement(s.QueryText)
+	if len(tokens) < 2 {
+		return nil
+	}
+	change := strings.ToUpper(tokens[0])
+	var preposition string
+	switch change {
+	case GrantChangeGrant:
+		preposition = "TO"
+	case GrantChangeRevoke:
+		preposition = "FROM"
+	default:
+		return nil
+	}
+	tokens = tokens[1:]
+
+	if strings.EqualFold(tokens[0], "ROLE") {
+		// ROLE r TO|FROM USER|ROLE g
+		if len(tokens) < 5 || !strings.EqualFold(tokens[2], preposition) {
+			return nil
+		}
+		role := resolveQualifiedName(tokens[1])
+		grantedTo, grantee := grantStatementGrantee(tokens[3], tokens[4])
+		if len(role) != 1 || grantee == "" {
+			return nil
+		}
+		return []GrantChange{{
+			Change: change, Privilege: "USAGE", GrantedOn: "ROLE", Name: role[0],
+			GrantedTo: grantedTo, GranteeName: grantee,
+		}}
+	}
+
+	on := -1
+	for i, token := range tokens {
+		if strings.EqualFold(token, "ON") {
+			on = i
+			break
+		}
+	}
+	// p [, ...] ON TABLE|DATABASE n TO|FROM ROLE|USER g
+	if on < 1 || len(tokens) < on+6 || !strings.EqualFold(tokens[on+3], preposition) {
+		return nil
+	}
+	privileges := grantStatementPrivileges(tokens[:on])
+	grantedTo, grantee := grantStatementGrantee(tokens[on+4], tokens[on+5])
+	if privileges == nil || grantee == "" {
+		return nil
+	}
+
+	base := GrantChange{Change: change, GrantedTo: grantedTo, GranteeName: grantee}
+	name := resolveQualifiedName(tokens[on+2])
+	switch objectType := strings.ToUpper(tokens[on+1]); {
+	case objectType == "TABLE" && len(name) == 3:
+		base.GrantedOn, base.TableCatalog, base.TableSchema, base.Name = objectType, name[0], name[1], name[2]
+	case objectType == "DATABASE" && len(name) == 1:
+		base.GrantedOn, base.Name = objectType, name[0]
+	default:
+		return nil
+	}
+
+	changes := make([]GrantChange, 0, len(privileges))
+	for _, privilege := range privileges {
+		c := base
+		c.Privilege = privilege
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// grantStatementPrivileges returns the comma-separated privileges of a GRANT or REVOKE, each
+// uppercased with its words single-spaced (EVOLVE SCHEMA), or nil if they include ALL or GRANT
+// OPTION FOR.
+func grantStatementPrivileges(tokens []string) []string {
+	var privileges []string
+	var words []string
+	for _, token := range append(tokens, ",") {
+		if token != "," {
+			words = append(words, strings.ToUpper(token))
+			continue
+		}
+		privilege := strings.Join(words, " ")
+		words = nil
+		if privilege == "" {
+			continue
+		}
+		if privilege == "ALL" || privilege == "ALL PRIVILEGES" || strings.HasPrefix(privilege, "GRANT OPTION FOR") {
+			return nil
+		}
+		privileges = append(privileges, privilege)
+	}
+	return privileges
+}
+
+// grantStatementGrantee resolves the grantee of a GRANT or REVOKE, or returns an empty name if it
+// is not an account role or a user.
+func grantStatementGrantee(kind, name string) (string, string) {
+	kind = strings.ToUpper(kind)
+	if kind != "ROLE" && kind != "USER" {
+		return "", ""
+	}
+	parts := resolveQualifiedName(name)
+	if len(parts) != 1 {
+		return "", ""
+	}
+	return kind, parts[0]
+}
+
+// tokenizeStatement splits a SQL statement into words and commas. Quoted identifiers stay whole,
+// and the statement ends at the first semicolon outside quotes.
+func tokenizeStatement(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	inQuotes := false
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case ch == '"':
+			inQuotes = !inQuotes
+			_ = current.WriteByte(ch)
+		case inQuotes:
+			_ = current.WriteByte(ch)
+		case ch == ';':
+			flush()
+			return tokens
+		case ch == ',':
+			flush()
+			tokens = append(tokens, ",")
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			flush()
+		default:
+			_ = current.WriteByte(ch)
+		}
+	}
+	flush()
+	return tokens
+}
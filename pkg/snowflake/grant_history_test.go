@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantStatement_Changes(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []GrantChange
+	}{
+		{
+			name: "role to user",
+			text: "grant role analyst to user jdoe;",
+			expected: []GrantChange{{
+				Change: GrantChangeGrant, Privilege: "USAGE", GrantedOn: "ROLE", Name: "ANALYST", GrantedTo: "USER", GranteeName: "JDOE",
+			}},
+		},
+		{
+			name: "role from role, quoted",
+			text: `REVOKE ROLE "Data Readers" FROM ROLE ANALYST`,
+			expected: []GrantChange{{
+				Change: GrantChangeRevoke, Privilege: "USAGE", GrantedOn: "ROLE", Name: "Data Readers", GrantedTo: "ROLE", GranteeName: "ANALYST",
+			}},
+		},
+		{
+			name: "table privileges",
+			text: "GRANT SELECT, insert ON TABLE sales.public.\"Orders\" TO ROLE analyst",
+			expected: []GrantChange{
+				{Change: GrantChangeGrant, Privilege: "SELECT", GrantedOn: "TABLE", TableCatalog: "SALES", TableSchema: "PUBLIC", Name: "Orders", GrantedTo: "ROLE", GranteeName: "ANALYST"},
+				{Change: GrantChangeGrant, Privilege: "INSERT", GrantedOn: "TABLE", TableCatalog: "SALES", TableSchema: "PUBLIC", Name: "Orders", GrantedTo: "ROLE", GranteeName: "ANALYST"},
+			},
+		},
+		{
+			name: "database ownership",
+			text: "grant ownership on database sales to role sysadmin copy current grants;",
+			expected: []GrantChange{{
+				Change: GrantChangeGrant, Privilege: "OWNERSHIP", GrantedOn: "DATABASE", Name: "SALES", GrantedTo: "ROLE", GranteeName: "SYSADMIN",
+			}},
+		},
+		{name: "all privileges", text: "GRANT ALL PRIVILEGES ON TABLE SALES.PUBLIC.ORDERS TO ROLE ANALYST"},
+		{name: "future tables", text: "GRANT SELECT ON FUTURE TABLES IN SCHEMA SALES.PUBLIC TO ROLE ANALYST"},
+		{name: "grant option", text: "REVOKE GRANT OPTION FOR SELECT ON TABLE SALES.PUBLIC.ORDERS FROM ROLE ANALYST"},
+		{name: "table not fully qualified", text: "GRANT SELECT ON TABLE ORDERS TO ROLE ANALYST"},
+		{name: "database role", text: "GRANT DATABASE ROLE SALES.READER TO ROLE ANALYST"},
+		{name: "share", text: "GRANT USAGE ON DATABASE SALES TO SHARE PARTNERS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement := &GrantStatement{QueryText: tt.text}
+			assert.Equal(t, tt.expected, statement.Changes())
+		})
+	}
+}
+
+func TestListGrantChanges_BuildsStatement(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	r := &AccountUsageRange{
+		After: &AccountUsagePosition{Time: "2025-01-02 03:04:05.000000000 +00:00", ID: "GRANT|ROLE|ANALYST"},
+		Until: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+	_, _, err = client.ListGrantChanges(context.Background(), r, 50)
+	require.NoError(t, err)
+	assert.Contains(t, capturedSQL, "FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_ROLES WHERE DELETED_ON IS NOT NULL")
+	assert.Contains(t, capturedSQL, "FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_USERS WHERE DELETED_ON IS NOT NULL")
+	assert.Contains(t, capturedSQL, "g.CHANGE_KEY > 'GRANT|ROLE|ANALYST'")
+	assert.Contains(t, capturedSQL, "AND g.CHANGED_AT < ")
+	assert.Contains(t, capturedSQL, "ORDER BY g.CHANGED_AT, g.CHANGE_KEY LIMIT 50;")
+}
+
+func TestListGrantStatements_BuildsStatement(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, _, err = client.ListGrantStatements(context.Background(), &AccountUsageRange{Since: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}, 10)
+	require.NoError(t, err)
+	assert.Contains(t, capturedSQL, "q.QUERY_TYPE IN ('GRANT', 'REVOKE') AND q.EXECUTION_STATUS = 'SUCCESS'")
+	assert.Contains(t, capturedSQL, "ORDER BY q.START_TIME, q.QUERY_ID LIMIT 10;")
+}
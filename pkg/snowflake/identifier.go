@@ -19,3 +19,57 @@ func unquoteSnowflakeIdentifier(s string) string {
 	inner := s[1 : len(s)-1]
 	return strings.ReplaceAll(inner, `""`, `"`)
 }
+
+// normalizeQualifiedName rewrites a dotted object name such as `"DB"."My Schema".RULE` into its
+// bare DB.My Schema.RULE form, unquoting each part with unquoteSnowflakeIdentifier.
+func normalizeQualifiedName(s string) string {
+	return strings.Join(SplitQualifiedName(s), ".")
+}
+
+// SplitQualifiedName splits a dotted object name into its unquoted parts. Parts may be quoted
+// ("DB"."My.Schema".RULE); dots inside quoted parts do not split.
+func SplitQualifiedName(s string) []string {
+	return splitQualifiedName(s, unquoteSnowflakeIdentifier)
+}
+
+// resolveQualifiedName splits a dotted object name as written in a SQL statement, where Snowflake
+// resolves unquoted parts to uppercase and quoted parts as they are (db.PUBLIC."t" is
+// DB.PUBLIC.t).
+func resolveQualifiedName(s string) []string {
+	return splitQualifiedName(s, func(part string) string {
+		if strings.HasPrefix(part, `"`) {
+			return unquoteSnowflakeIdentifier(part)
+		}
+		return strings.ToUpper(part)
+	})
+}
+
+func splitQualifiedName(s string, resolve func(string) string) []string {
+	var parts []string
+	var current strings.Builder
+	inQuotes := false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '"':
+			inQuotes = !inQuotes
+		case ch == '.' && !inQuotes:
+			parts = append(parts, resolve(strings.TrimSpace(current.String())))
+			current.Reset()
+			continue
+		}
+		_ = current.WriteByte(ch)
+	}
+	parts = append(parts, resolve(strings.TrimSpace(current.String())))
+	return parts
+}
+
+// quoteQualifiedName renders parts as a dotted identifier with every part double-quoted
+// ("DB"."SCHEMA"."NAME"), preserving case and special characters.
+func quoteQualifiedName(parts ...string) string {
+	quoted := make([]string, 0, len(parts))
+	for _, part := range parts {
+		quoted = append(quoted, `"`+escapeDoubleQuotedIdentifier(part)+`"`)
+	}
+	return strings.Join(quoted, ".")
+}
@@ -1,6 +1,9 @@
 package snowflake
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestUnquoteSnowflakeIdentifier(t *testing.T) {
 	tests := []struct {
@@ -59,3 +62,31 @@ func TestUnquoteSnowflakeIdentifier(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeQualifiedName(t *testing.T) {
+	tests := map[string]string{
+		"DB.PUBLIC.RULE":           "DB.PUBLIC.RULE",
+		`"DB"."PUBLIC"."RULE"`:     "DB.PUBLIC.RULE",
+		`"my.db"."Sch""ema".R`:     `my.db.Sch"ema.R`,
+		`"DB" . "PUBLIC" . "RULE"`: "DB.PUBLIC.RULE",
+		`RULE`:                     "RULE",
+	}
+	for in, want := range tests {
+		if got := normalizeQualifiedName(in); got != want {
+			t.Errorf("normalizeQualifiedName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestResolveQualifiedName(t *testing.T) {
+	tests := map[string]string{
+		`db.Public."t.x"`: "DB|PUBLIC|t.x",
+		`"Mixed"`:         "Mixed",
+		`analyst`:         "ANALYST",
+	}
+	for in, want := range tests {
+		if got := strings.Join(resolveQualifiedName(in), "|"); got != want {
+			t.Errorf("resolveQualifiedName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var loginEventStructFieldToColumnMap = map[string]string{
+	"EventID":                    "EVENT_ID",
+	"EventTimestamp":             "EVENT_TIMESTAMP",
+	"UserName":                   "USER_NAME",
+	"ClientIP":                   "CLIENT_IP",
+	"ReportedClientType":         "REPORTED_CLIENT_TYPE",
+	"FirstAuthenticationFactor":  "FIRST_AUTHENTICATION_FACTOR",
+	"SecondAuthenticationFactor": "SECOND_AUTHENTICATION_FACTOR",
+	"IsSuccess":                  "IS_SUCCESS",
+	"ErrorCode":                  "ERROR_CODE",
+	"ErrorMessage":               "ERROR_MESSAGE",
+}
+
+type (
+	// LoginEvent is a row of SNOWFLAKE.ACCOUNT_USAGE.LOGIN_HISTORY. EventTimestamp is UTC text
+	// (see ParseAccountUsageTime); ErrorCode and ErrorMessage are empty for successful logins.
+	LoginEvent struct {
+		EventID                    string
+		EventTimestamp             string
+		UserName                   string
+		ClientIP                   string
+		ReportedClientType         string
+		FirstAuthenticationFactor  string
+		SecondAuthenticationFactor string
+		IsSuccess                  string
+		ErrorCode                  string
+		ErrorMessage               string
+	}
+
+	ListLoginHistoryRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (e *LoginEvent) GetColumnName(fieldName string) string {
+	return loginEventStructFieldToColumnMap[fieldName]
+}
+
+// Succeeded reports whether the login attempt succeeded. IS_SUCCESS is YES or NO.
+func (e *LoginEvent) Succeeded() bool {
+	return strings.EqualFold(e.IsSuccess, "YES")
+}
+
+// Position is where a reader that stopped after this event resumes.
+func (e *LoginEvent) Position() *AccountUsagePosition {
+	return &AccountUsagePosition{Time: e.EventTimestamp, ID: e.EventID}
+}
+
+func (r *ListLoginHistoryRawResponse) GetLoginEvents() ([]LoginEvent, error) {
+	var events []LoginEvent
+	for _, row := range r.Data {
+		event := &LoginEvent{}
+		if err := r.ResultSetMetadata.ParseRow(event, row); err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+	return events, nil
+}
+
+// LoginHistoryLatency is how late Snowflake can add a row to LOGIN_HISTORY.
+const LoginHistoryLatency = 2 * time.Hour
+
+// ListLoginHistory reads up to limit login attempts in r, in (EVENT_TIMESTAMP, EVENT_ID) order.
+// The view needs IMPORTED PRIVILEGES on the SNOWFLAKE database.
+func (c *Client) ListLoginHistory(ctx context.Context, r *AccountUsageRange, limit int) ([]LoginEvent, int, error) {
+	var response ListLoginHistoryRawResponse
+	statusCode, err := c.runStatement(ctx, "list login history", "", &response,
+		fmt.Sprintf(
+			"SELECT TO_VARCHAR(h.EVENT_ID) AS EVENT_ID, %s AS EVENT_TIMESTAMP, h.USER_NAME, h.CLIENT_IP, "+
+				"h.REPORTED_CLIENT_TYPE, h.FIRST_AUTHENTICATION_FACTOR, h.SECOND_AUTHENTICATION_FACTOR, h.IS_SUCCESS, "+
+				"TO_VARCHAR(h.ERROR_CODE) AS ERROR_CODE, h.ERROR_MESSAGE "+
+				"FROM SNOWFLAKE.ACCOUNT_USAGE.LOGIN_HISTORY h WHERE %s "+
+				"ORDER BY h.EVENT_TIMESTAMP, h.EVENT_ID LIMIT %d;",
+			selectAccountUsageTime("h.EVENT_TIMESTAMP"),
+			r.where("h.EVENT_TIMESTAMP", "h.EVENT_ID"),
+			limit,
+		),
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	events, err := response.GetLoginEvents()
+	return events, statusCode, err
+}
@@ -0,0 +1,64 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var loginHistoryColumns = []string{
+	"EVENT_ID", "EVENT_TIMESTAMP", "USER_NAME", "CLIENT_IP", "REPORTED_CLIENT_TYPE", "FIRST_AUTHENTICATION_FACTOR",
+	"SECOND_AUTHENTICATION_FACTOR", "IS_SUCCESS", "ERROR_CODE", "ERROR_MESSAGE",
+}
+
+func TestListLoginHistory_ParsesRows(t *testing.T) {
+	server := serveRows(t, loginHistoryColumns, [][]string{
+		{"101", "2025-01-02 03:04:05.123456789 +00:00", "SVC_ETL", "10.0.0.1", "JDBC_DRIVER", "PASSWORD", "", "NO", "390100", "INCORRECT_USERNAME_PASSWORD"},
+	})
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	events, _, err := client.ListLoginHistory(context.Background(), &AccountUsageRange{}, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.False(t, events[0].Succeeded())
+	assert.Equal(t, "PASSWORD", events[0].FirstAuthenticationFactor)
+	assert.Equal(t, &AccountUsagePosition{Time: "2025-01-02 03:04:05.123456789 +00:00", ID: "101"}, events[0].Position())
+
+	occurredAt, err := ParseAccountUsageTime(events[0].EventTimestamp)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC), occurredAt.UTC())
+}
+
+func TestListLoginHistory_BuildsStatement(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	r := &AccountUsageRange{
+		Since: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+	_, _, err = client.ListLoginHistory(context.Background(), r, 50)
+	require.NoError(t, err)
+	assert.Contains(t, capturedSQL,
+		"WHERE h.EVENT_TIMESTAMP >= TO_TIMESTAMP_TZ('2025-01-01 00:00:00.000000000 +00:00', 'YYYY-MM-DD HH24:MI:SS.FF9 TZH:TZM') "+
+			"AND h.EVENT_TIMESTAMP < TO_TIMESTAMP_TZ('2025-01-03 00:00:00.000000000 +00:00', 'YYYY-MM-DD HH24:MI:SS.FF9 TZH:TZM') "+
+			"ORDER BY h.EVENT_TIMESTAMP, h.EVENT_ID LIMIT 50;")
+
+	r.After = &AccountUsagePosition{Time: "2025-01-02 03:04:05.000000000 +00:00", ID: "7'"}
+	_, _, err = client.ListLoginHistory(context.Background(), r, 50)
+	require.NoError(t, err)
+	after := "TO_TIMESTAMP_TZ('2025-01-02 03:04:05.000000000 +00:00', 'YYYY-MM-DD HH24:MI:SS.FF9 TZH:TZM')"
+	assert.Contains(t, capturedSQL,
+		"WHERE (h.EVENT_TIMESTAMP > "+after+" OR (h.EVENT_TIMESTAMP = "+after+" AND h.EVENT_ID > '7''')) AND h.EVENT_TIMESTAMP < ")
+}
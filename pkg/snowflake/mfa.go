@@ -0,0 +1,79 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxMinsToBypassMfa bounds MINS_TO_BYPASS_MFA. Snowflake accepts larger values, but a bypass is
+// meant to cover a single login while the user re-enrolls.
+const MaxMinsToBypassMfa = 60
+
+var mfaMethodStructFieldToColumnMap = map[string]string{
+	structFieldName: columnName,
+	structFieldType: columnType,
+}
+
+type (
+	// MfaMethod is a row of SHOW MFA METHODS FOR USER. Type is PASSKEY, TOTP or DUO.
+	MfaMethod struct {
+		Name string
+		Type string
+	}
+
+	ListMfaMethodsRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (m *MfaMethod) GetColumnName(fieldName string) string {
+	return mfaMethodStructFieldToColumnMap[fieldName]
+}
+
+func (r *ListMfaMethodsRawResponse) GetMfaMethods() ([]MfaMethod, error) {
+	var methods []MfaMethod
+	for _, row := range r.Data {
+		method := &MfaMethod{}
+		if err := r.ResultSetMetadata.ParseRow(method, row); err != nil {
+			return nil, err
+		}
+		methods = append(methods, *method)
+	}
+	return methods, nil
+}
+
+// ListUserMfaMethods returns the MFA methods a user has enrolled, along with the HTTP status code
+// so callers can tell a user they cannot inspect (422) from other failures.
+func (c *Client) ListUserMfaMethods(ctx context.Context, username string) ([]MfaMethod, int, error) {
+	var response ListMfaMethodsRawResponse
+	statusCode, err := c.runStatement(ctx, fmt.Sprintf("list mfa methods of user %s", username), "", &response,
+		fmt.Sprintf("SHOW MFA METHODS FOR USER %s;", quoteQualifiedName(username)),
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	methods, err := response.GetMfaMethods()
+	if err != nil {
+		return nil, statusCode, err
+	}
+	return methods, statusCode, nil
+}
+
+// RemoveUserMfaMethod unenrolls one MFA method from a user. It runs as UserAdminRole.
+func (c *Client) RemoveUserMfaMethod(ctx context.Context, username, methodName string) error {
+	return c.execStatement(ctx, fmt.Sprintf("remove mfa method %s of user %s", methodName, username), UserAdminRole,
+		fmt.Sprintf("ALTER USER %s REMOVE MFA METHOD %s;", quoteQualifiedName(username), quoteQualifiedName(methodName)),
+	)
+}
+
+// SetUserMinsToBypassMfa lets a user log in without MFA for the given number of minutes, between 1
+// and MaxMinsToBypassMfa. It runs as UserAdminRole.
+func (c *Client) SetUserMinsToBypassMfa(ctx context.Context, username string, minutes int) error {
+	if minutes < 1 || minutes > MaxMinsToBypassMfa {
+		return fmt.Errorf("baton-snowflake: minutes to bypass mfa must be between 1 and %d, got %d", MaxMinsToBypassMfa, minutes)
+	}
+	return c.execStatement(ctx, fmt.Sprintf("bypass mfa of user %s", username), UserAdminRole,
+		fmt.Sprintf("ALTER USER %s SET MINS_TO_BYPASS_MFA = %d;", quoteQualifiedName(username), minutes),
+	)
+}
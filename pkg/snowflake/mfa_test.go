@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUserMfaMethods_ParsesRows(t *testing.T) {
+	server := serveRows(t,
+		[]string{"name", "type", "comment"},
+		[][]string{{"PASSKEY-1", "PASSKEY", ""}, {"TOTP-1", "TOTP", "phone"}},
+	)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	methods, _, err := client.ListUserMfaMethods(context.Background(), "JDOE")
+	require.NoError(t, err)
+	assert.Equal(t, []MfaMethod{{Name: "PASSKEY-1", Type: "PASSKEY"}, {Name: "TOTP-1", Type: "TOTP"}}, methods)
+}
+
+func TestRemoveUserMfaMethod_QuotesIdentifiers(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	require.NoError(t, client.RemoveUserMfaMethod(context.Background(), `o"brien`, "TOTP-1"))
+	assert.Equal(t, `ALTER USER "o""brien" REMOVE MFA METHOD "TOTP-1";`, capturedSQL)
+}
+
+func TestSetUserMinsToBypassMfa_RejectsOutOfRange(t *testing.T) {
+	client, err := New("https://example.invalid", JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	require.Error(t, client.SetUserMinsToBypassMfa(context.Background(), "JDOE", 0))
+	require.Error(t, client.SetUserMinsToBypassMfa(context.Background(), "JDOE", MaxMinsToBypassMfa+1))
+}
@@ -0,0 +1,289 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	networkPolicyStructFieldToColumnMap = map[string]string{
+		structFieldCreatedOn: columnCreatedOn,
+		structFieldName:      columnName,
+		structFieldComment:   columnComment,
+	}
+
+	networkRuleStructFieldToColumnMap = map[string]string{
+		structFieldCreatedOn:    columnCreatedOn,
+		structFieldName:         columnName,
+		structFieldDatabaseName: columnDatabaseName,
+		structFieldSchemaName:   columnSchemaName,
+		structFieldOwner:        columnOwner,
+		structFieldComment:      columnComment,
+		structFieldType:         columnType,
+		"Mode":                  "mode",
+	}
+
+	// networkRuleDescriptionStructFieldToColumnMap adds DESCRIBE NETWORK RULE's value_list to the
+	// columns it shares with SHOW NETWORK RULES.
+	networkRuleDescriptionStructFieldToColumnMap = map[string]string{
+		structFieldName: columnName,
+		"ValueList":     "value_list",
+	}
+
+	propertyValueStructFieldToColumnMap = map[string]string{
+		structFieldName: columnName,
+		"Value":         "value",
+	}
+)
+
+// DESCRIBE NETWORK POLICY property names.
+const (
+	networkPolicyAllowedIPList       = "ALLOWED_IP_LIST"
+	networkPolicyBlockedIPList       = "BLOCKED_IP_LIST"
+	networkPolicyAllowedNetworkRules = "ALLOWED_NETWORK_RULE_LIST"
+	networkPolicyBlockedNetworkRules = "BLOCKED_NETWORK_RULE_LIST"
+)
+
+type (
+	// NetworkPolicy is an account-level network policy as returned by SHOW NETWORK POLICIES.
+	// The lists themselves are only available from DescribeNetworkPolicy.
+	NetworkPolicy struct {
+		CreatedOn time.Time
+		Name      string
+		Comment   string
+	}
+
+	// NetworkPolicyDescription is the DESCRIBE NETWORK POLICY output. Network rules are
+	// referenced by fully qualified name (DB.SCHEMA.RULE).
+	NetworkPolicyDescription struct {
+		AllowedIPList       []string
+		BlockedIPList       []string
+		AllowedNetworkRules []string
+		BlockedNetworkRules []string
+	}
+
+	// NetworkRule is a schema-level network rule as returned by SHOW NETWORK RULES. Type is
+	// IPV4, AWSVPCEID, AZURELINKID or HOST_PORT; Mode is INGRESS, INTERNAL_STAGE or EGRESS.
+	NetworkRule struct {
+		CreatedOn    time.Time
+		Name         string
+		DatabaseName string
+		SchemaName   string
+		Owner        string
+		Comment      string
+		Type         string
+		Mode         string
+	}
+
+	networkRuleDescription struct {
+		Name      string
+		ValueList string
+	}
+
+	// propertyValue is a row of the two-column (name, value) layout DESCRIBE uses for most
+	// account-level objects.
+	propertyValue struct {
+		Name  string
+		Value string
+	}
+
+	ListNetworkPoliciesRawResponse struct {
+		StatementsApiResponseBase
+	}
+	DescribeNetworkPolicyRawResponse struct {
+		StatementsApiResponseBase
+	}
+	ListNetworkRulesRawResponse struct {
+		StatementsApiResponseBase
+	}
+	DescribeNetworkRuleRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (p *NetworkPolicy) GetColumnName(fieldName string) string {
+	return networkPolicyStructFieldToColumnMap[fieldName]
+}
+
+func (r *NetworkRule) GetColumnName(fieldName string) string {
+	return networkRuleStructFieldToColumnMap[fieldName]
+}
+
+func (d *networkRuleDescription) GetColumnName(fieldName string) string {
+	return networkRuleDescriptionStructFieldToColumnMap[fieldName]
+}
+
+func (p *propertyValue) GetColumnName(fieldName string) string {
+	return propertyValueStructFieldToColumnMap[fieldName]
+}
+
+// FullyQualifiedName is the DB.SCHEMA.RULE form network policies use to reference the rule.
+func (r *NetworkRule) FullyQualifiedName() string {
+	return fmt.Sprintf("%s.%s.%s", r.DatabaseName, r.SchemaName, r.Name)
+}
+
+func (r *ListNetworkPoliciesRawResponse) GetNetworkPolicies() ([]NetworkPolicy, error) {
+	var policies []NetworkPolicy
+	for _, row := range r.Data {
+		policy := &NetworkPolicy{}
+		if err := r.ResultSetMetadata.ParseRow(policy, row); err != nil {
+			return nil, err
+		}
+		policies = append(policies, *policy)
+	}
+	return policies, nil
+}
+
+func (r *DescribeNetworkPolicyRawResponse) GetNetworkPolicyDescription() (*NetworkPolicyDescription, error) {
+	description := &NetworkPolicyDescription{}
+	for _, row := range r.Data {
+		property := &propertyValue{}
+		if err := r.ResultSetMetadata.ParseRow(property, row); err != nil {
+			return nil, err
+		}
+
+		switch strings.ToUpper(property.Name) {
+		case networkPolicyAllowedIPList:
+			description.AllowedIPList = splitValueList(property.Value)
+		case networkPolicyBlockedIPList:
+			description.BlockedIPList = splitValueList(property.Value)
+		case networkPolicyAllowedNetworkRules:
+			description.AllowedNetworkRules = parseNetworkRuleReferences(property.Value)
+		case networkPolicyBlockedNetworkRules:
+			description.BlockedNetworkRules = parseNetworkRuleReferences(property.Value)
+		}
+	}
+	return description, nil
+}
+
+func (r *ListNetworkRulesRawResponse) GetNetworkRules() ([]NetworkRule, error) {
+	var rules []NetworkRule
+	for _, row := range r.Data {
+		rule := &NetworkRule{}
+		if err := r.ResultSetMetadata.ParseRow(rule, row); err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, nil
+}
+
+func (r *DescribeNetworkRuleRawResponse) GetValueList() ([]string, error) {
+	for _, row := range r.Data {
+		description := &networkRuleDescription{}
+		if err := r.ResultSetMetadata.ParseRow(description, row); err != nil {
+			return nil, err
+		}
+		return splitValueList(description.ValueList), nil
+	}
+	return nil, nil
+}
+
+// splitValueList splits the comma-separated lists DESCRIBE returns for IP and value lists.
+// Snowflake renders an empty list as "" or "null".
+func splitValueList(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" || value == rowNull {
+		return nil
+	}
+
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseNetworkRuleReferences reads a DESCRIBE NETWORK POLICY rule list, which Snowflake returns
+// as a JSON array of {"fullyQualifiedRuleName": ...} objects. Anything else is treated as a plain
+// comma-separated list so an unexpected rendering still surfaces the names. Names are returned in
+// the bare DB.SCHEMA.RULE form of NetworkRule.FullyQualifiedName.
+func parseNetworkRuleReferences(value string) []string {
+	var references []struct {
+		FullyQualifiedRuleName string `json:"fullyQualifiedRuleName"`
+	}
+	if err := json.Unmarshal([]byte(value), &references); err != nil {
+		var names []string
+		for _, name := range splitValueList(value) {
+			names = append(names, normalizeQualifiedName(name))
+		}
+		return names
+	}
+
+	var names []string
+	for _, reference := range references {
+		if reference.FullyQualifiedRuleName != "" {
+			names = append(names, normalizeQualifiedName(reference.FullyQualifiedRuleName))
+		}
+	}
+	return names
+}
+
+// ListNetworkPolicies enumerates account-level network policies via SHOW NETWORK POLICIES. Only
+// policies the current role owns or has been granted USAGE on are returned.
+func (c *Client) ListNetworkPolicies(ctx context.Context) ([]NetworkPolicy, error) {
+	var response ListNetworkPoliciesRawResponse
+	if _, err := c.runStatement(ctx, "list network policies", "", &response, "SHOW NETWORK POLICIES;"); err != nil {
+		return nil, err
+	}
+
+	return response.GetNetworkPolicies()
+}
+
+// GetNetworkPolicy returns the named network policy, or nil when no such policy is visible to
+// the current role.
+func (c *Client) GetNetworkPolicy(ctx context.Context, name string) (*NetworkPolicy, error) {
+	policies, err := c.ListNetworkPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range policies {
+		if policies[i].Name == name {
+			return &policies[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) DescribeNetworkPolicy(ctx context.Context, name string) (*NetworkPolicyDescription, error) {
+	var response DescribeNetworkPolicyRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("describe network policy %s", name), "", &response,
+		fmt.Sprintf("DESCRIBE NETWORK POLICY \"%s\";", escapeDoubleQuotedIdentifier(name)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetNetworkPolicyDescription()
+}
+
+// ListNetworkRules enumerates every network rule visible to the current role across all
+// databases and schemas via SHOW NETWORK RULES IN ACCOUNT.
+func (c *Client) ListNetworkRules(ctx context.Context) ([]NetworkRule, error) {
+	var response ListNetworkRulesRawResponse
+	if _, err := c.runStatement(ctx, "list network rules", "", &response, "SHOW NETWORK RULES IN ACCOUNT;"); err != nil {
+		return nil, err
+	}
+
+	return response.GetNetworkRules()
+}
+
+// DescribeNetworkRule returns the rule's value list: IP ranges, VPC endpoint IDs or host:port
+// pairs depending on its type.
+func (c *Client) DescribeNetworkRule(ctx context.Context, database, schema, name string) ([]string, error) {
+	var response DescribeNetworkRuleRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("describe network rule %s.%s.%s", database, schema, name), "", &response,
+		fmt.Sprintf("DESCRIBE NETWORK RULE \"%s\".\"%s\".\"%s\";",
+			escapeDoubleQuotedIdentifier(database), escapeDoubleQuotedIdentifier(schema), escapeDoubleQuotedIdentifier(name)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetValueList()
+}
@@ -0,0 +1,150 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timestampColumns are the SHOW output columns serveRows types "timestamp_ltz".
+var timestampColumns = map[string]bool{columnCreatedOn: true, "expires_at": true}
+
+// serveRows returns an httptest.Server implementing the Snowflake Statements API for a single
+// statement whose result is rows with the given column names. Timestamp columns are typed
+// "timestamp_ltz", as in SHOW output; every other column is "text".
+func serveRows(t *testing.T, columns []string, rows [][]string) *httptest.Server {
+	t.Helper()
+	rowType := make([]map[string]interface{}, 0, len(columns))
+	for _, column := range columns {
+		columnType := rowTypeString
+		if timestampColumns[column] {
+			columnType = rowTypeTimestampLtz
+		}
+		rowType = append(rowType, map[string]interface{}{"name": column, "type": columnType})
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+
+		switch r.Method {
+		case http.MethodPost:
+			_ = enc.Encode(map[string]interface{}{
+				"statementHandle": "handle",
+			})
+		case http.MethodGet:
+			_ = enc.Encode(map[string]interface{}{
+				"statementHandle": "handle",
+				"resultSetMetadata": map[string]interface{}{
+					"numRows": len(rows),
+					"rowType": rowType,
+				},
+				"data": rows,
+			})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestDescribeNetworkPolicy_ParsesLists(t *testing.T) {
+	server := serveRows(t, []string{"name", "value"}, [][]string{
+		{"ALLOWED_IP_LIST", "10.0.0.0/8, 192.168.1.1"},
+		{"BLOCKED_IP_LIST", ""},
+		{"ALLOWED_NETWORK_RULE_LIST", `[{"fullyQualifiedRuleName":"\"SECURITY\".\"Net.Rules\".\"CORP_VPN\""},{"fullyQualifiedRuleName":"DB.PUBLIC.OFFICE"}]`},
+		{"BLOCKED_NETWORK_RULE_LIST", "null"},
+	})
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	description, err := client.DescribeNetworkPolicy(context.Background(), "CORP")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, description.AllowedIPList)
+	assert.Empty(t, description.BlockedIPList)
+	assert.Equal(t, []string{"SECURITY.Net.Rules.CORP_VPN", "DB.PUBLIC.OFFICE"}, description.AllowedNetworkRules)
+	assert.Empty(t, description.BlockedNetworkRules)
+}
+
+func TestDescribeNetworkRule_EscapesIdentifiers(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, err = client.DescribeNetworkRule(context.Background(), `my"db`, "PUBLIC", `rule"1`)
+	require.NoError(t, err)
+	assert.Equal(t, `DESCRIBE NETWORK RULE "my""db"."PUBLIC"."rule""1";`, capturedSQL)
+}
+
+func TestGetAccountParameter_ExactMatch(t *testing.T) {
+	// LIKE treats "_" as a wildcard, so SHOW PARAMETERS LIKE 'NETWORK_POLICY' can return
+	// near-miss keys as well.
+	server := serveRows(t, []string{"key", "value", "level"}, [][]string{
+		{"NETWORKXPOLICY", "WRONG", "ACCOUNT"},
+		{"NETWORK_POLICY", "CORP", "ACCOUNT"},
+	})
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	value, err := client.GetAccountParameter(context.Background(), ParameterNetworkPolicy)
+	require.NoError(t, err)
+	assert.Equal(t, "CORP", value)
+}
+
+// serveDescribeUser returns an httptest.Server answering DESCRIBE USER with the given extra
+// property rows on top of the properties GetUser requires.
+func serveDescribeUser(t *testing.T, extra [][]string) *httptest.Server {
+	t.Helper()
+	rows := [][]string{
+		{"NAME", "SVC"}, {"LOGIN_NAME", "SVC"}, {"DISPLAY_NAME", "SVC"}, {"FIRST_NAME", "null"},
+		{"LAST_NAME", "null"}, {"EMAIL", "null"}, {"DISABLED", "false"}, {"SNOWFLAKE_LOCK", "false"},
+		{"DEFAULT_ROLE", "PUBLIC"}, {"TYPE", "SERVICE"}, {"HAS_MFA", "false"}, {"COMMENT", "null"},
+	}
+	rows = append(rows, extra...)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"statementHandle": "handle",
+			"data":            rows,
+		})
+	}))
+}
+
+func TestGetUser_ReadsNetworkPolicy(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra [][]string
+		want  string
+	}{
+		{name: "set", extra: [][]string{{"NETWORK_POLICY", "CORP"}}, want: "CORP"},
+		{name: "null", extra: [][]string{{"NETWORK_POLICY", "null"}}, want: ""},
+		{name: "absent", extra: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := serveDescribeUser(t, tt.extra)
+			defer server.Close()
+
+			client, err := New(server.URL, JWTConfig{}, &http.Client{})
+			require.NoError(t, err)
+
+			user, _, err := client.GetUser(context.Background(), nil, "SVC")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, user.NetworkPolicy)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ObjectChangeRole and ObjectChangeTable are the values of ObjectChange.ObjectType.
+const (
+	ObjectChangeRole  = "ROLE"
+	ObjectChangeTable = "TABLE"
+)
+
+// ObjectChangesLatency is how late Snowflake can add or update a row of the views
+// ListObjectChanges reads (GRANTS_TO_ROLES, GRANTS_TO_USERS, ROLES and USERS).
+const ObjectChangesLatency = 2 * time.Hour
+
+// ErrTooManyObjectChanges is returned by ListObjectChanges when the changes do not fit in limit
+// rows or in the one result partition it reads.
+var ErrTooManyObjectChanges = errors.New("baton-snowflake: too many object changes")
+
+var objectChangeStructFieldToColumnMap = map[string]string{
+	"ObjectType":   "OBJECT_TYPE",
+	"DatabaseName": "DATABASE_NAME",
+	"SchemaName":   "SCHEMA_NAME",
+	"Name":         "NAME",
+}
+
+type (
+	// ObjectChange is an account role whose grantees, or a table or view whose grants, may have
+	// changed. DatabaseName and SchemaName are empty for roles.
+	ObjectChange struct {
+		ObjectType   string
+		DatabaseName string
+		SchemaName   string
+		Name         string
+	}
+
+	ListObjectChangesRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (o *ObjectChange) GetColumnName(fieldName string) string {
+	return objectChangeStructFieldToColumnMap[fieldName]
+}
+
+func (r *ListObjectChangesRawResponse) GetObjectChanges() ([]ObjectChange, error) {
+	var changes []ObjectChange
+	for _, row := range r.Data {
+		change := &ObjectChange{}
+		if err := r.ResultSetMetadata.ParseRow(change, row); err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+	return changes, nil
+}
+
+// objectChangesQuery selects each role and table a grant was created, modified or revoked on since
+// the timestamp, plus those that lost a grantee or were dropped:
+//   - roles granted to or revoked from users and roles (GRANTS_TO_USERS, GRANTS_TO_ROLES);
+//   - tables and views with a privilege granted or revoked, ownership included (GRANTS_TO_ROLES);
+//   - roles and tables granted to a role or user dropped since (ROLES, USERS);
+//   - dropped roles (ROLES) and tables (TABLES, whose DELETED column is its DELETED_ON).
+//
+// TABLES.LAST_ALTERED is not read: DML moves it, and an ownership transfer shows up in
+// GRANTS_TO_ROLES anyway.
+const objectChangesQuery = "SELECT DISTINCT c.OBJECT_TYPE, c.DATABASE_NAME, c.SCHEMA_NAME, c.NAME FROM (" +
+	"SELECT 'ROLE' AS OBJECT_TYPE, '' AS DATABASE_NAME, '' AS SCHEMA_NAME, ROLE AS NAME " +
+	"FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_USERS WHERE CREATED_ON >= {since} OR DELETED_ON >= {since} " +
+	"UNION ALL SELECT IFF(GRANTED_ON = 'ROLE', 'ROLE', 'TABLE'), COALESCE(TABLE_CATALOG, ''), COALESCE(TABLE_SCHEMA, ''), NAME " +
+	"FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_ROLES WHERE GRANTED_ON IN ('ROLE', 'TABLE', 'VIEW') " +
+	"AND (CREATED_ON >= {since} OR MODIFIED_ON >= {since} OR DELETED_ON >= {since}) " +
+	"UNION ALL SELECT IFF(g.GRANTED_ON = 'ROLE', 'ROLE', 'TABLE'), COALESCE(g.TABLE_CATALOG, ''), COALESCE(g.TABLE_SCHEMA, ''), g.NAME " +
+	"FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_ROLES g JOIN SNOWFLAKE.ACCOUNT_USAGE.ROLES r ON r.NAME = g.GRANTEE_NAME " +
+	"WHERE g.GRANTED_TO = 'ROLE' AND g.GRANTED_ON IN ('ROLE', 'TABLE', 'VIEW') AND r.DELETED_ON >= {since} " +
+	"UNION ALL SELECT 'ROLE', '', '', g.ROLE " +
+	"FROM SNOWFLAKE.ACCOUNT_USAGE.GRANTS_TO_USERS g JOIN SNOWFLAKE.ACCOUNT_USAGE.USERS u ON u.NAME = g.GRANTEE_NAME " +
+	"WHERE u.DELETED_ON >= {since} " +
+	"UNION ALL SELECT 'ROLE', '', '', NAME FROM SNOWFLAKE.ACCOUNT_USAGE.ROLES WHERE DELETED_ON >= {since} " +
+	"UNION ALL SELECT 'TABLE', TABLE_CATALOG, TABLE_SCHEMA, TABLE_NAME FROM SNOWFLAKE.ACCOUNT_USAGE.TABLES WHERE DELETED >= {since}" +
+	") c ORDER BY c.OBJECT_TYPE, c.DATABASE_NAME, c.SCHEMA_NAME, c.NAME LIMIT %d;"
+
+// ListObjectChanges reads the roles and tables whose grants may have changed at or after since
+// (see objectChangesQuery), or returns ErrTooManyObjectChanges if there are limit or more. The
+// views need IMPORTED PRIVILEGES on the SNOWFLAKE database.
+func (c *Client) ListObjectChanges(ctx context.Context, since time.Time, limit int) ([]ObjectChange, int, error) {
+	query := strings.ReplaceAll(objectChangesQuery, "{since}", accountUsageTimeLiteral(formatAccountUsageTime(since)))
+
+	var response ListObjectChangesRawResponse
+	statusCode, err := c.runStatement(ctx, "list object changes", "", &response, fmt.Sprintf(query, limit))
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	if response.ResultSetMetadata.NumRows >= limit {
+		return nil, statusCode, ErrTooManyObjectChanges
+	}
+
+	changes, err := response.GetObjectChanges()
+	return changes, statusCode, err
+}
@@ -0,0 +1,50 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var objectChangeColumns = []string{"OBJECT_TYPE", "DATABASE_NAME", "SCHEMA_NAME", "NAME"}
+
+func TestListObjectChanges_BuildsStatement(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, _, err = client.ListObjectChanges(context.Background(), time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), 100)
+	require.NoError(t, err)
+	assert.NotContains(t, capturedSQL, "{since}")
+	assert.Contains(t, capturedSQL, "DELETED_ON >= TO_TIMESTAMP_TZ('2025-01-02 03:04:05.000000000 +00:00', ")
+	assert.Contains(t, capturedSQL, "FROM SNOWFLAKE.ACCOUNT_USAGE.TABLES WHERE DELETED >= ")
+	assert.Contains(t, capturedSQL, "LIMIT 100;")
+}
+
+func TestListObjectChanges_ParsesRows(t *testing.T) {
+	server := serveRows(t, objectChangeColumns, [][]string{
+		{ObjectChangeRole, "", "", "ANALYST"},
+		{ObjectChangeTable, "SALES", "PUBLIC", "ORDERS"},
+	})
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	changes, _, err := client.ListObjectChanges(context.Background(), time.Now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []ObjectChange{
+		{ObjectType: ObjectChangeRole, Name: "ANALYST"},
+		{ObjectType: ObjectChangeTable, DatabaseName: "SALES", SchemaName: "PUBLIC", Name: "ORDERS"},
+	}, changes)
+
+	_, _, err = client.ListObjectChanges(context.Background(), time.Now(), 2)
+	assert.ErrorIs(t, err, ErrTooManyObjectChanges, "a full page may be missing changes")
+}
@@ -2,11 +2,17 @@ package snowflake
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"strconv"
 
+	"github.com/conductorone/baton-sdk/pkg/session"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 )
 
+const currentAccountCacheKey = "account"
+
 // organizationAccountStructFieldToColumnMap maps OrganizationAccount fields to the
 // SHOW ORGANIZATION ACCOUNTS columns the connector consumes. Only columns that are
 // always present are listed: ParseRow requires every mapped column to exist in the
@@ -15,14 +21,22 @@ import (
 // region groups, which previously aborted the whole sync (CXH-2093). Mapping only
 // the columns actually used keeps parsing resilient to that conditional layout.
 var organizationAccountStructFieldToColumnMap = map[string]string{
-	"Edition":        "edition",
-	"AccountLocator": "account_locator",
+	"OrganizationName": "organization_name",
+	"AccountName":      "account_name",
+	"SnowflakeRegion":  "snowflake_region",
+	"Edition":          "edition",
+	"AccountURL":       "account_url",
+	"AccountLocator":   "account_locator",
 }
 
 type (
 	OrganizationAccount struct {
-		Edition        string
-		AccountLocator string
+		OrganizationName string
+		AccountName      string
+		SnowflakeRegion  string
+		Edition          string
+		AccountURL       string
+		AccountLocator   string
 	}
 	ListOrganizationAccountsRawResponse struct {
 		StatementsApiResponseBase
@@ -33,6 +47,12 @@ func (o *OrganizationAccount) GetColumnName(fieldName string) string {
 	return organizationAccountStructFieldToColumnMap[fieldName]
 }
 
+// AccountIdentifier is the ORGNAME-ACCOUNTNAME identifier key-pair authentication to the account
+// at AccountURL takes.
+func (o *OrganizationAccount) AccountIdentifier() string {
+	return o.OrganizationName + "-" + o.AccountName
+}
+
 func (r *ListOrganizationAccountsRawResponse) GetOrganizationAccounts() ([]OrganizationAccount, error) {
 	var accounts []OrganizationAccount
 	for _, row := range r.Data {
@@ -45,6 +65,19 @@ func (r *ListOrganizationAccountsRawResponse) GetOrganizationAccounts() ([]Organ
 	return accounts, nil
 }
 
+// IsOrganizationUnavailable reports whether a failed organization statement, such as SHOW
+// ORGANIZATION ACCOUNTS, means the account has no organization features to report, which is safe
+// to skip. A regular or trial account is rejected with 400 regardless of role, an organization
+// account without GLOBALORGADMIN with 422, and a role without privileges on the statement's
+// objects with an access-control error. Cancellation and 5xx return false so the sync surfaces
+// them.
+func IsOrganizationUnavailable(statusCode int, err error) bool {
+	if err == nil {
+		return false
+	}
+	return statusCode == http.StatusBadRequest || IsUnprocessableEntity(statusCode, err) || IsInsufficientPrivileges(err)
+}
+
 func (c *Client) ListOrganizationAccounts(ctx context.Context) ([]OrganizationAccount, int, error) {
 	queries := []string{"SHOW ORGANIZATION ACCOUNTS;"}
 
@@ -87,6 +120,46 @@ func (c *Client) ListOrganizationAccounts(ctx context.Context) ([]OrganizationAc
 	return accounts, resp2.StatusCode, nil
 }
 
+// GetCurrentAccount returns the account the client is connected to, with its organization name,
+// account name, locator and region; AccountURL is the client's. Unlike ListOrganizationAccounts
+// it needs no privileges. The result is cached for the rest of the sync.
+func (c *Client) GetCurrentAccount(ctx context.Context, ss sessions.SessionStore) (*OrganizationAccount, error) {
+	if ss != nil {
+		if cached, found, err := session.GetJSON[*OrganizationAccount](ctx, ss, currentAccountCacheKey, currentAccountNamespace); err == nil && found {
+			return cached, nil
+		}
+	}
+
+	var response StatementsApiResponseBase
+	_, err := c.runStatement(ctx, "get current account", "", &response,
+		"SELECT CURRENT_ORGANIZATION_NAME(), CURRENT_ACCOUNT_NAME(), CURRENT_ACCOUNT(), CURRENT_REGION();",
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 || len(response.Data[0]) < 4 {
+		return nil, errors.New("baton-snowflake: current account query returned no row")
+	}
+
+	row := response.Data[0]
+	account := &OrganizationAccount{
+		AccountName:     row[1],
+		AccountLocator:  row[2],
+		SnowflakeRegion: row[3],
+		AccountURL:      c.AccountUrl,
+	}
+	if row[0] != rowNull {
+		account.OrganizationName = row[0]
+	}
+
+	if ss != nil {
+		// Best-effort, like GetPolicyAttachments.
+		_ = session.SetJSON(ctx, ss, currentAccountCacheKey, account, currentAccountNamespace)
+	}
+
+	return account, nil
+}
+
 func (c *Client) CountUsers(ctx context.Context) (int64, error) {
 	queries := []string{"SELECT COUNT(*) FROM SNOWFLAKE.ACCOUNT_USAGE.USERS WHERE DELETED_ON IS NULL;"}
 
@@ -1,6 +1,12 @@
 package snowflake
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
 
 // TestGetOrganizationAccountsOmitsRegionGroup is a regression test for CXH-2093.
 // SHOW ORGANIZATION ACCOUNTS does not return a region_group column for organizations
@@ -55,4 +61,35 @@ func TestGetOrganizationAccountsOmitsRegionGroup(t *testing.T) {
 	if got := accounts[0].AccountLocator; got != "AB12345" {
 		t.Errorf("AccountLocator = %q, want %q", got, "AB12345")
 	}
+	if got := accounts[0].AccountURL; got != "https://example.snowflakecomputing.com" {
+		t.Errorf("AccountURL = %q, want %q", got, "https://example.snowflakecomputing.com")
+	}
+	if got := accounts[0].AccountIdentifier(); got != "EXAMPLE_ORG-EXAMPLE_ACCOUNT" {
+		t.Errorf("AccountIdentifier() = %q, want %q", got, "EXAMPLE_ORG-EXAMPLE_ACCOUNT")
+	}
+}
+
+func TestIsOrganizationUnavailable(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		want       bool
+	}{
+		{"no error", http.StatusOK, nil, false},
+		{"400 status code", http.StatusBadRequest, errors.New("400 Bad Request"), true},
+		{"422 status code", http.StatusUnprocessableEntity, errors.New("unprocessable entity"), true},
+		{"422 in error message", 0, errors.New("rpc error: code = Unknown desc = 422 Unprocessable Entity"), true},
+		{"insufficient privileges", http.StatusUnprocessableEntity, fmt.Errorf("%w: access denied", ErrInsufficientPrivileges), true},
+		{"insufficient privileges without status", 0, fmt.Errorf("%w: access denied", ErrInsufficientPrivileges), true},
+		{"500 propagates", http.StatusInternalServerError, errors.New("500 Internal Server Error"), false},
+		{"context canceled propagates", 0, context.Canceled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOrganizationUnavailable(tt.statusCode, tt.err); got != tt.want {
+				t.Errorf("IsOrganizationUnavailable(%d, %v) = %v, want %v", tt.statusCode, tt.err, got, tt.want)
+			}
+		})
+	}
 }
@@ -0,0 +1,228 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/session"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
+)
+
+// Organization users and organization user groups are defined once in the organization account
+// and imported into regular accounts group by group. Importing a group creates an account user,
+// with the organization user's name, for each of its members.
+// https://docs.snowflake.com/en/user-guide/organization-users
+
+var (
+	// Like organizationAccountStructFieldToColumnMap, only the columns the connector consumes are
+	// mapped, since ParseRow fails on any mapped column the response lacks.
+	organizationUserStructFieldToColumnMap = map[string]string{
+		"Name":        columnName,
+		"LoginName":   "login_name",
+		"DisplayName": "display_name",
+		"FirstName":   "first_name",
+		"LastName":    "last_name",
+		"Email":       "email",
+		"Comment":     columnComment,
+	}
+
+	organizationUserGroupStructFieldToColumnMap = map[string]string{
+		"Name":    columnName,
+		"Comment": columnComment,
+	}
+)
+
+const (
+	// SHOW ORGANIZATION USER GROUPS columns that depend on where it runs: the organization account
+	// reports which accounts may import a group, a regular account whether it has imported it.
+	// They are read with optionalRowValue rather than mapped.
+	columnVisibility = "visibility"
+	columnIsImported = "is_imported"
+
+	importedOrganizationUsersCacheKey = "account"
+)
+
+type (
+	OrganizationUser struct {
+		Name        string
+		LoginName   string
+		DisplayName string
+		FirstName   string
+		LastName    string
+		Email       string
+		Comment     string
+	}
+
+	// OrganizationUserGroup is a row of SHOW ORGANIZATION USER GROUPS. Visibility is only set
+	// when listed in the organization account, IsImported only when listed in a regular account.
+	OrganizationUserGroup struct {
+		Name       string
+		Comment    string
+		Visibility string
+		IsImported bool
+	}
+
+	ListOrganizationUsersRawResponse struct {
+		StatementsApiResponseBase
+	}
+	ListOrganizationUserGroupsRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (u *OrganizationUser) GetColumnName(fieldName string) string {
+	return organizationUserStructFieldToColumnMap[fieldName]
+}
+
+func (g *OrganizationUserGroup) GetColumnName(fieldName string) string {
+	return organizationUserGroupStructFieldToColumnMap[fieldName]
+}
+
+func (g *OrganizationUserGroup) unparsedFields() []string {
+	return []string{"Visibility", "IsImported"}
+}
+
+func (r *ListOrganizationUsersRawResponse) GetOrganizationUsers() ([]OrganizationUser, error) {
+	var users []OrganizationUser
+	for _, row := range r.Data {
+		user := &OrganizationUser{}
+		if err := r.ResultSetMetadata.ParseRow(user, row); err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+func (r *ListOrganizationUserGroupsRawResponse) GetOrganizationUserGroups() ([]OrganizationUserGroup, error) {
+	var groups []OrganizationUserGroup
+	for _, row := range r.Data {
+		group := &OrganizationUserGroup{}
+		if err := r.ResultSetMetadata.ParseRow(group, row); err != nil {
+			return nil, err
+		}
+		group.Visibility = optionalRowValue(&r.ResultSetMetadata, row, columnVisibility)
+		if imported := optionalRowValue(&r.ResultSetMetadata, row, columnIsImported); imported != "" {
+			isImported, err := strconv.ParseBool(imported)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", columnIsImported, err)
+			}
+			group.IsImported = isImported
+		}
+		groups = append(groups, *group)
+	}
+	return groups, nil
+}
+
+// optionalRowValue returns the column's value in row, or "" when the column is absent or NULL.
+func optionalRowValue(m *ResultSetMetadata, row []string, column string) string {
+	found, i, _ := m.FindRowTypeByName(column)
+	if !found || i >= len(row) || row[i] == rowNull {
+		return ""
+	}
+	return row[i]
+}
+
+// ListOrganizationUsers returns the organization's users. Like ListOrganizationAccounts it runs
+// as GLOBALORGADMIN, so it only succeeds in the organization account; the status code lets
+// callers tell that case apart.
+func (c *Client) ListOrganizationUsers(ctx context.Context) ([]OrganizationUser, int, error) {
+	var response ListOrganizationUsersRawResponse
+	statusCode, err := c.runStatement(ctx, "list organization users", GlobalOrgAdminRole, &response,
+		"SHOW ORGANIZATION USERS;",
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	users, err := response.GetOrganizationUsers()
+	if err != nil {
+		return nil, statusCode, err
+	}
+	return users, statusCode, nil
+}
+
+// ListOrganizationUserGroups returns the organization's user groups, as GLOBALORGADMIN.
+func (c *Client) ListOrganizationUserGroups(ctx context.Context) ([]OrganizationUserGroup, int, error) {
+	return c.listOrganizationUserGroups(ctx, GlobalOrgAdminRole)
+}
+
+// ListAvailableOrganizationUserGroups returns the organization user groups visible to this
+// account, with whether each has been imported. It runs under the connector user's default role.
+func (c *Client) ListAvailableOrganizationUserGroups(ctx context.Context) ([]OrganizationUserGroup, int, error) {
+	return c.listOrganizationUserGroups(ctx, "")
+}
+
+func (c *Client) listOrganizationUserGroups(ctx context.Context, role string) ([]OrganizationUserGroup, int, error) {
+	var response ListOrganizationUserGroupsRawResponse
+	statusCode, err := c.runStatement(ctx, "list organization user groups", role, &response,
+		"SHOW ORGANIZATION USER GROUPS;",
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	groups, err := response.GetOrganizationUserGroups()
+	if err != nil {
+		return nil, statusCode, err
+	}
+	return groups, statusCode, nil
+}
+
+// ListOrganizationUserGroupMembers returns the organization users in the group, as GLOBALORGADMIN.
+func (c *Client) ListOrganizationUserGroupMembers(ctx context.Context, group string) ([]OrganizationUser, error) {
+	return c.listOrganizationUserGroupMembers(ctx, group, GlobalOrgAdminRole)
+}
+
+func (c *Client) listOrganizationUserGroupMembers(ctx context.Context, group, role string) ([]OrganizationUser, error) {
+	var response ListOrganizationUsersRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list members of organization user group %s", group), role, &response,
+		fmt.Sprintf("SHOW ORGANIZATION USERS IN ORGANIZATION USER GROUP \"%s\";", escapeDoubleQuotedIdentifier(group)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetOrganizationUsers()
+}
+
+// GetImportedOrganizationUsers returns the names of the organization users this account has
+// imported, through the organization user groups it imported. Each was created as the account
+// user of the same name. Accounts that cannot list organization user groups, such as those
+// outside an organization, have none. The result is cached for the rest of the sync.
+func (c *Client) GetImportedOrganizationUsers(ctx context.Context, ss sessions.SessionStore) (map[string]bool, error) {
+	if ss != nil {
+		if cached, found, err := session.GetJSON[map[string]bool](ctx, ss, importedOrganizationUsersCacheKey, importedOrganizationUsersNamespace); err == nil && found {
+			return cached, nil
+		}
+	}
+
+	imported := map[string]bool{}
+	groups, statusCode, err := c.ListAvailableOrganizationUserGroups(ctx)
+	if err != nil && !IsOrganizationUnavailable(statusCode, err) {
+		return nil, err
+	}
+	for _, group := range groups {
+		if !group.IsImported {
+			continue
+		}
+		members, err := c.listOrganizationUserGroupMembers(ctx, group.Name, "")
+		if err != nil {
+			if IsInsufficientPrivileges(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, member := range members {
+			imported[member.Name] = true
+		}
+	}
+
+	if ss != nil {
+		// Best-effort, like GetPolicyAttachments.
+		_ = session.SetJSON(ctx, ss, importedOrganizationUsersCacheKey, imported, importedOrganizationUsersNamespace)
+	}
+
+	return imported, nil
+}
@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrganizationUserGroups_ReadsLocationDependentColumns(t *testing.T) {
+	// In a regular account SHOW ORGANIZATION USER GROUPS has is_imported but no visibility.
+	resp := &ListOrganizationUserGroupsRawResponse{
+		StatementsApiResponseBase: StatementsApiResponseBase{
+			ResultSetMetadata: ResultSetMetadata{RowTypes: []RowType{
+				{Name: "name", Type: rowTypeString},
+				{Name: "comment", Type: rowTypeString},
+				{Name: "is_imported", Type: rowTypeString},
+			}},
+			Data: [][]string{
+				{"ENGINEERING", "", "true"},
+				{"FINANCE", "", "false"},
+			},
+		},
+	}
+
+	groups, err := resp.GetOrganizationUserGroups()
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.True(t, groups[0].IsImported)
+	assert.False(t, groups[1].IsImported)
+	assert.Empty(t, groups[0].Visibility)
+}
+
+func TestListOrganizationUserGroupMembers_QuotesGroupName(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, err = client.ListOrganizationUserGroupMembers(context.Background(), `data"eng`)
+	require.NoError(t, err)
+	assert.Equal(t, `SHOW ORGANIZATION USERS IN ORGANIZATION USER GROUP "data""eng";`, capturedSQL)
+}
+
+func TestGetImportedOrganizationUsers_NoneOutsideOrganization(t *testing.T) {
+	server := newStatusServer(t, http.StatusBadRequest, "002003", "Unsupported feature 'ORGANIZATION USER GROUPS'.")
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	imported, err := client.GetImportedOrganizationUsers(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, imported)
+}
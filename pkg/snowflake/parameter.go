@@ -0,0 +1,71 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+)
+
+// Account parameters read by the connector. https://docs.snowflake.com/en/sql-reference/parameters
+const (
+	ParameterNetworkPolicy = "NETWORK_POLICY"
+)
+
+var parameterStructFieldToColumnMap = map[string]string{
+	"Key":   "key",
+	"Value": "value",
+	"Level": "level",
+}
+
+type (
+	// Parameter is one row of SHOW PARAMETERS. Level is empty when the value is the Snowflake
+	// default and "ACCOUNT" when an administrator set it at account level.
+	Parameter struct {
+		Key   string
+		Value string
+		Level string
+	}
+	ListParametersRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (p *Parameter) GetColumnName(fieldName string) string {
+	return parameterStructFieldToColumnMap[fieldName]
+}
+
+func (r *ListParametersRawResponse) GetParameters() ([]Parameter, error) {
+	var parameters []Parameter
+	for _, row := range r.Data {
+		parameter := &Parameter{}
+		if err := r.ResultSetMetadata.ParseRow(parameter, row); err != nil {
+			return nil, err
+		}
+		parameters = append(parameters, *parameter)
+	}
+	return parameters, nil
+}
+
+// GetAccountParameter returns the account-level value of a single parameter, or "" when it is
+// unset. SHOW PARAMETERS' LIKE is a pattern, so the exact key is matched from the returned rows.
+func (c *Client) GetAccountParameter(ctx context.Context, key string) (string, error) {
+	var response ListParametersRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("show account parameter %s", key), "", &response,
+		fmt.Sprintf("SHOW PARAMETERS LIKE '%s' IN ACCOUNT;", escapeLikeStringLiteral(key)),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	parameters, err := response.GetParameters()
+	if err != nil {
+		return "", err
+	}
+
+	for _, parameter := range parameters {
+		if parameter.Key == key {
+			return parameter.Value, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,482 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/session"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
+)
+
+// Policy kinds, as POLICY_REFERENCES reports them in POLICY_KIND.
+const (
+	PolicyKindAuthentication = "AUTHENTICATION_POLICY"
+	PolicyKindPassword       = "PASSWORD_POLICY"
+	PolicyKindSession        = "SESSION_POLICY"
+	PolicyKindMasking        = "MASKING_POLICY"
+	PolicyKindRowAccess      = "ROW_ACCESS_POLICY"
+
+	// PolicyKindNetwork is the user-level network policy. It is a parameter rather than a
+	// schema-level policy, so it only appears in ALTER USER SET/UNSET.
+	PolicyKindNetwork = "NETWORK_POLICY"
+)
+
+// POLICY_REFERENCES REF_ENTITY_DOMAIN values for policies attached to users and the account.
+const (
+	PolicyRefDomainUser    = "USER"
+	PolicyRefDomainAccount = "ACCOUNT"
+)
+
+// userPolicyKinds are the policy kinds that can be attached to users and the account.
+var userPolicyKinds = []string{PolicyKindAuthentication, PolicyKindPassword, PolicyKindSession}
+
+// policyShowKeywords maps a policy kind to the object type used in SHOW <type> POLICIES and
+// DESCRIBE <type> POLICY.
+var policyShowKeywords = map[string]string{
+	PolicyKindAuthentication: "AUTHENTICATION",
+	PolicyKindPassword:       "PASSWORD",
+	PolicyKindSession:        "SESSION",
+	PolicyKindMasking:        "MASKING",
+	PolicyKindRowAccess:      "ROW ACCESS",
+}
+
+const authenticationPolicyMFAEnrollment = "MFA_ENROLLMENT"
+
+var (
+	policyStructFieldToColumnMap = map[string]string{
+		structFieldCreatedOn:    columnCreatedOn,
+		structFieldName:         columnName,
+		structFieldDatabaseName: columnDatabaseName,
+		structFieldSchemaName:   columnSchemaName,
+		structFieldOwner:        columnOwner,
+		structFieldComment:      columnComment,
+	}
+
+	// POLICY_REFERENCES is a table function, so its columns come back upper-cased.
+	policyReferenceStructFieldToColumnMap = map[string]string{
+		"PolicyDatabase":  "POLICY_DB",
+		"PolicySchema":    "POLICY_SCHEMA",
+		"PolicyName":      "POLICY_NAME",
+		"PolicyKind":      "POLICY_KIND",
+		"RefDatabaseName": "REF_DATABASE_NAME",
+		"RefSchemaName":   "REF_SCHEMA_NAME",
+		"RefEntityName":   "REF_ENTITY_NAME",
+		"RefEntityDomain": "REF_ENTITY_DOMAIN",
+		"RefColumnName":   "REF_COLUMN_NAME",
+	}
+
+	policyPropertyStructFieldToColumnMap = map[string]string{
+		"Property": "property",
+		"Value":    "value",
+	}
+)
+
+type (
+	// Policy is a schema-level policy as returned by SHOW <kind> POLICIES. Kind is not part of
+	// the SHOW output and is set from the command that listed it.
+	Policy struct {
+		CreatedOn    time.Time
+		Name         string
+		DatabaseName string
+		SchemaName   string
+		Owner        string
+		Comment      string
+		Kind         string
+	}
+
+	// PolicyReference is a row of INFORMATION_SCHEMA.POLICY_REFERENCES: one attachment of a
+	// policy to a user, the account, a table or a column.
+	PolicyReference struct {
+		PolicyDatabase  string
+		PolicySchema    string
+		PolicyName      string
+		PolicyKind      string
+		RefDatabaseName string
+		RefSchemaName   string
+		RefEntityName   string
+		RefEntityDomain string
+		RefColumnName   string
+	}
+
+	policyProperty struct {
+		Property string
+		Value    string
+	}
+
+	// PolicyAttachments is every authentication, password and session policy attachment in the
+	// account, keyed by policy kind. Policy names are fully qualified (DB.SCHEMA.NAME).
+	PolicyAttachments struct {
+		Account map[string]string            `json:"account"`
+		Users   map[string]map[string]string `json:"users"`
+		// MFAEnrollment is each authentication policy's MFA_ENROLLMENT (REQUIRED or OPTIONAL).
+		MFAEnrollment map[string]string `json:"mfa_enrollment"`
+	}
+
+	ListPoliciesRawResponse struct {
+		StatementsApiResponseBase
+	}
+	ListPolicyReferencesRawResponse struct {
+		StatementsApiResponseBase
+	}
+	DescribePolicyRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (p *Policy) GetColumnName(fieldName string) string {
+	return policyStructFieldToColumnMap[fieldName]
+}
+
+func (p *Policy) unparsedFields() []string {
+	return []string{"Kind"}
+}
+
+func (r *PolicyReference) GetColumnName(fieldName string) string {
+	return policyReferenceStructFieldToColumnMap[fieldName]
+}
+
+func (p *policyProperty) GetColumnName(fieldName string) string {
+	return policyPropertyStructFieldToColumnMap[fieldName]
+}
+
+// FullyQualifiedName is the DB.SCHEMA.NAME form used as the policy's resource ID.
+func (p *Policy) FullyQualifiedName() string {
+	return fmt.Sprintf("%s.%s.%s", p.DatabaseName, p.SchemaName, p.Name)
+}
+
+// PolicyFullyQualifiedName is the DB.SCHEMA.NAME of the attached policy, matching
+// Policy.FullyQualifiedName.
+func (r *PolicyReference) PolicyFullyQualifiedName() string {
+	return fmt.Sprintf("%s.%s.%s", r.PolicyDatabase, r.PolicySchema, r.PolicyName)
+}
+
+// ForUser returns the policy of the given kind that applies to username: the user's own
+// attachment if any, otherwise the account's.
+func (a *PolicyAttachments) ForUser(username, kind string) string {
+	if a == nil {
+		return ""
+	}
+	if policy := a.Users[username][kind]; policy != "" {
+		return policy
+	}
+	return a.Account[kind]
+}
+
+// UsersAttachedTo lists the users the named policy is attached to directly.
+func (a *PolicyAttachments) UsersAttachedTo(kind, policyName string) []string {
+	if a == nil {
+		return nil
+	}
+	var users []string
+	for username, policies := range a.Users {
+		if policies[kind] == policyName {
+			users = append(users, username)
+		}
+	}
+	return users
+}
+
+func (r *ListPoliciesRawResponse) GetPolicies(kind string) ([]Policy, error) {
+	var policies []Policy
+	for _, row := range r.Data {
+		policy := &Policy{}
+		if err := r.ResultSetMetadata.ParseRow(policy, row); err != nil {
+			return nil, err
+		}
+		policy.Kind = kind
+		policies = append(policies, *policy)
+	}
+	return policies, nil
+}
+
+func (r *ListPolicyReferencesRawResponse) GetPolicyReferences() ([]PolicyReference, error) {
+	var references []PolicyReference
+	for _, row := range r.Data {
+		reference := &PolicyReference{}
+		if err := r.ResultSetMetadata.ParseRow(reference, row); err != nil {
+			return nil, err
+		}
+		references = append(references, *reference)
+	}
+	return references, nil
+}
+
+func (r *DescribePolicyRawResponse) GetProperties() (map[string]string, error) {
+	properties := make(map[string]string, len(r.Data))
+	for _, row := range r.Data {
+		property := &policyProperty{}
+		if err := r.ResultSetMetadata.ParseRow(property, row); err != nil {
+			return nil, err
+		}
+		properties[strings.ToUpper(property.Property)] = property.Value
+	}
+	return properties, nil
+}
+
+// ListPolicies enumerates every policy of the given kind visible to the current role via
+// SHOW <kind> POLICIES IN ACCOUNT.
+func (c *Client) ListPolicies(ctx context.Context, kind string) ([]Policy, error) {
+	keyword, ok := policyShowKeywords[kind]
+	if !ok {
+		return nil, fmt.Errorf("baton-snowflake: unsupported policy kind %s", kind)
+	}
+
+	var response ListPoliciesRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list %s policies", strings.ToLower(keyword)), "", &response,
+		fmt.Sprintf("SHOW %s POLICIES IN ACCOUNT;", keyword),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetPolicies(kind)
+}
+
+// DescribePolicy returns the policy's properties (upper-cased property name to value).
+func (c *Client) DescribePolicy(ctx context.Context, policy *Policy) (map[string]string, error) {
+	keyword, ok := policyShowKeywords[policy.Kind]
+	if !ok {
+		return nil, fmt.Errorf("baton-snowflake: unsupported policy kind %s", policy.Kind)
+	}
+
+	var response DescribePolicyRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("describe %s policy %s", strings.ToLower(keyword), policy.FullyQualifiedName()), "", &response,
+		fmt.Sprintf("DESCRIBE %s POLICY %s;", keyword, quoteQualifiedName(policy.DatabaseName, policy.SchemaName, policy.Name)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetProperties()
+}
+
+// ListPolicyReferences returns every attachment of policy via the POLICY_REFERENCES table
+// function of the policy's own database.
+func (c *Client) ListPolicyReferences(ctx context.Context, policy *Policy) ([]PolicyReference, error) {
+	qualifiedName := quoteQualifiedName(policy.DatabaseName, policy.SchemaName, policy.Name)
+
+	var response ListPolicyReferencesRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list references of policy %s", policy.FullyQualifiedName()), "", &response,
+		fmt.Sprintf(
+			"SELECT * FROM TABLE(%s.INFORMATION_SCHEMA.POLICY_REFERENCES(POLICY_NAME => '%s'));",
+			quoteQualifiedName(policy.DatabaseName), escapeStringLiteral(qualifiedName),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetPolicyReferences()
+}
+
+const policyAttachmentsCacheKey = "account"
+
+// GetPolicyAttachments collects the authentication, password and session policy attachments
+// of every user and of the account. It costs one POLICY_REFERENCES query per policy, so the
+// result is cached for the rest of the sync. Policy kinds the current role cannot list are
+// left out.
+func (c *Client) GetPolicyAttachments(ctx context.Context, ss sessions.SessionStore) (*PolicyAttachments, error) {
+	if ss != nil {
+		if cached, found, err := session.GetJSON[*PolicyAttachments](ctx, ss, policyAttachmentsCacheKey, policyAttachmentsNamespace); err == nil && found {
+			return cached, nil
+		}
+	}
+
+	attachments := newPolicyAttachments()
+	for _, kind := range userPolicyKinds {
+		policies, err := c.ListPolicies(ctx, kind)
+		if err != nil {
+			if IsInsufficientPrivileges(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for i := range policies {
+			policy := &policies[i]
+			references, err := c.ListPolicyReferences(ctx, policy)
+			if err != nil {
+				if IsInsufficientPrivileges(err) || IsSharedDatabaseUnavailable(err) {
+					continue
+				}
+				return nil, err
+			}
+			for i := range references {
+				attachments.add(kind, policy.FullyQualifiedName(), &references[i])
+			}
+
+			if kind == PolicyKindAuthentication {
+				properties, err := c.DescribePolicy(ctx, policy)
+				if err != nil {
+					if IsInsufficientPrivileges(err) {
+						continue
+					}
+					return nil, err
+				}
+				attachments.MFAEnrollment[policy.FullyQualifiedName()] = properties[authenticationPolicyMFAEnrollment]
+			}
+		}
+	}
+
+	if ss != nil {
+		// Best-effort, like GetUser: a failed write only costs a later call a recomputation.
+		_ = session.SetJSON(ctx, ss, policyAttachmentsCacheKey, attachments, policyAttachmentsNamespace)
+	}
+
+	return attachments, nil
+}
+
+// GetPolicy looks up a policy of the given kind by schema and name. It returns nil when no such
+// policy is visible to the current role. SHOW ... LIKE is a pattern, so the name is matched
+// exactly among the returned rows.
+func (c *Client) GetPolicy(ctx context.Context, kind, database, schema, name string) (*Policy, error) {
+	keyword, ok := policyShowKeywords[kind]
+	if !ok {
+		return nil, fmt.Errorf("baton-snowflake: unsupported policy kind %s", kind)
+	}
+
+	var response ListPoliciesRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("get %s policy %s.%s.%s", strings.ToLower(keyword), database, schema, name), "", &response,
+		fmt.Sprintf("SHOW %s POLICIES LIKE '%s' IN SCHEMA %s LIMIT %d;",
+			keyword, escapeLikeStringLiteral(name), quoteQualifiedName(database, schema), wildcardLookupLimit),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := response.GetPolicies(kind)
+	if err != nil {
+		return nil, err
+	}
+	for i := range policies {
+		if policies[i].Name == name {
+			return &policies[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetUserPolicyAttachments is GetPolicyAttachments for one user: the user's own attachments and
+// the account's, read with a POLICY_REFERENCES query for each instead of one per policy. It is
+// for reading a single user, so it is not cached.
+func (c *Client) GetUserPolicyAttachments(ctx context.Context, username string) (*PolicyAttachments, error) {
+	attachments := newPolicyAttachments()
+
+	var policies []Policy
+	for _, kind := range userPolicyKinds {
+		kindPolicies, err := c.ListPolicies(ctx, kind)
+		if err != nil {
+			if IsInsufficientPrivileges(err) {
+				continue
+			}
+			return nil, err
+		}
+		policies = append(policies, kindPolicies...)
+	}
+	if len(policies) == 0 {
+		return attachments, nil
+	}
+
+	account, err := c.GetCurrentAccount(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// POLICY_REFERENCES is read from a database's INFORMATION_SCHEMA, but for a user or the
+	// account any database returns the same rows, so the first policy's is used.
+	database := policies[0].DatabaseName
+	entities := []struct{ name, domain string }{
+		{username, PolicyRefDomainUser},
+		{account.AccountName, PolicyRefDomainAccount},
+	}
+	for _, entity := range entities {
+		references, err := c.listEntityPolicyReferences(ctx, database, entity.name, entity.domain)
+		if err != nil {
+			if IsInsufficientPrivileges(err) || IsSharedDatabaseUnavailable(err) {
+				continue
+			}
+			return nil, err
+		}
+		for i := range references {
+			kind := strings.ToUpper(references[i].PolicyKind)
+			if Contains(userPolicyKinds, kind) {
+				attachments.add(kind, references[i].PolicyFullyQualifiedName(), &references[i])
+			}
+		}
+	}
+
+	for i := range policies {
+		policy := &policies[i]
+		if policy.Kind != PolicyKindAuthentication || !attachments.attaches(policy.FullyQualifiedName()) {
+			continue
+		}
+		properties, err := c.DescribePolicy(ctx, policy)
+		if err != nil {
+			if IsInsufficientPrivileges(err) {
+				continue
+			}
+			return nil, err
+		}
+		attachments.MFAEnrollment[policy.FullyQualifiedName()] = properties[authenticationPolicyMFAEnrollment]
+	}
+
+	return attachments, nil
+}
+
+// listEntityPolicyReferences returns the policies attached to one object via the POLICY_REFERENCES
+// table function of database. domain is the object's REF_ENTITY_DOMAIN, e.g. user or table.
+func (c *Client) listEntityPolicyReferences(ctx context.Context, database, name, domain string) ([]PolicyReference, error) {
+	var response ListPolicyReferencesRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list policy references of %s %s", strings.ToLower(domain), name), "", &response,
+		fmt.Sprintf(
+			"SELECT * FROM TABLE(%s.INFORMATION_SCHEMA.POLICY_REFERENCES(REF_ENTITY_NAME => '%s', REF_ENTITY_DOMAIN => '%s'));",
+			quoteQualifiedName(database), escapeStringLiteral(name), strings.ToLower(domain),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetPolicyReferences()
+}
+
+func newPolicyAttachments() *PolicyAttachments {
+	return &PolicyAttachments{
+		Account:       map[string]string{},
+		Users:         map[string]map[string]string{},
+		MFAEnrollment: map[string]string{},
+	}
+}
+
+// add records the attachment of the named policy of kind that reference describes.
+func (a *PolicyAttachments) add(kind, policyName string, reference *PolicyReference) {
+	switch strings.ToUpper(reference.RefEntityDomain) {
+	case PolicyRefDomainAccount:
+		a.Account[kind] = policyName
+	case PolicyRefDomainUser:
+		if a.Users[reference.RefEntityName] == nil {
+			a.Users[reference.RefEntityName] = map[string]string{}
+		}
+		a.Users[reference.RefEntityName][kind] = policyName
+	}
+}
+
+// attaches reports whether the named policy is attached to the account or any user.
+func (a *PolicyAttachments) attaches(policyName string) bool {
+	for _, policy := range a.Account {
+		if policy == policyName {
+			return true
+		}
+	}
+	for _, policies := range a.Users {
+		for _, policy := range policies {
+			if policy == policyName {
+				return true
+			}
+		}
+	}
+	return false
+}
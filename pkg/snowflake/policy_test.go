@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPolicyReferences_QuotesPolicyName(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, err = client.ListPolicyReferences(context.Background(), &Policy{
+		DatabaseName: "SECURITY",
+		SchemaName:   "Policies",
+		Name:         `o'brien"s`,
+		Kind:         PolicyKindAuthentication,
+	})
+	require.NoError(t, err)
+	assert.Equal(t,
+		`SELECT * FROM TABLE("SECURITY".INFORMATION_SCHEMA.POLICY_REFERENCES(POLICY_NAME => '"SECURITY"."Policies"."o''brien""s"'));`,
+		capturedSQL,
+	)
+}
+
+func TestListPolicies_RejectsUnknownKind(t *testing.T) {
+	client, err := New("http://localhost", JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, err = client.ListPolicies(context.Background(), "AGGREGATION_POLICY")
+	require.Error(t, err)
+}
+
+func TestListPolicyReferences_ParsesRows(t *testing.T) {
+	server := serveRows(t,
+		[]string{"POLICY_DB", "POLICY_SCHEMA", "POLICY_NAME", "POLICY_KIND", "REF_DATABASE_NAME", "REF_SCHEMA_NAME", "REF_ENTITY_NAME", "REF_ENTITY_DOMAIN", "REF_COLUMN_NAME"},
+		[][]string{
+			{"SECURITY", "PUBLIC", "MFA_REQUIRED", PolicyKindAuthentication, "", "", "SVC_ETL", "USER", ""},
+			{"SECURITY", "PUBLIC", "MFA_REQUIRED", PolicyKindAuthentication, "", "", "ACME", "ACCOUNT", ""},
+		},
+	)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	references, err := client.ListPolicyReferences(context.Background(), &Policy{DatabaseName: "SECURITY", SchemaName: "PUBLIC", Name: "MFA_REQUIRED"})
+	require.NoError(t, err)
+	require.Len(t, references, 2)
+	assert.Equal(t, "SECURITY.PUBLIC.MFA_REQUIRED", references[0].PolicyFullyQualifiedName())
+	assert.Equal(t, "SVC_ETL", references[0].RefEntityName)
+	assert.Equal(t, PolicyRefDomainAccount, references[1].RefEntityDomain)
+}
+
+func TestPolicyAttachments_ForUser(t *testing.T) {
+	attachments := &PolicyAttachments{
+		Account: map[string]string{PolicyKindAuthentication: "SECURITY.PUBLIC.DEFAULT_AUTH"},
+		Users: map[string]map[string]string{
+			"SVC_ETL": {PolicyKindAuthentication: "SECURITY.PUBLIC.KEYPAIR_ONLY"},
+		},
+	}
+
+	assert.Equal(t, "SECURITY.PUBLIC.KEYPAIR_ONLY", attachments.ForUser("SVC_ETL", PolicyKindAuthentication))
+	assert.Equal(t, "SECURITY.PUBLIC.DEFAULT_AUTH", attachments.ForUser("ALICE", PolicyKindAuthentication))
+	assert.Equal(t, "", attachments.ForUser("ALICE", PolicyKindPassword))
+	assert.Equal(t, []string{"SVC_ETL"}, attachments.UsersAttachedTo(PolicyKindAuthentication, "SECURITY.PUBLIC.KEYPAIR_ONLY"))
+	assert.Empty(t, attachments.UsersAttachedTo(PolicyKindAuthentication, "SECURITY.PUBLIC.DEFAULT_AUTH"))
+}
+
+// TestUserPolicyStatements_RunUnderDefaultRole verifies that attaching and detaching user
+// policies does not switch to USERADMIN, which lacks the APPLY and ATTACH POLICY privileges.
+func TestUserPolicyStatements_RunUnderDefaultRole(t *testing.T) {
+	var roles []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req StatementsApiRequestBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		roles = append(roles, req.Role)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"statementHandle": "handle"})
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, client.SetUserNetworkPolicy(ctx, "ALICE", "CORP_ONLY"))
+	require.NoError(t, client.SetUserPolicy(ctx, "ALICE", &Policy{DatabaseName: "SECURITY", SchemaName: publicSchema, Name: "STRICT", Kind: PolicyKindPassword}))
+	require.NoError(t, client.UnsetUserPolicy(ctx, "ALICE", PolicyKindNetwork))
+	assert.Equal(t, []string{"", "", ""}, roles)
+}
+
+func TestGetUserPolicyAttachments_WithoutPoliciesReadsNoReferences(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	attachments, err := client.GetUserPolicyAttachments(context.Background(), "JDOE")
+	require.NoError(t, err)
+	assert.Equal(t, "SHOW SESSION POLICIES IN ACCOUNT;", capturedSQL)
+	assert.Empty(t, attachments.ForUser("JDOE", PolicyKindAuthentication))
+}
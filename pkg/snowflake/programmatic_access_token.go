@@ -0,0 +1,162 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ProgrammaticAccessTokenStatusActive is the status of a token that can still authenticate.
+// Others are EXPIRED and DISABLED.
+const ProgrammaticAccessTokenStatusActive = "ACTIVE"
+
+var programmaticAccessTokenStructFieldToColumnMap = map[string]string{
+	structFieldCreatedOn: columnCreatedOn,
+	structFieldName:      columnName,
+	structFieldComment:   columnComment,
+	"UserName":           "user_name",
+	"RoleRestriction":    "role_restriction",
+	"ExpiresAt":          "expires_at",
+	"Status":             "status",
+	"CreatedBy":          "created_by",
+}
+
+var programmaticAccessTokenSecretStructFieldToColumnMap = map[string]string{
+	structFieldName: "token_name",
+	"Secret":        "token_secret",
+}
+
+type (
+	// ProgrammaticAccessToken is a row of SHOW USER PROGRAMMATIC ACCESS TOKENS. Snowflake does
+	// not report when a token was last used.
+	ProgrammaticAccessToken struct {
+		CreatedOn       time.Time
+		Name            string
+		UserName        string
+		RoleRestriction string
+		ExpiresAt       time.Time
+		Status          string
+		Comment         string
+		CreatedBy       string
+	}
+
+	// ProgrammaticAccessTokenSecret is the single row ALTER USER ... ADD and ROTATE PROGRAMMATIC
+	// ACCESS TOKEN return. Snowflake shows the secret only once, in this response.
+	ProgrammaticAccessTokenSecret struct {
+		Name   string
+		Secret string
+	}
+
+	// AddProgrammaticAccessTokenOptions are the optional properties of a new token. A zero
+	// DaysToExpiry leaves Snowflake's default of 15 days.
+	AddProgrammaticAccessTokenOptions struct {
+		RoleRestriction string
+		DaysToExpiry    int
+		Comment         string
+	}
+
+	ListProgrammaticAccessTokensRawResponse struct {
+		StatementsApiResponseBase
+	}
+	ProgrammaticAccessTokenSecretRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (t *ProgrammaticAccessToken) GetColumnName(fieldName string) string {
+	return programmaticAccessTokenStructFieldToColumnMap[fieldName]
+}
+
+// IsActive reports whether the token can still be used to authenticate.
+func (t *ProgrammaticAccessToken) IsActive() bool {
+	return strings.EqualFold(t.Status, ProgrammaticAccessTokenStatusActive)
+}
+
+func (t *ProgrammaticAccessTokenSecret) GetColumnName(fieldName string) string {
+	return programmaticAccessTokenSecretStructFieldToColumnMap[fieldName]
+}
+
+func (r *ListProgrammaticAccessTokensRawResponse) GetProgrammaticAccessTokens() ([]ProgrammaticAccessToken, error) {
+	var tokens []ProgrammaticAccessToken
+	for _, row := range r.Data {
+		token := &ProgrammaticAccessToken{}
+		if err := r.ResultSetMetadata.ParseRow(token, row); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, nil
+}
+
+// ListProgrammaticAccessTokens returns the programmatic access tokens of a user. Like DESCRIBE
+// USER it needs OWNERSHIP or MONITOR on the user.
+func (c *Client) ListProgrammaticAccessTokens(ctx context.Context, username string) ([]ProgrammaticAccessToken, error) {
+	var response ListProgrammaticAccessTokensRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list programmatic access tokens of user %s", username), "", &response,
+		fmt.Sprintf("SHOW USER PROGRAMMATIC ACCESS TOKENS FOR USER %s;", quoteQualifiedName(username)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetProgrammaticAccessTokens()
+}
+
+func (r *ProgrammaticAccessTokenSecretRawResponse) GetProgrammaticAccessTokenSecret() (*ProgrammaticAccessTokenSecret, error) {
+	if len(r.Data) == 0 {
+		return nil, fmt.Errorf("baton-snowflake: statement returned no programmatic access token")
+	}
+	secret := &ProgrammaticAccessTokenSecret{}
+	if err := r.ResultSetMetadata.ParseRow(secret, r.Data[0]); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// AddProgrammaticAccessToken mints a new token for a user and returns its secret. Like the other
+// ALTER USER statements it runs as UserAdminRole.
+func (c *Client) AddProgrammaticAccessToken(ctx context.Context, username, tokenName string, opts *AddProgrammaticAccessTokenOptions) (*ProgrammaticAccessTokenSecret, error) {
+	query := fmt.Sprintf("ALTER USER %s ADD PROGRAMMATIC ACCESS TOKEN %s", quoteQualifiedName(username), quoteQualifiedName(tokenName))
+	if opts != nil {
+		if opts.RoleRestriction != "" {
+			query += fmt.Sprintf(" ROLE_RESTRICTION = '%s'", escapeStringLiteral(opts.RoleRestriction))
+		}
+		if opts.DaysToExpiry > 0 {
+			query += fmt.Sprintf(" DAYS_TO_EXPIRY = %d", opts.DaysToExpiry)
+		}
+		if opts.Comment != "" {
+			query += fmt.Sprintf(" COMMENT = '%s'", escapeStringLiteral(opts.Comment))
+		}
+	}
+
+	var response ProgrammaticAccessTokenSecretRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("add programmatic access token %s to user %s", tokenName, username), UserAdminRole, &response, query+";")
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetProgrammaticAccessTokenSecret()
+}
+
+// RotateProgrammaticAccessToken replaces a token's secret and returns the new one. The token
+// keeps its name, role restriction and expiry; Snowflake renames the old secret and expires it
+// after 24 hours so clients can be switched over.
+func (c *Client) RotateProgrammaticAccessToken(ctx context.Context, username, tokenName string) (*ProgrammaticAccessTokenSecret, error) {
+	var response ProgrammaticAccessTokenSecretRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("rotate programmatic access token %s of user %s", tokenName, username), UserAdminRole, &response,
+		fmt.Sprintf("ALTER USER %s ROTATE PROGRAMMATIC ACCESS TOKEN %s;", quoteQualifiedName(username), quoteQualifiedName(tokenName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetProgrammaticAccessTokenSecret()
+}
+
+// RemoveProgrammaticAccessToken revokes a user's token. It runs as UserAdminRole.
+func (c *Client) RemoveProgrammaticAccessToken(ctx context.Context, username, tokenName string) error {
+	return c.execStatement(ctx, fmt.Sprintf("remove programmatic access token %s from user %s", tokenName, username), UserAdminRole,
+		fmt.Sprintf("ALTER USER %s REMOVE PROGRAMMATIC ACCESS TOKEN %s;", quoteQualifiedName(username), quoteQualifiedName(tokenName)),
+	)
+}
@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListProgrammaticAccessTokens_ParsesRows(t *testing.T) {
+	server := serveRows(t,
+		[]string{"name", "user_name", "role_restriction", "expires_at", "status", "comment", "created_on", "created_by"},
+		[][]string{
+			{"ETL_TOKEN", "SVC_ETL", "LOADER", "1735689600.000000000", "ACTIVE", "", "1700000000.000000000", "ADMIN"},
+			{"OLD_TOKEN", "SVC_ETL", "", "", "EXPIRED", "", "1690000000.000000000", "SVC_ETL"},
+		},
+	)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	tokens, err := client.ListProgrammaticAccessTokens(context.Background(), "SVC_ETL")
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "LOADER", tokens[0].RoleRestriction)
+	assert.Equal(t, int64(1735689600), tokens[0].ExpiresAt.Unix())
+	assert.True(t, tokens[0].IsActive())
+	assert.True(t, tokens[1].ExpiresAt.IsZero())
+	assert.False(t, tokens[1].IsActive())
+}
+
+func TestListProgrammaticAccessTokens_QuotesUsername(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, err = client.ListProgrammaticAccessTokens(context.Background(), `o"brien`)
+	require.NoError(t, err)
+	assert.Equal(t, `SHOW USER PROGRAMMATIC ACCESS TOKENS FOR USER "o""brien";`, capturedSQL)
+}
+
+func TestAddProgrammaticAccessToken_ReturnsSecret(t *testing.T) {
+	server := serveRows(t,
+		[]string{"token_name", "token_secret"},
+		[][]string{{"BATON_REQ_1", "secret-value"}},
+	)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	secret, err := client.AddProgrammaticAccessToken(context.Background(), "SVC_ETL", "BATON_REQ_1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "BATON_REQ_1", secret.Name)
+	assert.Equal(t, "secret-value", secret.Secret)
+}
+
+func TestAddProgrammaticAccessToken_BuildsStatement(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, err = client.AddProgrammaticAccessToken(context.Background(), "SVC_ETL", "ETL_TOKEN", &AddProgrammaticAccessTokenOptions{
+		RoleRestriction: "LOADER",
+		DaysToExpiry:    30,
+		Comment:         "it's for etl",
+	})
+	require.Error(t, err, "captureStatement returns no rows")
+	assert.Equal(t,
+		`ALTER USER "SVC_ETL" ADD PROGRAMMATIC ACCESS TOKEN "ETL_TOKEN" ROLE_RESTRICTION = 'LOADER' DAYS_TO_EXPIRY = 30 COMMENT = 'it''s for etl';`,
+		capturedSQL,
+	)
+}
+
+func TestRotateAndRemoveProgrammaticAccessToken_Statements(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, _ = client.RotateProgrammaticAccessToken(context.Background(), "SVC_ETL", "ETL_TOKEN")
+	assert.Equal(t, `ALTER USER "SVC_ETL" ROTATE PROGRAMMATIC ACCESS TOKEN "ETL_TOKEN";`, capturedSQL)
+
+	require.NoError(t, client.RemoveProgrammaticAccessToken(context.Background(), "SVC_ETL", "ETL_TOKEN"))
+	assert.Equal(t, `ALTER USER "SVC_ETL" REMOVE PROGRAMMATIC ACCESS TOKEN "ETL_TOKEN";`, capturedSQL)
+}
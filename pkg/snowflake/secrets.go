@@ -3,6 +3,7 @@ package snowflake
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
@@ -14,6 +15,13 @@ import (
 
 const snowflakeDateFormat = "2006-01-02 15:04:05.999"
 
+// RsaPublicKeyProperty and RsaPublicKey2Property are the two user properties that hold key-pair
+// authentication keys. Having two lets a key be rotated without an outage.
+const (
+	RsaPublicKeyProperty  = "RSA_PUBLIC_KEY"
+	RsaPublicKey2Property = "RSA_PUBLIC_KEY_2"
+)
+
 func (c *Client) ListSecrets(ctx context.Context, database string) ([]Secret, error) {
 	l := ctxzap.Extract(ctx)
 
@@ -100,6 +108,15 @@ func findUserDescriptionPropertyValue(properties []UserDescriptionProperty, name
 	return ""
 }
 
+// nonNullPropertyValue is findUserDescriptionPropertyValue with DESCRIBE USER's "null" mapped to "".
+func nonNullPropertyValue(properties []UserDescriptionProperty, name string) string {
+	value := findUserDescriptionPropertyValue(properties, name)
+	if value == rowNull {
+		return ""
+	}
+	return value
+}
+
 func (r *RsaGetUserRawResponse) GetUserRsa(ctx context.Context) (*UserRsa, error) {
 	rsa := &UserRsa{}
 
@@ -134,5 +151,38 @@ func (r *RsaGetUserRawResponse) GetUserRsa(ctx context.Context) (*UserRsa, error
 		rsa.RsaPublicKeyLastSetTime2 = &rsa2Time
 	}
 
+	rsa.RsaPublicKey = nonNullPropertyValue(userDescriptions, RsaPublicKeyProperty)
+	rsa.RsaPublicKey2 = nonNullPropertyValue(userDescriptions, RsaPublicKey2Property)
+	rsa.RsaPublicKeyFingerprint = nonNullPropertyValue(userDescriptions, "RSA_PUBLIC_KEY_FP")
+	rsa.RsaPublicKeyFingerprint2 = nonNullPropertyValue(userDescriptions, "RSA_PUBLIC_KEY_2_FP")
+
 	return rsa, nil
 }
+
+func validRsaPublicKeyProperty(property string) error {
+	if property != RsaPublicKeyProperty && property != RsaPublicKey2Property {
+		return fmt.Errorf("baton-snowflake: invalid rsa public key property %q", property)
+	}
+	return nil
+}
+
+// SetUserRsaPublicKey sets one of the user's key-pair slots to publicKey, the base64 DER body of
+// a PEM public key. Like the other ALTER USER statements it runs as UserAdminRole.
+func (c *Client) SetUserRsaPublicKey(ctx context.Context, username, property, publicKey string) error {
+	if err := validRsaPublicKeyProperty(property); err != nil {
+		return err
+	}
+	return c.execStatement(ctx, fmt.Sprintf("set %s on user %s", strings.ToLower(property), username), UserAdminRole,
+		fmt.Sprintf("ALTER USER %s SET %s = '%s';", quoteQualifiedName(username), property, escapeStringLiteral(publicKey)),
+	)
+}
+
+// UnsetUserRsaPublicKey clears one of the user's key-pair slots. It runs as UserAdminRole.
+func (c *Client) UnsetUserRsaPublicKey(ctx context.Context, username, property string) error {
+	if err := validRsaPublicKeyProperty(property); err != nil {
+		return err
+	}
+	return c.execStatement(ctx, fmt.Sprintf("unset %s on user %s", strings.ToLower(property), username), UserAdminRole,
+		fmt.Sprintf("ALTER USER %s UNSET %s;", quoteQualifiedName(username), property),
+	)
+}
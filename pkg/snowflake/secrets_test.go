@@ -43,3 +43,35 @@ func TestUserRsa_EscapesUsername(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, `DESCRIBE USER "weird""user";`, capturedSQL)
 }
+
+func TestGetUserRsa_ParsesKeysAndFingerprints(t *testing.T) {
+	response := &RsaGetUserRawResponse{
+		StatementsApiResponseBase: StatementsApiResponseBase{
+			ResultSetMetadata: ResultSetMetadata{
+				RowTypes: []RowType{
+					{Name: "property", Type: rowTypeString},
+					{Name: "value", Type: rowTypeString},
+					{Name: "default", Type: rowTypeString},
+					{Name: "description", Type: rowTypeString},
+				},
+			},
+			Data: [][]string{
+				{"NAME", "SVC_ETL", "null", ""},
+				{"RSA_PUBLIC_KEY", "MIIBIjAN", "null", ""},
+				{"RSA_PUBLIC_KEY_FP", "SHA256:abc=", "null", ""},
+				{"RSA_PUBLIC_KEY_LAST_SET_TIME", "2025-06-01 00:00:00.000", "null", ""},
+				{"RSA_PUBLIC_KEY_2", "null", "null", ""},
+				{"RSA_PUBLIC_KEY_2_FP", "null", "null", ""},
+			},
+		},
+	}
+
+	rsa, err := response.GetUserRsa(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "MIIBIjAN", rsa.RsaPublicKey)
+	assert.Equal(t, "SHA256:abc=", rsa.RsaPublicKeyFingerprint)
+	assert.NotNil(t, rsa.RsaPublicKeyLastSetTime)
+	assert.Empty(t, rsa.RsaPublicKey2)
+	assert.Empty(t, rsa.RsaPublicKeyFingerprint2)
+	assert.Nil(t, rsa.RsaPublicKeyLastSetTime2)
+}
@@ -0,0 +1,208 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ShareKindOutbound is the SHOW SHARES kind of shares this account provides to others.
+// INBOUND shares surface as SHARED databases instead; see Database.IsSharedOrSystem.
+const ShareKindOutbound = "OUTBOUND"
+
+var (
+	shareStructFieldToColumnMap = map[string]string{
+		structFieldCreatedOn:    columnCreatedOn,
+		structFieldKind:         columnKind,
+		structFieldName:         columnName,
+		structFieldDatabaseName: columnDatabaseName,
+		structFieldOwner:        columnOwner,
+		structFieldComment:      columnComment,
+		"OwnerAccount":          "owner_account",
+		"To":                    "to",
+	}
+
+	managedAccountStructFieldToColumnMap = map[string]string{
+		structFieldCreatedOn: columnCreatedOn,
+		structFieldName:      columnName,
+		structFieldComment:   columnComment,
+		"Cloud":              "cloud",
+		"Region":             "region",
+		"Locator":            "locator",
+		"URL":                "url",
+		"IsReader":           "is_reader",
+	}
+)
+
+type (
+	// Share is a row of SHOW SHARES. To is the comma-separated list of consumer accounts; see
+	// Share.ConsumerAccounts.
+	Share struct {
+		CreatedOn    time.Time
+		Kind         string
+		OwnerAccount string
+		Name         string
+		DatabaseName string
+		To           string
+		Owner        string
+		Comment      string
+	}
+
+	// ManagedAccount is a row of SHOW MANAGED ACCOUNTS. Reader accounts are managed accounts
+	// created to give non-Snowflake customers access to this account's shares.
+	ManagedAccount struct {
+		CreatedOn time.Time
+		Name      string
+		Cloud     string
+		Region    string
+		Locator   string
+		URL       string
+		Comment   string
+		IsReader  bool
+	}
+
+	ListSharesRawResponse struct {
+		StatementsApiResponseBase
+	}
+	ListManagedAccountsRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (s *Share) GetColumnName(fieldName string) string {
+	return shareStructFieldToColumnMap[fieldName]
+}
+
+func (m *ManagedAccount) GetColumnName(fieldName string) string {
+	return managedAccountStructFieldToColumnMap[fieldName]
+}
+
+// IsOutbound reports whether the share is provided by this account.
+func (s *Share) IsOutbound() bool {
+	return strings.EqualFold(s.Kind, ShareKindOutbound)
+}
+
+// ConsumerAccounts lists the accounts the share is granted to, as SHOW SHARES reports them
+// (ORG.ACCOUNT_NAME or a legacy locator).
+func (s *Share) ConsumerAccounts() []string {
+	return splitValueList(s.To)
+}
+
+// MatchesConsumer reports whether consumer, an entry of Share.ConsumerAccounts, names this
+// managed account. Managed accounts belong to the provider's organization, so an ORG.ACCOUNT_NAME
+// consumer matches only when ORG is organization; a consumer in the legacy locator form has no
+// organization and matches the locator or, failing that, the account name.
+func (m *ManagedAccount) MatchesConsumer(consumer, organization string) bool {
+	if org, name, ok := strings.Cut(consumer, "."); ok {
+		return strings.EqualFold(org, organization) && strings.EqualFold(name, m.Name)
+	}
+	return (m.Locator != "" && strings.EqualFold(consumer, m.Locator)) || strings.EqualFold(consumer, m.Name)
+}
+
+func (r *ListSharesRawResponse) GetShares() ([]Share, error) {
+	var shares []Share
+	for _, row := range r.Data {
+		share := &Share{}
+		if err := r.ResultSetMetadata.ParseRow(share, row); err != nil {
+			return nil, err
+		}
+		shares = append(shares, *share)
+	}
+	return shares, nil
+}
+
+func (r *ListManagedAccountsRawResponse) GetManagedAccounts() ([]ManagedAccount, error) {
+	var accounts []ManagedAccount
+	for _, row := range r.Data {
+		account := &ManagedAccount{}
+		if err := r.ResultSetMetadata.ParseRow(account, row); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, nil
+}
+
+// ListOutboundShares returns the shares this account provides to other accounts.
+func (c *Client) ListOutboundShares(ctx context.Context) ([]Share, error) {
+	var response ListSharesRawResponse
+	_, err := c.runStatement(ctx, "list shares", "", &response, "SHOW SHARES;")
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := response.GetShares()
+	if err != nil {
+		return nil, err
+	}
+
+	var outbound []Share
+	for _, share := range shares {
+		if share.IsOutbound() {
+			outbound = append(outbound, share)
+		}
+	}
+	return outbound, nil
+}
+
+// GetOutboundShare looks up an outbound share by name. It returns nil when no such share is
+// visible to the current role.
+func (c *Client) GetOutboundShare(ctx context.Context, name string) (*Share, error) {
+	var response ListSharesRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("get share %s", name), "", &response,
+		fmt.Sprintf("SHOW SHARES LIKE '%s' LIMIT %d;", escapeLikeStringLiteral(name), wildcardLookupLimit),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := response.GetShares()
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		if shares[i].IsOutbound() && shares[i].Name == name {
+			return &shares[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ListShareGrants runs SHOW GRANTS TO SHARE: one row per object the share exposes. The rows
+// have the same shape as SHOW GRANTS ON TABLE, so they are returned as TableGrants, with the
+// object in GrantedOn and Name.
+func (c *Client) ListShareGrants(ctx context.Context, share string) ([]TableGrant, error) {
+	var response ListTableGrantsRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list grants to share %s", share), "", &response,
+		fmt.Sprintf("SHOW GRANTS TO SHARE %s;", quoteQualifiedName(share)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetTableGrants()
+}
+
+// ListReaderAccounts returns the reader accounts managed by this account.
+func (c *Client) ListReaderAccounts(ctx context.Context) ([]ManagedAccount, error) {
+	var response ListManagedAccountsRawResponse
+	_, err := c.runStatement(ctx, "list managed accounts", "", &response, "SHOW MANAGED ACCOUNTS;")
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := response.GetManagedAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var readers []ManagedAccount
+	for _, account := range accounts {
+		if account.IsReader {
+			readers = append(readers, account)
+		}
+	}
+	return readers, nil
+}
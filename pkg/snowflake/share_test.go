@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOutboundShares_SkipsInbound(t *testing.T) {
+	server := serveRows(t,
+		[]string{"created_on", "kind", "owner_account", "name", "database_name", "to", "owner", "comment"},
+		[][]string{
+			{"1700000000.000000000", "INBOUND", "PARTNER.ACCT", "WEATHER", "WEATHER_DB", "", "", ""},
+			{"1700000000.000000000", "OUTBOUND", "ACME.PROD", "SALES_S", "SALES_DB", "ACME.READER1, PARTNER.ACCT", "ACCOUNTADMIN", ""},
+		},
+	)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	shares, err := client.ListOutboundShares(context.Background())
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	assert.Equal(t, "SALES_S", shares[0].Name)
+	assert.Equal(t, []string{"ACME.READER1", "PARTNER.ACCT"}, shares[0].ConsumerAccounts())
+}
+
+func TestListShareGrants_QuotesShareName(t *testing.T) {
+	var capturedSQL string
+	server := captureStatement(t, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	_, err = client.ListShareGrants(context.Background(), `sales"s`)
+	require.NoError(t, err)
+	assert.Equal(t, `SHOW GRANTS TO SHARE "sales""s";`, capturedSQL)
+}
+
+func TestManagedAccount_MatchesConsumer(t *testing.T) {
+	reader := &ManagedAccount{Name: "READER1", Locator: "RE47190"}
+
+	assert.True(t, reader.MatchesConsumer("ACME.READER1", "acme"))
+	assert.True(t, reader.MatchesConsumer("re47190", "ACME"))
+	assert.True(t, reader.MatchesConsumer("READER1", "ACME"))
+	assert.False(t, reader.MatchesConsumer("PARTNER.READER1", "ACME"), "an account of the same name in another organization")
+	assert.False(t, reader.MatchesConsumer("PARTNER.ACCT", "ACME"))
+}
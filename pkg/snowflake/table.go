@@ -3,6 +3,7 @@ package snowflake
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -269,6 +270,9 @@ func escapeDoubleQuotedIdentifier(s string) string {
 	return strings.ReplaceAll(s, `"`, `""`)
 }
 
+// ErrTableNotFound is returned by GetTable when the schema has no table with the exact name.
+var ErrTableNotFound = errors.New("baton-snowflake: table not found")
+
 func (c *Client) GetTable(ctx context.Context, database, schema, tableName string) (*Table, error) {
 	// SHOW TABLES' LIKE has no ESCAPE clause, so _ and % stay live wildcards; adding "ESCAPE '\'"
 	// here (a prior version did) makes Snowflake reject the query with a 422.
@@ -318,7 +322,7 @@ func (c *Client) GetTable(ctx context.Context, database, schema, tableName strin
 		}
 	}
 
-	return nil, fmt.Errorf("table %s.%s.%s not found", database, schema, tableName)
+	return nil, fmt.Errorf("%w: %s.%s.%s", ErrTableNotFound, database, schema, tableName)
 }
 
 var tableGrantStructFieldToColumnMap = map[string]string{
@@ -463,6 +467,20 @@ func (c *Client) ListTableGrants(ctx context.Context, ss sessions.SessionStore,
 	return page.Grants, nextCursor, nil
 }
 
+// ForgetTableGrants drops the grants ListTableGrants cached for the table or view.
+func (c *Client) ForgetTableGrants(ctx context.Context, ss sessions.SessionStore, database, schema, tableName string) error {
+	for _, objectKind := range []string{"TABLE", "VIEW"} {
+		cacheKey := tableGrantsCacheKey(database, schema, tableName, objectKind)
+		if err := session.DeleteJSON(ctx, ss, cacheKey, tableGrantsNamespace); err != nil {
+			return err
+		}
+		if err := session.DeleteJSON(ctx, ss, cacheKey, tableGrantsPartialNamespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // tableGrantsFirstPage is the result of the initial SHOW GRANTS ON TABLE/VIEW request: partition
 // 0's rows plus everything needed to page through the rest (mirrors what the cursor later carries
 // into listTableGrantsPartition).
@@ -0,0 +1,385 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/session"
+	"github.com/conductorone/baton-sdk/pkg/types/sessions"
+)
+
+// TAG_REFERENCES DOMAIN values for the objects whose tags are synced.
+const (
+	TagDomainDatabase = "DATABASE"
+	TagDomainSchema   = "SCHEMA"
+	TagDomainTable    = "TABLE"
+	TagDomainColumn   = "COLUMN"
+)
+
+var (
+	tagStructFieldToColumnMap = map[string]string{
+		structFieldCreatedOn:    columnCreatedOn,
+		structFieldName:         columnName,
+		structFieldDatabaseName: columnDatabaseName,
+		structFieldSchemaName:   columnSchemaName,
+		structFieldOwner:        columnOwner,
+		structFieldComment:      columnComment,
+		"AllowedValues":         "allowed_values",
+	}
+
+	// ACCOUNT_USAGE views return upper-cased column names.
+	tagReferenceStructFieldToColumnMap = map[string]string{
+		"TagDatabase":    "TAG_DATABASE",
+		"TagSchema":      "TAG_SCHEMA",
+		"TagName":        "TAG_NAME",
+		"TagValue":       "TAG_VALUE",
+		"ObjectDatabase": "OBJECT_DATABASE",
+		"ObjectSchema":   "OBJECT_SCHEMA",
+		"ObjectName":     "OBJECT_NAME",
+		"ColumnName":     "COLUMN_NAME",
+		"Domain":         "DOMAIN",
+	}
+)
+
+type (
+	// Tag is a tag as returned by SHOW TAGS. AllowedValues is the raw JSON array Snowflake
+	// reports, or empty when any value is allowed; see Tag.AllowedValueList.
+	Tag struct {
+		CreatedOn     time.Time
+		Name          string
+		DatabaseName  string
+		SchemaName    string
+		Owner         string
+		Comment       string
+		AllowedValues string
+	}
+
+	// TagReference is a row of SNOWFLAKE.ACCOUNT_USAGE.TAG_REFERENCES: one tag value set on a
+	// database, schema, table or column.
+	TagReference struct {
+		TagDatabase    string
+		TagSchema      string
+		TagName        string
+		TagValue       string
+		ObjectDatabase string
+		ObjectSchema   string
+		ObjectName     string
+		ColumnName     string
+		Domain         string
+	}
+
+	// ObjectTags is every tag set directly on a database, schema, table or column, keyed by the
+	// object (DB, DB.SCHEMA, DB.SCHEMA.TABLE) and then by the tag's DB.SCHEMA.NAME. Columns are
+	// keyed by table and then by column name.
+	ObjectTags struct {
+		Databases map[string]map[string]string            `json:"databases"`
+		Schemas   map[string]map[string]string            `json:"schemas"`
+		Tables    map[string]map[string]string            `json:"tables"`
+		Columns   map[string]map[string]map[string]string `json:"columns"`
+		// Unavailable is set when TAG_REFERENCES could not be read, so an empty result does
+		// not mean the objects are untagged.
+		Unavailable bool `json:"unavailable,omitempty"`
+	}
+
+	ListTagsRawResponse struct {
+		StatementsApiResponseBase
+	}
+	ListTagReferencesRawResponse struct {
+		StatementsApiResponseBase
+	}
+)
+
+func (t *Tag) GetColumnName(fieldName string) string {
+	return tagStructFieldToColumnMap[fieldName]
+}
+
+func (r *TagReference) GetColumnName(fieldName string) string {
+	return tagReferenceStructFieldToColumnMap[fieldName]
+}
+
+// FullyQualifiedName is the DB.SCHEMA.NAME form used as the tag's resource ID and as the key
+// of ObjectTags entries.
+func (t *Tag) FullyQualifiedName() string {
+	return fmt.Sprintf("%s.%s.%s", t.DatabaseName, t.SchemaName, t.Name)
+}
+
+// AllowedValueList parses AllowedValues. It returns nil when the tag accepts any value.
+func (t *Tag) AllowedValueList() []string {
+	if t.AllowedValues == "" || t.AllowedValues == rowNull {
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal([]byte(t.AllowedValues), &values); err != nil {
+		return splitValueList(t.AllowedValues)
+	}
+	return values
+}
+
+// TagFullyQualifiedName is the DB.SCHEMA.NAME of the referenced tag, matching
+// Tag.FullyQualifiedName.
+func (r *TagReference) TagFullyQualifiedName() string {
+	return fmt.Sprintf("%s.%s.%s", r.TagDatabase, r.TagSchema, r.TagName)
+}
+
+// ForDatabase returns the tags set on the database.
+func (o *ObjectTags) ForDatabase(database string) map[string]string {
+	if o == nil {
+		return nil
+	}
+	return o.Databases[database]
+}
+
+// ForSchema returns the tags set on database.schema.
+func (o *ObjectTags) ForSchema(database, schema string) map[string]string {
+	if o == nil {
+		return nil
+	}
+	return o.Schemas[fmt.Sprintf("%s.%s", database, schema)]
+}
+
+// ForTable returns the tags set on database.schema.table.
+func (o *ObjectTags) ForTable(database, schema, table string) map[string]string {
+	if o == nil {
+		return nil
+	}
+	return o.Tables[fmt.Sprintf("%s.%s.%s", database, schema, table)]
+}
+
+// ForColumns returns the tags set on each column of database.schema.table.
+func (o *ObjectTags) ForColumns(database, schema, table string) map[string]map[string]string {
+	if o == nil {
+		return nil
+	}
+	return o.Columns[fmt.Sprintf("%s.%s.%s", database, schema, table)]
+}
+
+func (o *ObjectTags) add(reference *TagReference) {
+	tag := reference.TagFullyQualifiedName()
+	set := func(objects map[string]map[string]string, key string) {
+		if objects[key] == nil {
+			objects[key] = map[string]string{}
+		}
+		objects[key][tag] = reference.TagValue
+	}
+
+	switch strings.ToUpper(reference.Domain) {
+	case TagDomainDatabase:
+		set(o.Databases, reference.ObjectName)
+	case TagDomainSchema:
+		set(o.Schemas, fmt.Sprintf("%s.%s", reference.ObjectDatabase, reference.ObjectName))
+	case TagDomainTable:
+		set(o.Tables, fmt.Sprintf("%s.%s.%s", reference.ObjectDatabase, reference.ObjectSchema, reference.ObjectName))
+	case TagDomainColumn:
+		if reference.ColumnName == "" {
+			return
+		}
+		table := fmt.Sprintf("%s.%s.%s", reference.ObjectDatabase, reference.ObjectSchema, reference.ObjectName)
+		if o.Columns[table] == nil {
+			o.Columns[table] = map[string]map[string]string{}
+		}
+		set(o.Columns[table], reference.ColumnName)
+	}
+}
+
+func (r *ListTagsRawResponse) GetTags() ([]Tag, error) {
+	var tags []Tag
+	for _, row := range r.Data {
+		tag := &Tag{}
+		if err := r.ResultSetMetadata.ParseRow(tag, row); err != nil {
+			return nil, err
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, nil
+}
+
+func (r *ListTagReferencesRawResponse) GetTagReferences() ([]TagReference, error) {
+	var references []TagReference
+	for _, row := range r.Data {
+		reference := &TagReference{}
+		if err := r.ResultSetMetadata.ParseRow(reference, row); err != nil {
+			return nil, err
+		}
+		references = append(references, *reference)
+	}
+	return references, nil
+}
+
+// ListTags enumerates every tag visible to the current role via SHOW TAGS IN ACCOUNT.
+func (c *Client) ListTags(ctx context.Context) ([]Tag, error) {
+	var response ListTagsRawResponse
+	_, err := c.runStatement(ctx, "list tags", "", &response, "SHOW TAGS IN ACCOUNT;")
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetTags()
+}
+
+// ListTagGrants runs SHOW GRANTS ON TAG. The rows have the same shape as SHOW GRANTS ON TABLE,
+// so they are returned as TableGrants.
+func (c *Client) ListTagGrants(ctx context.Context, tag *Tag) ([]TableGrant, error) {
+	var response ListTableGrantsRawResponse
+	_, err := c.runStatement(ctx, fmt.Sprintf("list grants on tag %s", tag.FullyQualifiedName()), "", &response,
+		fmt.Sprintf("SHOW GRANTS ON TAG %s;", quoteQualifiedName(tag.DatabaseName, tag.SchemaName, tag.Name)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetTableGrants()
+}
+
+// ListTagReferences reads the database, schema, table and column tag assignments of the whole
+// account from SNOWFLAKE.ACCOUNT_USAGE.TAG_REFERENCES. The view lags by up to two hours and
+// needs IMPORTED PRIVILEGES on the SNOWFLAKE database.
+func (c *Client) ListTagReferences(ctx context.Context) ([]TagReference, int, error) {
+	return c.listTagReferences(ctx, "list tag references",
+		fmt.Sprintf("DOMAIN IN ('%s', '%s', '%s', '%s')", TagDomainDatabase, TagDomainSchema, TagDomainTable, TagDomainColumn),
+	)
+}
+
+// listTagReferences reads the TAG_REFERENCES rows of live objects that match condition.
+func (c *Client) listTagReferences(ctx context.Context, action, condition string) ([]TagReference, int, error) {
+	var response ListTagReferencesRawResponse
+	statusCode, err := c.runStatement(ctx, action, "", &response,
+		"SELECT TAG_DATABASE, TAG_SCHEMA, TAG_NAME, TAG_VALUE, OBJECT_DATABASE, OBJECT_SCHEMA, OBJECT_NAME, COLUMN_NAME, DOMAIN "+
+			"FROM SNOWFLAKE.ACCOUNT_USAGE.TAG_REFERENCES WHERE OBJECT_DELETED IS NULL AND ("+condition+");",
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	references, err := response.GetTagReferences()
+	return references, statusCode, err
+}
+
+const objectTagsCacheKey = "account"
+
+// GetObjectTags collects the tags of every database, schema, table and column from one
+// TAG_REFERENCES query, cached for the rest of the sync. A role without access to
+// ACCOUNT_USAGE gets a 422 (a compilation error, since the view is not visible to it rather
+// than denied); that is reported as Unavailable instead of failing every database and table
+// page of the sync.
+func (c *Client) GetObjectTags(ctx context.Context, ss sessions.SessionStore) (*ObjectTags, error) {
+	if ss != nil {
+		if cached, found, err := session.GetJSON[*ObjectTags](ctx, ss, objectTagsCacheKey, objectTagsNamespace); err == nil && found {
+			return cached, nil
+		}
+	}
+
+	references, statusCode, err := c.ListTagReferences(ctx)
+	tags, err := newObjectTags(references, statusCode, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if ss != nil {
+		// Best-effort, like GetPolicyAttachments.
+		_ = session.SetJSON(ctx, ss, objectTagsCacheKey, tags, objectTagsNamespace)
+	}
+
+	return tags, nil
+}
+
+// GetDatabaseTags is GetObjectTags for one database: only the tags set on it, read live with
+// SYSTEM$GET_TAG rather than from the lagging TAG_REFERENCES view. It is for reading a single
+// database, so it is not cached.
+func (c *Client) GetDatabaseTags(ctx context.Context, database string) (*ObjectTags, error) {
+	tags, err := c.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selects := tagValueSelects(tags, TagDomainDatabase, "", "", database, quoteQualifiedName(database))
+	references, statusCode, err := c.getTagValues(ctx, fmt.Sprintf("get tags of database %s", database), selects)
+	return newObjectTags(references, statusCode, err)
+}
+
+// GetTableTags is GetObjectTags for one table: the tags set on it, its schema and its database,
+// read live with SYSTEM$GET_TAG, and those set on its columns, from
+// INFORMATION_SCHEMA.TAG_REFERENCES_ALL_COLUMNS. Neither lags like TAG_REFERENCES. It is for
+// reading a single table, so it is not cached.
+func (c *Client) GetTableTags(ctx context.Context, database, schema, table string) (*ObjectTags, error) {
+	tags, err := c.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var selects []string
+	selects = append(selects, tagValueSelects(tags, TagDomainDatabase, "", "", database, quoteQualifiedName(database))...)
+	selects = append(selects, tagValueSelects(tags, TagDomainSchema, database, "", schema, quoteQualifiedName(database, schema))...)
+	selects = append(selects, tagValueSelects(tags, TagDomainTable, database, schema, table, quoteQualifiedName(database, schema, table))...)
+	if len(tags) > 0 {
+		// TAG_REFERENCES_ALL_COLUMNS also reports the tags each column inherits from the table,
+		// schema and database; only those set on the column itself are kept.
+		selects = append(selects, fmt.Sprintf(
+			"SELECT TAG_DATABASE, TAG_SCHEMA, TAG_NAME, TAG_VALUE, OBJECT_DATABASE, OBJECT_SCHEMA, OBJECT_NAME, COLUMN_NAME, DOMAIN "+
+				"FROM TABLE(%s.INFORMATION_SCHEMA.TAG_REFERENCES_ALL_COLUMNS('%s', 'table')) WHERE LEVEL = '%s'",
+			quoteQualifiedName(database), escapeStringLiteral(quoteQualifiedName(database, schema, table)), TagDomainColumn,
+		))
+	}
+	references, statusCode, err := c.getTagValues(ctx, fmt.Sprintf("get tags of table %s.%s.%s", database, schema, table), selects)
+	return newObjectTags(references, statusCode, err)
+}
+
+// tagValueSelects builds, for each tag, a SELECT of SYSTEM$GET_TAG on one object shaped like a
+// TAG_REFERENCES row. objectDatabase, objectSchema and objectName fill the row's OBJECT_* columns
+// the way TAG_REFERENCES does for domain; identifier is the quoted name SYSTEM$GET_TAG looks up.
+func tagValueSelects(tags []Tag, domain, objectDatabase, objectSchema, objectName, identifier string) []string {
+	selects := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		selects = append(selects, fmt.Sprintf(
+			"SELECT '%s' AS TAG_DATABASE, '%s' AS TAG_SCHEMA, '%s' AS TAG_NAME, "+
+				"SYSTEM$GET_TAG('%s', '%s', '%s') AS TAG_VALUE, "+
+				"'%s' AS OBJECT_DATABASE, '%s' AS OBJECT_SCHEMA, '%s' AS OBJECT_NAME, NULL AS COLUMN_NAME, '%s' AS DOMAIN",
+			escapeStringLiteral(tag.DatabaseName), escapeStringLiteral(tag.SchemaName), escapeStringLiteral(tag.Name),
+			escapeStringLiteral(quoteQualifiedName(tag.DatabaseName, tag.SchemaName, tag.Name)), escapeStringLiteral(identifier), strings.ToLower(domain),
+			escapeStringLiteral(objectDatabase), escapeStringLiteral(objectSchema), escapeStringLiteral(objectName), domain,
+		))
+	}
+	return selects
+}
+
+// getTagValues runs selects as one UNION ALL statement and returns the rows that carry a value:
+// SYSTEM$GET_TAG returns NULL for a tag that is not set on the object.
+func (c *Client) getTagValues(ctx context.Context, action string, selects []string) ([]TagReference, int, error) {
+	if len(selects) == 0 {
+		return nil, 0, nil
+	}
+
+	var response ListTagReferencesRawResponse
+	statusCode, err := c.runStatement(ctx, action, "", &response,
+		"SELECT * FROM ("+strings.Join(selects, " UNION ALL ")+") WHERE TAG_VALUE IS NOT NULL;",
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	references, err := response.GetTagReferences()
+	return references, statusCode, err
+}
+
+// newObjectTags builds ObjectTags from TAG_REFERENCES rows, or rows shaped like them, reporting
+// a 422 as Unavailable as GetObjectTags describes.
+func newObjectTags(references []TagReference, statusCode int, err error) (*ObjectTags, error) {
+	tags := &ObjectTags{
+		Databases: map[string]map[string]string{},
+		Schemas:   map[string]map[string]string{},
+		Tables:    map[string]map[string]string{},
+		Columns:   map[string]map[string]map[string]string{},
+	}
+	if err != nil {
+		if !IsUnprocessableEntity(statusCode, err) {
+			return nil, err
+		}
+		tags.Unavailable = true
+	}
+	for i := range references {
+		tags.add(&references[i])
+	}
+	return tags, nil
+}
@@ -0,0 +1,144 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetObjectTags_KeysByObject(t *testing.T) {
+	server := serveRows(t,
+		[]string{"TAG_DATABASE", "TAG_SCHEMA", "TAG_NAME", "TAG_VALUE", "OBJECT_DATABASE", "OBJECT_SCHEMA", "OBJECT_NAME", "COLUMN_NAME", "DOMAIN"},
+		[][]string{
+			{"GOV", "TAGS", "SENSITIVITY", "RESTRICTED", "", "", "FINANCE", "", TagDomainDatabase},
+			{"GOV", "TAGS", "SENSITIVITY", "INTERNAL", "FINANCE", "", "REPORTING", "", TagDomainSchema},
+			{"GOV", "TAGS", "OWNER_TEAM", "LEDGER", "FINANCE", "PUBLIC", "GL", "", TagDomainTable},
+			{"GOV", "TAGS", "PII", "TRUE", "FINANCE", "PUBLIC", "CUSTOMERS", "SSN", TagDomainColumn},
+		},
+	)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	tags, err := client.GetObjectTags(context.Background(), nil)
+	require.NoError(t, err)
+	assert.False(t, tags.Unavailable)
+	assert.Equal(t, map[string]string{"GOV.TAGS.SENSITIVITY": "RESTRICTED"}, tags.ForDatabase("FINANCE"))
+	assert.Equal(t, map[string]string{"GOV.TAGS.SENSITIVITY": "INTERNAL"}, tags.ForSchema("FINANCE", "REPORTING"))
+	assert.Equal(t, map[string]string{"GOV.TAGS.OWNER_TEAM": "LEDGER"}, tags.ForTable("FINANCE", "PUBLIC", "GL"))
+	assert.Equal(t,
+		map[string]map[string]string{"SSN": {"GOV.TAGS.PII": "TRUE"}},
+		tags.ForColumns("FINANCE", "PUBLIC", "CUSTOMERS"),
+	)
+}
+
+// TestGetObjectTags_UnavailableWithoutAccountUsage checks a role that cannot see ACCOUNT_USAGE
+// gets an empty, Unavailable result rather than an error that would fail the sync.
+func TestGetObjectTags_UnavailableWithoutAccountUsage(t *testing.T) {
+	server := new422Server(t, "002003", "SQL compilation error:\nDatabase 'SNOWFLAKE' does not exist or not authorized.")
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	tags, err := client.GetObjectTags(context.Background(), nil)
+	require.NoError(t, err)
+	assert.True(t, tags.Unavailable)
+	assert.Nil(t, tags.ForDatabase("FINANCE"))
+}
+
+func TestTag_AllowedValueList(t *testing.T) {
+	assert.Equal(t, []string{"PUBLIC", "RESTRICTED"}, (&Tag{AllowedValues: `["PUBLIC","RESTRICTED"]`}).AllowedValueList())
+	assert.Nil(t, (&Tag{AllowedValues: rowNull}).AllowedValueList())
+	assert.Nil(t, (&Tag{}).AllowedValueList())
+}
+
+// serveTagValues answers SHOW TAGS with one tag, GOV.TAGS.PII, and any other statement with
+// values, recording that statement in capturedSQL.
+func serveTagValues(t *testing.T, values [][]string, capturedSQL *string) *httptest.Server {
+	t.Helper()
+	rowType := func(columns ...string) []map[string]interface{} {
+		var rowType []map[string]interface{}
+		for _, column := range columns {
+			rowType = append(rowType, map[string]interface{}{"name": column, "type": rowTypeString})
+		}
+		return rowType
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+
+		switch r.Method {
+		case http.MethodPost:
+			var req StatementsApiRequestBody
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			handle := "tags"
+			if !strings.HasPrefix(req.Statement, "SHOW TAGS") {
+				handle = "values"
+				*capturedSQL = req.Statement
+			}
+			_ = enc.Encode(map[string]interface{}{"statementHandle": handle})
+		case http.MethodGet:
+			if strings.HasSuffix(r.URL.Path, "/tags") {
+				_ = enc.Encode(map[string]interface{}{
+					"statementHandle": "tags",
+					"resultSetMetadata": map[string]interface{}{
+						"numRows": 1,
+						"rowType": append(
+							[]map[string]interface{}{{"name": columnCreatedOn, "type": rowTypeTimestampLtz}},
+							rowType(columnName, columnDatabaseName, columnSchemaName, columnOwner, columnComment, "allowed_values")...,
+						),
+					},
+					"data": [][]string{{"", "PII", "GOV", "TAGS", "GOVERNANCE", "", ""}},
+				})
+				return
+			}
+			_ = enc.Encode(map[string]interface{}{
+				"statementHandle": "values",
+				"resultSetMetadata": map[string]interface{}{
+					"numRows": len(values),
+					"rowType": rowType("TAG_DATABASE", "TAG_SCHEMA", "TAG_NAME", "TAG_VALUE", "OBJECT_DATABASE", "OBJECT_SCHEMA", "OBJECT_NAME", "COLUMN_NAME", "DOMAIN"),
+				},
+				"data": values,
+			})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+// TestGetTableTags_ReadsTheTableLive checks a single table's tags come from SYSTEM$GET_TAG and
+// TAG_REFERENCES_ALL_COLUMNS, which are current, rather than the lagging TAG_REFERENCES view.
+func TestGetTableTags_ReadsTheTableLive(t *testing.T) {
+	var capturedSQL string
+	server := serveTagValues(t, [][]string{
+		{"GOV", "TAGS", "PII", "LOW", "", "", "FINANCE", "", TagDomainDatabase},
+		{"GOV", "TAGS", "PII", "HIGH", "FINANCE", "PUBLIC", "O'NEIL", "SSN", TagDomainColumn},
+	}, &capturedSQL)
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	tags, err := client.GetTableTags(context.Background(), "FINANCE", "PUBLIC", "O'NEIL")
+	require.NoError(t, err)
+	assert.NotContains(t, capturedSQL, "ACCOUNT_USAGE")
+	assert.Contains(t, capturedSQL, `SYSTEM$GET_TAG('"GOV"."TAGS"."PII"', '"FINANCE"', 'database')`)
+	assert.Contains(t, capturedSQL, `SYSTEM$GET_TAG('"GOV"."TAGS"."PII"', '"FINANCE"."PUBLIC"', 'schema')`)
+	assert.Contains(t, capturedSQL, `SYSTEM$GET_TAG('"GOV"."TAGS"."PII"', '"FINANCE"."PUBLIC"."O''NEIL"', 'table')`)
+	assert.Contains(t, capturedSQL, `TABLE("FINANCE".INFORMATION_SCHEMA.TAG_REFERENCES_ALL_COLUMNS('"FINANCE"."PUBLIC"."O''NEIL"', 'table'))`)
+	assert.Equal(t, map[string]string{"GOV.TAGS.PII": "LOW"}, tags.ForDatabase("FINANCE"))
+	assert.Equal(t,
+		map[string]map[string]string{"SSN": {"GOV.TAGS.PII": "HIGH"}},
+		tags.ForColumns("FINANCE", "PUBLIC", "O'NEIL"),
+	)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -38,6 +39,19 @@ var (
 		"HasRSAPublicKey",
 		"HasPassword",
 		"LastSuccessLogin", // May not be present for newly created users
+		"MfaMethods",       // From SHOW MFA METHODS FOR USER
+	}
+
+	// Properties only DESCRIBE USER returns. GetUser treats them as optional.
+	userDescribeOnlyStructFieldToColumnMap = map[string]string{
+		"NetworkPolicy": "network_policy",
+	}
+
+	// SHOW USERS lacks the describe-only properties and the MFA methods; ParseRow leaves them
+	// empty.
+	userListUnparsedStructFields = []string{
+		"NetworkPolicy",
+		"MfaMethods", // From SHOW MFA METHODS FOR USER
 	}
 
 	secretStructFieldToColumnMap = map[string]string{
@@ -77,12 +91,21 @@ type (
 		Type             string
 		HasMfa           bool
 		Comment          string
+		NetworkPolicy    string
+		// MfaMethods comes from SHOW MFA METHODS FOR USER, not from SHOW or DESCRIBE USER.
+		MfaMethods []MfaMethod
 	}
 
+	// UserRsa is the key-pair authentication state of a user from DESCRIBE USER. The public keys
+	// are the base64 DER bodies as set; fingerprints are Snowflake's SHA256:<base64> digests.
 	UserRsa struct {
 		Username                 string
 		RsaPublicKeyLastSetTime  *time.Time
 		RsaPublicKeyLastSetTime2 *time.Time
+		RsaPublicKey             string
+		RsaPublicKey2            string
+		RsaPublicKeyFingerprint  string
+		RsaPublicKeyFingerprint2 string
 	}
 
 	UserDescriptionProperty struct {
@@ -129,6 +152,12 @@ func (u *User) GetColumnName(fieldName string) string {
 	return userStructFieldToColumnMap[fieldName]
 }
 
+// unparsedFields are the User fields SHOW USERS does not return: the DESCRIBE USER-only
+// properties and the MFA methods.
+func (u *User) unparsedFields() []string {
+	return userListUnparsedStructFields
+}
+
 func (u *UserDescriptionProperty) GetColumnName(fieldName string) string {
 	return userDescriptionStructFieldToColumnMap[fieldName]
 }
@@ -156,6 +185,12 @@ func (r *GetUserRawResponse) GetUser() (*User, error) {
 		if Contains(ignoredUserStructFieldsForDescribeOperation, field.Name) {
 			continue
 		}
+		if describeColumn, ok := userDescribeOnlyStructFieldToColumnMap[field.Name]; ok {
+			if value, found := r.GetValueByColumnName(strings.ToUpper(describeColumn)); found && value != rowNull {
+				reflected.Field(i).SetString(value)
+			}
+			continue
+		}
 		columnName := strings.ToUpper(user.GetColumnName(field.Name))
 
 		value, found := r.GetValueByColumnName(columnName)
@@ -224,7 +259,32 @@ func (c *Client) ListUsers(ctx context.Context, cursor string, limit int) ([]Use
 	return users, nil
 }
 
-// SHOW USERS returns a superset of DESCRIBE USER fields, so cached entries are safe to reuse for GetUser.
+// FindUser returns the user as ListUsers lists it, from SHOW USERS LIKE, or nil when there is no
+// user with the exact name. As with GetAccountRole, _ and % in the name are wildcards, so every
+// row is scanned for the exact match.
+func (c *Client) FindUser(ctx context.Context, username string) (*User, int, error) {
+	var response ListUsersRawResponse
+	statusCode, err := c.runStatement(ctx, fmt.Sprintf("find user %s", username), "", &response,
+		fmt.Sprintf("SHOW USERS LIKE '%s' LIMIT %d;", escapeLikeStringLiteral(username), wildcardLookupLimit),
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	users, err := response.GetUsers()
+	if err != nil {
+		return nil, statusCode, err
+	}
+	for i := range users {
+		if users[i].Username == username {
+			return &users[i], statusCode, nil
+		}
+	}
+	return nil, statusCode, nil
+}
+
+// SHOW USERS returns a superset of DESCRIBE USER fields apart from the describe-only ones
+// (NetworkPolicy), so callers that need those must fill them in before caching.
 func (c *Client) CacheUsers(ctx context.Context, ss sessions.SessionStore, users []User) error {
 	if ss == nil || len(users) == 0 {
 		return nil
@@ -311,6 +371,138 @@ func (c *Client) SetUserDisabled(ctx context.Context, userName string, disabled
 	return nil
 }
 
+// SetUserNetworkPolicy attaches a network policy to a user, replacing any policy already set.
+// Unlike SetUserDisabled it runs under the connector user's default role, since attaching a
+// policy takes privileges USERADMIN does not hold: OWNERSHIP on the network policy, or ATTACH
+// POLICY on the account, besides OWNERSHIP on the user.
+func (c *Client) SetUserNetworkPolicy(ctx context.Context, userName, policyName string) error {
+	return c.execStatement(ctx, fmt.Sprintf("set network policy %s on user %s", policyName, userName), "",
+		fmt.Sprintf("ALTER USER \"%s\" SET NETWORK_POLICY = \"%s\";",
+			escapeDoubleQuotedIdentifier(userName), escapeDoubleQuotedIdentifier(policyName)),
+	)
+}
+
+// SetUserPolicy attaches an authentication, password or session policy to a user. FORCE
+// replaces a policy of the same kind already attached instead of failing. Like
+// SetUserNetworkPolicy it runs under the default role, which needs APPLY <kind> POLICY on the
+// account, or APPLY on the policy and OWNERSHIP on the user.
+func (c *Client) SetUserPolicy(ctx context.Context, userName string, policy *Policy) error {
+	keyword, ok := policyShowKeywords[policy.Kind]
+	if !ok {
+		return fmt.Errorf("baton-snowflake: unsupported policy kind %s", policy.Kind)
+	}
+
+	return c.execStatement(ctx, fmt.Sprintf("set %s policy %s on user %s", strings.ToLower(keyword), policy.FullyQualifiedName(), userName), "",
+		fmt.Sprintf("ALTER USER \"%s\" SET %s POLICY %s FORCE;",
+			escapeDoubleQuotedIdentifier(userName), keyword, quoteQualifiedName(policy.DatabaseName, policy.SchemaName, policy.Name)),
+	)
+}
+
+// UnsetUserPolicy detaches the user's policy of the given kind (PolicyKindNetwork or a
+// schema-level policy kind). Unsetting a policy that is not set succeeds. It runs under the
+// default role and needs the privileges attaching the policy does.
+func (c *Client) UnsetUserPolicy(ctx context.Context, userName, kind string) error {
+	var property string
+	if kind == PolicyKindNetwork {
+		property = "NETWORK_POLICY"
+	} else {
+		keyword, ok := policyShowKeywords[kind]
+		if !ok {
+			return fmt.Errorf("baton-snowflake: unsupported policy kind %s", kind)
+		}
+		property = keyword + " POLICY"
+	}
+
+	return c.execStatement(ctx, fmt.Sprintf("unset %s on user %s", strings.ToLower(property), userName), "",
+		fmt.Sprintf("ALTER USER \"%s\" UNSET %s;", escapeDoubleQuotedIdentifier(userName), property),
+	)
+}
+
+// SetUserPassword replaces a user's password and requires them to change it at next login. The
+// password is bound rather than written into the statement, so it stays out of the statement
+// text, query history and error messages. It runs as UserAdminRole.
+func (c *Client) SetUserPassword(ctx context.Context, userName, password string) error {
+	return c.execBoundStatement(ctx, fmt.Sprintf("reset password of user %s", userName), UserAdminRole,
+		fmt.Sprintf("ALTER USER \"%s\" SET PASSWORD = ? MUST_CHANGE_PASSWORD = TRUE;", escapeDoubleQuotedIdentifier(userName)),
+		password,
+	)
+}
+
+// RequireUserPasswordChange makes a user choose a new password at next login. It runs as
+// UserAdminRole.
+func (c *Client) RequireUserPasswordChange(ctx context.Context, userName string) error {
+	return c.execStatement(ctx, fmt.Sprintf("require password change for user %s", userName), UserAdminRole,
+		fmt.Sprintf("ALTER USER \"%s\" SET MUST_CHANGE_PASSWORD = TRUE;", escapeDoubleQuotedIdentifier(userName)),
+	)
+}
+
+// UnlockUser clears a lockout caused by failed login attempts (SNOWFLAKE_LOCK). It runs as
+// UserAdminRole.
+func (c *Client) UnlockUser(ctx context.Context, userName string) error {
+	return c.execStatement(ctx, fmt.Sprintf("unlock user %s", userName), UserAdminRole,
+		fmt.Sprintf("ALTER USER \"%s\" SET MINS_TO_UNLOCK = 0;", escapeDoubleQuotedIdentifier(userName)),
+	)
+}
+
+// AbortUserQueries aborts every query the user has running or queued. It runs as UserAdminRole.
+func (c *Client) AbortUserQueries(ctx context.Context, userName string) error {
+	return c.execStatement(ctx, fmt.Sprintf("abort queries of user %s", userName), UserAdminRole,
+		fmt.Sprintf("ALTER USER \"%s\" ABORT ALL QUERIES;", escapeDoubleQuotedIdentifier(userName)),
+	)
+}
+
+// updatableUserProperties are the properties UpdateUser sets. DEFAULT_SECONDARY_ROLES takes ALL
+// or NONE and is written as a role list; the others are written as string literals.
+var updatableUserProperties = []string{
+	"EMAIL",
+	"DISPLAY_NAME",
+	"FIRST_NAME",
+	"LAST_NAME",
+	"COMMENT",
+	"DEFAULT_ROLE",
+	"DEFAULT_WAREHOUSE",
+	"DEFAULT_NAMESPACE",
+	"DEFAULT_SECONDARY_ROLES",
+}
+
+// UpdateUser sets several user properties, keyed by property name, in one ALTER USER ... SET.
+// Like SetUserDisabled it runs as UserAdminRole.
+func (c *Client) UpdateUser(ctx context.Context, userName string, properties map[string]string) error {
+	if len(properties) == 0 {
+		return fmt.Errorf("baton-snowflake: no properties to update on user %s", userName)
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		if !Contains(updatableUserProperties, name) {
+			return fmt.Errorf("baton-snowflake: user property %s cannot be updated", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	assignments := make([]string, 0, len(names))
+	for _, name := range names {
+		value := properties[name]
+		if name != "DEFAULT_SECONDARY_ROLES" {
+			assignments = append(assignments, fmt.Sprintf("%s = '%s'", name, escapeStringLiteral(value)))
+			continue
+		}
+		switch strings.ToUpper(value) {
+		case "ALL":
+			assignments = append(assignments, "DEFAULT_SECONDARY_ROLES = ('ALL')")
+		case "NONE":
+			assignments = append(assignments, "DEFAULT_SECONDARY_ROLES = ()")
+		default:
+			return fmt.Errorf("baton-snowflake: DEFAULT_SECONDARY_ROLES must be ALL or NONE, got %q", value)
+		}
+	}
+
+	return c.execStatement(ctx, fmt.Sprintf("update user %s", userName), UserAdminRole,
+		fmt.Sprintf("ALTER USER \"%s\" SET %s;", escapeDoubleQuotedIdentifier(userName), strings.Join(assignments, " ")),
+	)
+}
+
 func (r *ListSecretsRawResponse) ListSecrets() ([]Secret, error) {
 	var secrets []Secret
 	for _, row := range r.Data {
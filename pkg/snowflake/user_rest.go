@@ -29,8 +29,20 @@ type CreateUserRequest struct {
 	DefaultNamespace      string `json:"default_namespace,omitempty"`
 	DefaultRole           string `json:"default_role,omitempty"`
 	DefaultSecondaryRoles string `json:"default_secondary_roles,omitempty"` // ALL or NONE
+	Type                  string `json:"type,omitempty"`                    // PERSON, SERVICE or LEGACY_SERVICE
+	RsaPublicKey          string `json:"rsa_public_key,omitempty"`
 }
 
+// User TYPE values. CreateUserRequest.Type accepts PERSON, SERVICE and LEGACY_SERVICE; SERVICE
+// users cannot have a password.
+// https://docs.snowflake.com/en/sql-reference/sql/create-user#label-user-type-property
+const (
+	UserTypePerson        = "PERSON"
+	UserTypeService       = "SERVICE"
+	UserTypeServiceAgent  = "SERVICE_AGENT"
+	UserTypeLegacyService = "LEGACY_SERVICE"
+)
+
 // CreateUserResponse represents the response from creating a user.
 type CreateUserResponse struct {
 	Status  string `json:"status,omitempty"`
@@ -2,7 +2,9 @@ package snowflake
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -73,3 +75,63 @@ func TestSetUserDisabled_RendersBooleanValue(t *testing.T) {
 		})
 	}
 }
+
+// TestGetUsers_LeavesUnparsedFieldsEmpty verifies that SHOW USERS rows parse although they lack
+// the describe-only and MFA method fields of User.
+func TestGetUsers_LeavesUnparsedFieldsEmpty(t *testing.T) {
+	columns := []string{"name", "login_name", "display_name", "first_name", "last_name", "email", "disabled", "snowflake_lock",
+		"default_role", "has_rsa_public_key", "has_password", "last_success_login", "type", "has_mfa", "comment"}
+	response := ListUsersRawResponse{}
+	for _, column := range columns {
+		rowType := RowType{Name: column, Type: rowTypeString}
+		if column == "last_success_login" {
+			rowType.Type = rowTypeTimestampLtz
+		}
+		response.ResultSetMetadata.RowTypes = append(response.ResultSetMetadata.RowTypes, rowType)
+	}
+	response.Data = [][]string{{"ALICE", "alice", "Alice", "", "", "", "false", "false", "PUBLIC", "false", "true", "", "PERSON", "false", ""}}
+
+	users, err := response.GetUsers()
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "ALICE", users[0].Username)
+	assert.Empty(t, users[0].NetworkPolicy)
+	assert.Empty(t, users[0].MfaMethods)
+}
+
+type strictRow struct {
+	Name  string
+	Extra string
+}
+
+func (r *strictRow) GetColumnName(fieldName string) string {
+	return map[string]string{"Name": columnName}[fieldName]
+}
+
+// TestParseRow_FailsOnUnmappedField verifies that ParseRow only skips fields a type declares
+// unparsed.
+func TestParseRow_FailsOnUnmappedField(t *testing.T) {
+	m := ResultSetMetadata{RowTypes: []RowType{{Name: columnName, Type: rowTypeString}}}
+	err := m.ParseRow(&strictRow{}, []string{"ALICE"})
+	require.Error(t, err)
+}
+
+func TestSetUserPassword_BindsPassword(t *testing.T) {
+	const password = `pa'ss"word`
+
+	var body StatementsApiRequestBody
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"statementHandle": "handle"})
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, JWTConfig{}, &http.Client{})
+	require.NoError(t, err)
+
+	require.NoError(t, client.SetUserPassword(context.Background(), "jdoe", password))
+	assert.Equal(t, `ALTER USER "jdoe" SET PASSWORD = ? MUST_CHANGE_PASSWORD = TRUE;`, body.Statement)
+	assert.Equal(t, map[string]QueryParameter{"1": {Type: "TEXT", Value: password}}, body.Bindings)
+	assert.Equal(t, UserAdminRole, body.Role)
+}